@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// client calls the admin APIs of the services with a token of an admin or moderator
+type client struct {
+	http  *http.Client
+	token string
+
+	authURL string
+	userURL string
+	gameURL string
+	chatURL string
+}
+
+// call sends a request and prints the payload of the response
+func (c *client) call(method, baseURL, path string, query url.Values, body interface{}) error {
+	payload, err := c.request(method, baseURL, path, query, body)
+	if err != nil || len(payload) == 0 || string(payload) == "null" {
+		return err
+	}
+	return printJSON(payload)
+}
+
+// request sends a request with an optional JSON body and returns the payload of the response:
+// the data of responses wrapped in utils.Response, the whole body of others
+func (c *client) request(method, baseURL, path string, query url.Values, body interface{}) (json.RawMessage, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	target := baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Success *bool           `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+		Error   string          `json:"error"`
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+
+	if resp.StatusCode >= 300 {
+		if response.Error == "" {
+			response.Error = resp.Status
+		}
+		if len(response.Data) > 0 && string(response.Data) != "null" {
+			return nil, fmt.Errorf("%s: %s", response.Error, response.Data)
+		}
+		return nil, fmt.Errorf("%s", response.Error)
+	}
+
+	if response.Message != "" {
+		fmt.Fprintln(os.Stderr, response.Message)
+	}
+	if response.Success != nil {
+		return response.Data, nil
+	}
+	return raw, nil
+}
+
+// printJSON prints raw JSON indented to stdout
+func printJSON(raw json.RawMessage) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(os.Stdout)
+	return err
+}
@@ -0,0 +1,3 @@
+module ttt-admin
+
+go 1.21
@@ -0,0 +1,307 @@
+// Command ttt-admin operates the platform through the admin APIs of the services:
+// it searches and bans users, ends and deletes games, inspects the live rooms
+// of the chat service and broadcasts announcements.
+//
+// Requests are authorized with the token of an admin or moderator, taken from
+// -token or TTT_ADMIN_TOKEN. The login command prints one, which expires after
+// 15 minutes, with the refresh token of the session in TTT_ADMIN_REFRESH_TOKEN.
+// The refresh command replaces both until the session expires:
+//
+//	eval "$(TTT_ADMIN_PASSWORD=... ttt-admin login admin@example.com)"
+//	eval "$(ttt-admin refresh)"
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// command is a subcommand of the CLI
+type command struct {
+	usage string
+	run   func(c *client, args []string) error
+}
+
+var commands = map[string]command{
+	"login":       {"login <email>  print a token and a refresh token, the password and two-factor code are read from TTT_ADMIN_PASSWORD and TTT_ADMIN_OTP or stdin", login},
+	"refresh":     {"refresh  print a new token with the current roles in exchange for the refresh token in TTT_ADMIN_REFRESH_TOKEN", refresh},
+	"users":       {"users [-offset n] [-limit n] [query]  search users by email or username", listUsers},
+	"user":        {"user <id>  show a user with their roles and ban", showUser},
+	"roles":       {"roles <id> [role...]  replace the roles of a user", setRoles},
+	"ban":         {"ban <id> [reason]  keep a user from logging in", ban},
+	"unban":       {"unban <id>  lift the ban of a user", unban},
+	"end-game":    {"end-game <id>  finish an active game without a winner", endGame},
+	"delete-game": {"delete-game <id>  delete a game and its history", deleteGame},
+	"rooms":       {"rooms  list the games with connected clients", rooms},
+	"announce":    {"announce <message>  send a system announcement to every connected user", announce},
+	"reports":     {"reports [-offset n] [-limit n]  list reports of users, newest first", reports},
+}
+
+func main() {
+	token := flag.String("token", os.Getenv("TTT_ADMIN_TOKEN"), "token of an admin or moderator")
+	authURL := flag.String("auth-service", envOr("AUTH_SERVICE_URL", "http://localhost:8081"), "base URL of the auth service")
+	userURL := flag.String("user-service", envOr("USER_SERVICE_URL", "http://localhost:8082"), "base URL of the user service")
+	gameURL := flag.String("game-service", envOr("GAME_SERVICE_URL", "http://localhost:8083"), "base URL of the game service")
+	chatURL := flag.String("chat-service", envOr("CHAT_SERVICE_URL", "http://localhost:8084"), "base URL of the chat service")
+	flag.Usage = usage
+	flag.Parse()
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	c := &client{
+		http:    &http.Client{Timeout: 30 * time.Second},
+		token:   *token,
+		authURL: strings.TrimRight(*authURL, "/"),
+		userURL: strings.TrimRight(*userURL, "/"),
+		gameURL: strings.TrimRight(*gameURL, "/"),
+		chatURL: strings.TrimRight(*chatURL, "/"),
+	}
+	if err := cmd.run(c, flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "ttt-admin %s: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the flags and commands
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: ttt-admin [flags] <command> [arguments]\n\nFlags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(flag.CommandLine.Output(), "\nCommands:\n")
+	for _, name := range []string{"login", "refresh", "users", "user", "roles", "ban", "unban", "end-game", "delete-game", "rooms", "announce", "reports"} {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s\n", commands[name].usage)
+	}
+}
+
+// envOr returns the environment variable key, or fallback when it is not set
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func login(c *client, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: login <email>")
+	}
+
+	password, err := prompt("Password", "TTT_ADMIN_PASSWORD")
+	if err != nil {
+		return err
+	}
+
+	payload, err := c.request(http.MethodPost, c.authURL, "/login", nil, map[string]string{
+		"email":    args[0],
+		"password": password,
+	})
+	if err != nil {
+		return err
+	}
+
+	var issued struct {
+		issuedTokens
+		ChallengeToken string `json:"challenge_token"`
+	}
+	if err := json.Unmarshal(payload, &issued); err != nil {
+		return err
+	}
+
+	if issued.ChallengeToken != "" {
+		code, err := prompt("Two-factor code", "TTT_ADMIN_OTP")
+		if err != nil {
+			return err
+		}
+		payload, err = c.request(http.MethodPost, c.authURL, "/login/2fa", nil, map[string]string{
+			"challenge_token": issued.ChallengeToken,
+			"code":            code,
+		})
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(payload, &issued); err != nil {
+			return err
+		}
+	}
+	issued.print()
+	return nil
+}
+
+func refresh(c *client, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: refresh")
+	}
+
+	refreshToken := os.Getenv("TTT_ADMIN_REFRESH_TOKEN")
+	if refreshToken == "" {
+		return errors.New("TTT_ADMIN_REFRESH_TOKEN is not set, log in again")
+	}
+
+	payload, err := c.request(http.MethodPost, c.authURL, "/token/refresh", nil, map[string]string{
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	var issued issuedTokens
+	if err := json.Unmarshal(payload, &issued); err != nil {
+		return err
+	}
+	issued.print()
+	return nil
+}
+
+// issuedTokens - tokens of a login or refresh
+type issuedTokens struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// print writes the tokens as shell assignments for eval
+func (t issuedTokens) print() {
+	fmt.Printf("export TTT_ADMIN_TOKEN=%s\n", t.Token)
+	fmt.Printf("export TTT_ADMIN_REFRESH_TOKEN=%s\n", t.RefreshToken)
+}
+
+// stdin is shared by prompts, as a reader of its own could buffer the input of the next one
+var stdin = bufio.NewReader(os.Stdin)
+
+// prompt returns the value of the environment variable key, or reads a line from stdin when it is not set
+func prompt(label, key string) (string, error) {
+	if value := os.Getenv(key); value != "" {
+		return value, nil
+	}
+	fmt.Fprintf(os.Stderr, "%s: ", label)
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func listUsers(c *client, args []string) error {
+	query, args, err := page("users", args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return errors.New("usage: users [-offset n] [-limit n] [query]")
+	}
+	if len(args) == 1 {
+		query.Set("q", args[0])
+	}
+	return c.call(http.MethodGet, c.authURL, "/admin/users", query, nil)
+}
+
+func showUser(c *client, args []string) error {
+	id, err := singleID("user <id>", args)
+	if err != nil {
+		return err
+	}
+	return c.call(http.MethodGet, c.authURL, "/admin/users/"+id, nil, nil)
+}
+
+func setRoles(c *client, args []string) error {
+	if len(args) < 1 {
+		return errors.New("usage: roles <id> [role...]")
+	}
+	return c.call(http.MethodPut, c.authURL, "/admin/users/"+url.PathEscape(args[0])+"/roles", nil, map[string][]string{
+		"roles": append([]string{}, args[1:]...),
+	})
+}
+
+func ban(c *client, args []string) error {
+	if len(args) < 1 {
+		return errors.New("usage: ban <id> [reason]")
+	}
+	return c.call(http.MethodPost, c.authURL, "/admin/users/"+url.PathEscape(args[0])+"/ban", nil, map[string]string{
+		"reason": strings.Join(args[1:], " "),
+	})
+}
+
+func unban(c *client, args []string) error {
+	id, err := singleID("unban <id>", args)
+	if err != nil {
+		return err
+	}
+	return c.call(http.MethodDelete, c.authURL, "/admin/users/"+id+"/ban", nil, nil)
+}
+
+func endGame(c *client, args []string) error {
+	id, err := singleID("end-game <id>", args)
+	if err != nil {
+		return err
+	}
+	return c.call(http.MethodPost, c.gameURL, "/games/"+id+"/end", nil, nil)
+}
+
+func deleteGame(c *client, args []string) error {
+	id, err := singleID("delete-game <id>", args)
+	if err != nil {
+		return err
+	}
+	return c.call(http.MethodDelete, c.gameURL, "/games/"+id, nil, nil)
+}
+
+func rooms(c *client, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: rooms")
+	}
+	return c.call(http.MethodGet, c.chatURL, "/admin/rooms", nil, nil)
+}
+
+func announce(c *client, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: announce <message>")
+	}
+	return c.call(http.MethodPost, c.chatURL, "/admin/announcements", nil, map[string]string{
+		"content": strings.Join(args, " "),
+	})
+}
+
+func reports(c *client, args []string) error {
+	query, args, err := page("reports", args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errors.New("usage: reports [-offset n] [-limit n]")
+	}
+	return c.call(http.MethodGet, c.userURL, "/admin/reports", query, nil)
+}
+
+// page parses the -offset and -limit flags of a listing command
+// and returns them as query parameters with the remaining arguments
+func page(name string, args []string) (url.Values, []string, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	offset := flags.Int("offset", 0, "number of entries to skip")
+	limit := flags.Int("limit", 50, "number of entries to list")
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	query := url.Values{}
+	query.Set("offset", fmt.Sprint(*offset))
+	query.Set("limit", fmt.Sprint(*limit))
+	return query, flags.Args(), nil
+}
+
+// singleID returns the only argument of a command, escaped for use in a path
+func singleID(usage string, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("usage: " + usage)
+	}
+	return url.PathEscape(args[0]), nil
+}
@@ -3,12 +3,18 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	JWT      JWTConfig
+	Service  ServiceConfig
+	Outbox   OutboxConfig
+	Storage  StorageConfig
+	Admin    AdminConfig
+	Mail     MailConfig
 }
 
 type ServerConfig struct {
@@ -23,12 +29,51 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// InMemory keeps data in process memory instead of the database, for development only (STORAGE=memory)
+	InMemory bool
 }
 
 type JWTConfig struct {
 	Secret string
 }
 
+// ServiceConfig holds the secret shared by the services to call each other's internal routes
+type ServiceConfig struct {
+	Secret string
+}
+
+type OutboxConfig struct {
+	WebhookURLs []string
+	// WebhookSecrets sign the envelopes posted to the webhook URLs, one per URL in the same order
+	WebhookSecrets []string
+	NotifyChannel  string
+	MaxAttempts    int
+	// SubscriberSecret checks the signatures of envelopes posted to this service by other outboxes
+	SubscriberSecret string
+}
+
+type StorageConfig struct {
+	Dir     string
+	BaseURL string
+}
+
+type AdminConfig struct {
+	Emails []string
+}
+
+// MailConfig selects how emails are sent: over SMTP when SMTPHost is set,
+// otherwise appended to File, or logged when File is empty too
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	File         string
+	// AppURL is the address of the frontend the links in emails point to
+	AppURL string
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -42,10 +87,37 @@ func Load() *Config {
 			Password: getEnv("DB_PASSWORD", "password"),
 			Name:     getEnv("DB_NAME", "app"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			InMemory: getEnv("STORAGE", "") == "memory",
 		},
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", "your-secret-key"),
 		},
+		Service: ServiceConfig{
+			Secret: getEnv("SERVICE_SECRET", ""),
+		},
+		Outbox: OutboxConfig{
+			WebhookURLs:      getEnvAsSlice("OUTBOX_WEBHOOK_URLS"),
+			WebhookSecrets:   getEnvAsSlice("OUTBOX_WEBHOOK_SECRETS"),
+			NotifyChannel:    getEnv("OUTBOX_NOTIFY_CHANNEL", ""),
+			MaxAttempts:      getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10),
+			SubscriberSecret: getEnv("OUTBOX_SUBSCRIBER_SECRET", ""),
+		},
+		Storage: StorageConfig{
+			Dir:     getEnv("STORAGE_DIR", "./data/blobs"),
+			BaseURL: getEnv("STORAGE_BASE_URL", "/media"),
+		},
+		Admin: AdminConfig{
+			Emails: getEnvAsSlice("ADMIN_EMAILS"),
+		},
+		Mail: MailConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("MAIL_FROM", "no-reply@localhost"),
+			File:         getEnv("MAIL_FILE", ""),
+			AppURL:       getEnv("APP_URL", "http://localhost:3000"),
+		},
 	}
 }
 
@@ -63,4 +135,14 @@ func getEnvAsInt(key string, defaultValue int) int {
 		}
 	}
 	return defaultValue
-} 
\ No newline at end of file
+}
+
+func getEnvAsSlice(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
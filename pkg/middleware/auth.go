@@ -8,8 +8,11 @@ import (
 )
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID      string   `json:"user_id"`
+	Email       string   `json:"email"`
+	Username    string   `json:"username,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"` // granted by the roles when the token was issued
 	jwt.RegisteredClaims
 }
 
@@ -29,27 +32,49 @@ func Auth(jwtSecret string) fiber.Handler {
 			})
 		}
 
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
+		return authenticate(c, tokenString, jwtSecret)
+	}
+}
 
-		if err != nil || !token.Valid {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid token",
-			})
-		}
+// WebSocketAuth authenticates like Auth, but also takes the token from the token query parameter,
+// as browsers cannot set headers on WebSocket connections
+func WebSocketAuth(jwtSecret string) fiber.Handler {
+	auth := Auth(jwtSecret)
 
-		claims, ok := token.Claims.(*Claims)
-		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid token claims",
-			})
+	return func(c *fiber.Ctx) error {
+		tokenString := c.Query("token")
+		if tokenString == "" {
+			return auth(c)
 		}
+		return authenticate(c, tokenString, jwtSecret)
+	}
+}
 
-		// Store user info in context
-		c.Locals("user_id", claims.UserID)
-		c.Locals("email", claims.Email)
+// authenticate validates tokenString and stores the user it was issued for in the context
+func authenticate(c *fiber.Ctx, tokenString, jwtSecret string) error {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	})
 
-		return c.Next()
+	if err != nil || !token.Valid {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token",
+		})
 	}
-} 
\ No newline at end of file
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid token claims",
+		})
+	}
+
+	// Store user info in context
+	c.Locals("user_id", claims.UserID)
+	c.Locals("email", claims.Email)
+	c.Locals("username", claims.Username)
+	c.Locals("roles", claims.Roles)
+	c.Locals("permissions", claims.Permissions)
+
+	return c.Next()
+}
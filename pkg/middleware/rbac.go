@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Roles assigned by the auth service
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+// Permissions checked by the services
+const (
+	PermissionViewUsers     = "users:view"
+	PermissionBanUsers      = "users:ban"
+	PermissionEndGames      = "games:end"
+	PermissionDeleteGames   = "games:delete"
+	PermissionViewRooms     = "rooms:view"
+	PermissionAnnounce      = "announcements:send"
+	PermissionViewReports   = "reports:view"
+	PermissionManageSeasons = "seasons:manage"
+	PermissionManageRoles   = "roles:manage"
+)
+
+// DefaultRoles are the roles the auth service keeps up to date, with their permissions
+var DefaultRoles = map[string][]string{
+	RoleAdmin: {
+		PermissionViewUsers,
+		PermissionBanUsers,
+		PermissionEndGames,
+		PermissionDeleteGames,
+		PermissionViewRooms,
+		PermissionAnnounce,
+		PermissionViewReports,
+		PermissionManageSeasons,
+		PermissionManageRoles,
+	},
+	RoleModerator: {
+		PermissionViewUsers,
+		PermissionBanUsers,
+		PermissionEndGames,
+		PermissionDeleteGames,
+		PermissionViewRooms,
+		PermissionViewReports,
+	},
+}
+
+// RequireRole lets only users with at least one of the roles through. It must run after Auth.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		for _, role := range roles {
+			if HasRole(c, role) {
+				return c.Next()
+			}
+		}
+		return forbidden(c)
+	}
+}
+
+// RequirePermission lets only users with all of the permissions through. It must run after Auth.
+func RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		for _, permission := range permissions {
+			if !HasPermission(c, permission) {
+				return forbidden(c)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// HasRole reports whether the authenticated user has the role
+func HasRole(c *fiber.Ctx, role string) bool {
+	return contains(c.Locals("roles"), role)
+}
+
+// HasPermission reports whether the authenticated user has the permission
+func HasPermission(c *fiber.Ctx, permission string) bool {
+	return contains(c.Locals("permissions"), permission)
+}
+
+// forbidden rejects a request of a user without the required roles or permissions
+func forbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error": "Insufficient permissions",
+	})
+}
+
+// contains reports whether the claim values stored in locals include value
+func contains(locals interface{}, value string) bool {
+	values, _ := locals.([]string)
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
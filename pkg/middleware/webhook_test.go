@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	body := `{"idempotency_key":"key"}`
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		status    int
+	}{
+		{"signed with the secret", "secret", SignBody("secret", []byte(body)), fiber.StatusOK},
+		{"signed with another secret", "secret", SignBody("other", []byte(body)), fiber.StatusUnauthorized},
+		{"signature of another body", "secret", SignBody("secret", []byte(`{}`)), fiber.StatusUnauthorized},
+		{"unsigned", "secret", "", fiber.StatusUnauthorized},
+		{"without a secret", "", SignBody("", []byte(body)), fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/events", WebhookSignature(tt.secret), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+			req := httptest.NewRequest(fiber.MethodPost, "/events", strings.NewReader(body))
+			req.Header.Set(SignatureHeader, tt.signature)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -21,23 +21,271 @@ type User struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	IsActive  bool   `json:"is_active" gorm:"default:true"`
+	AvatarKey string `json:"-"` // blob store prefix of the current avatar, empty without one
+
+	BannedAt  *time.Time `json:"banned_at,omitempty"` // banned users cannot log in
+	BanReason string     `json:"ban_reason,omitempty"`
 }
 
-type Game struct {
+// Role grants permissions to the users it is assigned to. Roles are kept by the auth service,
+// which embeds them and their permissions in the tokens it issues.
+type Role struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name" gorm:"uniqueIndex;size:32;not null"`
+	Permissions string    `json:"permissions"` // comma separated
+}
+
+// UserRole assigns a role to a user
+type UserRole struct {
+	UserID    uint      `json:"user_id" gorm:"primarykey;autoIncrement:false"`
+	RoleID    uint      `json:"role_id" gorm:"primarykey;autoIncrement:false"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuthToken is a single-use token sent by email to verify the address of a user or reset their password,
+// or returned by a login waiting for a second factor. Only a hash of the token is stored.
+type AuthToken struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	Purpose   string     `json:"purpose" gorm:"size:16;not null"` // verify_email, reset_password, mfa_challenge or refresh
+	TokenHash string     `json:"-" gorm:"uniqueIndex;size:64;not null"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"` // also set when a newer token replaced it
+}
+
+// TwoFactor is the TOTP secret of a user. It is pending until the user confirms it with a code,
+// after which logins require a code from it or a recovery code.
+type TwoFactor struct {
+	UserID      uint       `json:"user_id" gorm:"primarykey;autoIncrement:false"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Secret      string     `json:"-" gorm:"size:64;not null"` // base32
+	EnabledAt   *time.Time `json:"enabled_at,omitempty"`
+	LastCounter int64      `json:"-"` // time step of the last accepted code, which cannot be used again
+}
+
+// RecoveryCode is a single-use code replacing a TOTP code when logging in. Only a hash of the code is stored.
+type RecoveryCode struct {
+	ID       uint       `json:"id" gorm:"primarykey"`
+	UserID   uint       `json:"user_id" gorm:"index;not null"`
+	CodeHash string     `json:"-" gorm:"size:64;not null"`
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+}
+
+// UserSettings are per-user preferences. Defaults are set by the user service
+// rather than by column defaults, as gorm would not store false over a default.
+type UserSettings struct {
 	BaseModel
-	Player1ID   uint      `json:"player1_id" gorm:"not null"`
-	Player2ID   *uint     `json:"player2_id"`
-	Status      string    `json:"status" gorm:"default:'waiting'"` // waiting, active, finished
-	WinnerID    *uint     `json:"winner_id"`
-	Board       string    `json:"board" gorm:"default:'---------'"` // 9 characters: X, O, -
-	CurrentTurn uint      `json:"current_turn" gorm:"default:1"`
-	StartedAt   *time.Time `json:"started_at"`
-	FinishedAt  *time.Time `json:"finished_at"`
+	UserID           uint   `json:"user_id" gorm:"uniqueIndex;not null"`
+	DefaultBoardSize int    `json:"default_board_size" gorm:"not null"`
+	DefaultWinLength int    `json:"default_win_length" gorm:"not null"` // K of new games
+	Theme            string `json:"theme" gorm:"size:16;not null"` // light, dark or system
+
+	NotifyGameInvites bool `json:"notify_game_invites"`
+	NotifyYourTurn    bool `json:"notify_your_turn"`
+	NotifyChat        bool `json:"notify_chat"`
+	NotifyEmail       bool `json:"notify_email"`
+}
+
+// Friendship is a friend request from Requester to Addressee, and a friendship once accepted.
+// Declined requests and ended friendships are deleted, so the pair can start over.
+type Friendship struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	RequesterID uint       `json:"requester_id" gorm:"uniqueIndex:idx_friendships_pair;not null"`
+	AddresseeID uint       `json:"addressee_id" gorm:"uniqueIndex:idx_friendships_pair;index;not null"`
+	Status      string     `json:"status" gorm:"size:16;not null"` // pending or accepted
+	AcceptedAt  *time.Time `json:"accepted_at"`
+}
+
+// Block hides the users from each other: no friend requests, chat messages or shared games
+type Block struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	BlockerID uint      `json:"blocker_id" gorm:"uniqueIndex:idx_blocks_pair;not null"`
+	BlockedID uint      `json:"blocked_id" gorm:"uniqueIndex:idx_blocks_pair;index;not null"`
+}
+
+// GameResult is the outcome of a finished game for one of its players,
+// the source of the per-user statistics of the user service
+type GameResult struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	UserID     uint      `json:"user_id" gorm:"uniqueIndex:idx_game_results_user_game;not null"`
+	GameID     string    `json:"game_id" gorm:"uniqueIndex:idx_game_results_user_game;size:64;not null"`
+	Variant    string    `json:"variant" gorm:"size:16"`
+	BoardSize  int       `json:"board_size"`
+	WinLength  int       `json:"win_length"`
+	Symbol     string    `json:"symbol" gorm:"size:8"`
+	Result     string    `json:"result" gorm:"size:8;not null"` // win, loss or draw
+	MovedFirst bool      `json:"moved_first"`
+	Moves      int       `json:"moves"` // moves of all players
+	Duration   int64     `json:"duration"` // milliseconds from start to finish
+	FinishedAt time.Time `json:"finished_at" gorm:"index"`
+}
+
+// Achievement is a badge earned by a user, unlocked at most once per user
+type Achievement struct {
+	ID         uint      `json:"-" gorm:"primarykey"`
+	UserID     uint      `json:"user_id" gorm:"uniqueIndex:idx_achievements_user_key;not null"`
+	Key        string    `json:"key" gorm:"uniqueIndex:idx_achievements_user_key;size:64;not null"`
+	GameID     string    `json:"game_id,omitempty" gorm:"size:64"` // game that unlocked it, if any
+	UnlockedAt time.Time `json:"unlocked_at"`
+}
+
+// UserActivity counts what a user did outside of games
+type UserActivity struct {
+	UserID       uint      `json:"user_id" gorm:"primarykey;autoIncrement:false"`
+	ChatMessages int       `json:"chat_messages"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Report is a complaint of a user about another user, reviewed by moderators
+type Report struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+	ReporterID uint      `json:"reporter_id" gorm:"index;not null"`
+	ReportedID uint      `json:"reported_id" gorm:"index;not null"`
+	GameID     string    `json:"game_id,omitempty" gorm:"size:64"` // game the report is about, if any
+	Reason     string    `json:"reason" gorm:"size:16;not null"`   // cheating, abuse, spam or other
+	Details    string    `json:"details"`
+}
+
+// Rating is the Glicko-2 rating of a user, carried over between seasons with a soft reset
+type Rating struct {
+	UserID     uint      `json:"user_id" gorm:"primarykey;autoIncrement:false"`
+	Rating     float64   `json:"rating"`
+	Deviation  float64   `json:"deviation"`
+	Volatility float64   `json:"volatility"`
+	Games      int       `json:"games"`                 // rated games since the last season reset
+	RatedAt    time.Time `json:"rated_at" gorm:"index"` // when the deviation was last brought up to date
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// RatedGame marks a game whose result was applied to the ratings of its players
+type RatedGame struct {
+	GameID  string    `gorm:"primarykey;size:64"`
+	RatedAt time.Time
+}
+
+// Season is a competitive period, ratings are soft-reset when it starts
+// and its leaderboard is archived when it closes
+type Season struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time  `json:"created_at"`
+	Name      string     `json:"name" gorm:"size:100;not null"`
+	Status    string     `json:"status" gorm:"size:16;index;not null"` // scheduled, active or closed
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    time.Time  `json:"ends_at"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// LeaderboardEntry is a placement on the archived leaderboard of a closed season
+type LeaderboardEntry struct {
+	SeasonID  uint    `json:"season_id,omitempty" gorm:"primarykey;autoIncrement:false"`
+	Rank      int     `json:"rank" gorm:"primarykey;autoIncrement:false"`
+	UserID    uint    `json:"user_id" gorm:"index;not null"`
+	Username  string  `json:"username"` // as it was when the season closed
+	Rating    float64 `json:"rating"`
+	Deviation float64 `json:"deviation"`
+	Games     int     `json:"games"`
+}
+
+// Game is keyed by the domain game ID (a ULID) rather than an auto-increment integer
+type Game struct {
+	ID        string         `json:"id" gorm:"primarykey;size:64"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Player1ID    uint       `json:"player1_id" gorm:"not null"`
+	Player2ID    *uint      `json:"player2_id"`
+	Status       string     `json:"status" gorm:"default:'waiting'"` // waiting, active, finished
+	WinnerID     *uint      `json:"winner_id"`
+	FinishReason string     `json:"finish_reason"` // win_line, draw_board, resign, timeout, abandon, agreed_draw, misere_line, ended
+	Board        string     `json:"board" gorm:"default:'---------'"` // one character per cell: X, O, -
+	CurrentTurn  uint       `json:"current_turn" gorm:"default:1"` // seat number, 1 to 4
+	StartedAt    *time.Time `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+
+	TurnStartedAt *time.Time `json:"turn_started_at"` // when the player to move started thinking
+
+	DrawOfferedByID   *uint  `json:"draw_offered_by_id"`
+	BestOf            int    `json:"best_of" gorm:"default:1"`
+	Variant           string `json:"variant" gorm:"size:16;default:'classic'"` // classic, misere, wild, ultimate, connect, gomoku, gomoku_freestyle
+	BoardSize         int    `json:"board_size"` // connect variant only
+	WinLength         int    `json:"win_length"` // connect variant only
+	Gravity           bool   `json:"gravity"`
+	PlayerCount       int    `json:"player_count" gorm:"default:2"`
+	PlayerIDs         string `json:"player_ids"` // comma separated user IDs in seat order, the first two repeat Player1ID and Player2ID
+	Symbols           string `json:"symbols"`    // comma separated symbols in seat order
+	Eliminated        string `json:"eliminated"` // comma separated user IDs
+	Invited           string `json:"invited"` // comma separated user IDs of the only players who may join
+	Moves             string `json:"moves" gorm:"type:jsonb;not null;default:'[]'"` // move history
+	TakebackLimit     int    `json:"takeback_limit" gorm:"not null;default:0"` // -1 for unlimited
+	AI                string `json:"ai" gorm:"size:8"` // difficulty of the computer opponent, empty in games between people
+
+	TakebackRequestedByID *uint  `json:"takeback_requested_by_id"`
+	TakebacksUsed         string `json:"takebacks_used" gorm:"type:jsonb;not null;default:'{}'"` // accepted takebacks by user ID
+	LastMove          *int   `json:"last_move"` // position of the last move
+	SeriesID          string `json:"series_id" gorm:"index;size:64"` // game ID of the first game of the rematch series
+	PreviousGameID    string `json:"previous_game_id" gorm:"size:64"`
+	NextGameID        string `json:"next_game_id" gorm:"size:64"`
+	RematchAcceptedBy string `json:"rematch_accepted_by"` // comma separated user IDs
+
+	Version int `json:"version" gorm:"not null;default:0"` // incremented on every change, used for compare-and-swap
+}
+
+// GameStream tracks the version of a game event stream
+type GameStream struct {
+	StreamID  string    `json:"stream_id" gorm:"primarykey;size:64"`
+	Version   int       `json:"version" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GameEvent is an append-only domain event of a game stream
+type GameEvent struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	StreamID   string    `json:"stream_id" gorm:"uniqueIndex:idx_game_events_stream_version;size:64;not null"`
+	Version    int       `json:"version" gorm:"uniqueIndex:idx_game_events_stream_version;not null"`
+	EventType  string    `json:"event_type" gorm:"not null"`
+	Payload    string    `json:"payload" gorm:"type:jsonb;not null"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// GameSnapshot is the latest folded state of a game stream
+type GameSnapshot struct {
+	StreamID  string    `json:"stream_id" gorm:"primarykey;size:64"`
+	Version   int       `json:"version" gorm:"not null"`
+	State     string    `json:"state" gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Tournament is a tournament of the game service, stored as a document with its rounds
+type Tournament struct {
+	ID        string    `json:"id" gorm:"primarykey;size:64"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"not null"`
+	Format    string    `json:"format" gorm:"size:16;not null"` // round_robin, swiss, knockout
+	Status    string    `json:"status" gorm:"size:16;index;not null"` // registering, running, finished
+	Version   int       `json:"version" gorm:"not null"`
+	State     string    `json:"state" gorm:"type:jsonb;not null"`
+}
+
+// TournamentGame links a game to the tournament it was paired in
+type TournamentGame struct {
+	GameID       string `json:"game_id" gorm:"primarykey;size:64"`
+	TournamentID string `json:"tournament_id" gorm:"size:64;index;not null"`
 }
 
 type Message struct {
 	BaseModel
-	GameID  uint   `json:"game_id" gorm:"not null"`
+	GameID  string `json:"game_id" gorm:"size:64;not null"`
 	UserID  uint   `json:"user_id" gorm:"not null"`
 	Content string `json:"content" gorm:"not null"`
 } 
\ No newline at end of file
@@ -0,0 +1,32 @@
+package outbox
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ConsumeFunc handles a delivered envelope inside the consumer transaction
+type ConsumeFunc func(tx *gorm.DB, envelope Envelope) error
+
+// Consume runs handle at most once per idempotency key.
+// Redelivered messages are acknowledged without calling handle again.
+func Consume(db *gorm.DB, envelope Envelope, handle ConsumeFunc) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ProcessedMessage{
+			IdempotencyKey: envelope.IdempotencyKey,
+			ProcessedAt:    time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Already processed
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return handle(tx, envelope)
+	})
+}
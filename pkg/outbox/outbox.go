@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/database"
+)
+
+// Message is an event stored in the same transaction as the aggregate change
+// and delivered to subscribers by the Relay
+type Message struct {
+	ID             uint       `json:"id" gorm:"primarykey"`
+	IdempotencyKey string     `json:"idempotency_key" gorm:"uniqueIndex;size:36;not null"`
+	AggregateType  string     `json:"aggregate_type" gorm:"not null"`
+	AggregateID    string     `json:"aggregate_id" gorm:"index;not null"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	Payload        string     `json:"payload" gorm:"type:jsonb;not null"`
+	Attempts       int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index"`
+	LastError      string     `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at" gorm:"index"`
+}
+
+// TableName overrides the table name
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// DeadLetter is a message that could not be delivered within the attempt limit
+type DeadLetter struct {
+	ID             uint      `json:"id" gorm:"primarykey"`
+	IdempotencyKey string    `json:"idempotency_key" gorm:"uniqueIndex;size:36;not null"`
+	AggregateType  string    `json:"aggregate_type" gorm:"not null"`
+	AggregateID    string    `json:"aggregate_id" gorm:"index;not null"`
+	EventType      string    `json:"event_type" gorm:"not null"`
+	Payload        string    `json:"payload" gorm:"type:jsonb;not null"`
+	Attempts       int       `json:"attempts"`
+	LastError      string    `json:"last_error"`
+	CreatedAt      time.Time `json:"created_at"`
+	FailedAt       time.Time `json:"failed_at"`
+}
+
+// TableName overrides the table name
+func (DeadLetter) TableName() string {
+	return "outbox_dead_letters"
+}
+
+// ProcessedMessage remembers idempotency keys already handled by a consumer
+type ProcessedMessage struct {
+	IdempotencyKey string    `json:"idempotency_key" gorm:"primarykey;size:36"`
+	ProcessedAt    time.Time `json:"processed_at"`
+}
+
+// TableName overrides the table name
+func (ProcessedMessage) TableName() string {
+	return "outbox_processed_messages"
+}
+
+// Envelope is the delivered form of a message
+type Envelope struct {
+	IdempotencyKey string          `json:"idempotency_key"`
+	AggregateType  string          `json:"aggregate_type"`
+	AggregateID    string          `json:"aggregate_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// Migrate creates outbox tables for producers
+func Migrate(db *gorm.DB) error {
+	return database.AutoMigrate(db, &Message{}, &DeadLetter{})
+}
+
+// MigrateConsumer creates the idempotency table for consumers
+func MigrateConsumer(db *gorm.DB) error {
+	return database.AutoMigrate(db, &ProcessedMessage{})
+}
+
+// NewMessage creates an outbox message with a fresh idempotency key
+func NewMessage(aggregateType, aggregateID, eventType string, payload interface{}) (*Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	now := time.Now()
+	return &Message{
+		IdempotencyKey: uuid.NewString(),
+		AggregateType:  aggregateType,
+		AggregateID:    aggregateID,
+		EventType:      eventType,
+		Payload:        string(data),
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}, nil
+}
+
+// Enqueue stores messages using tx, which must be the transaction of the aggregate change
+func Enqueue(tx *gorm.DB, messages ...*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := tx.Create(messages).Error; err != nil {
+		return fmt.Errorf("failed to enqueue outbox messages: %w", err)
+	}
+	return nil
+}
+
+// Envelope returns the delivered form of the message
+func (m *Message) Envelope() Envelope {
+	return Envelope{
+		IdempotencyKey: m.IdempotencyKey,
+		AggregateType:  m.AggregateType,
+		AggregateID:    m.AggregateID,
+		EventType:      m.EventType,
+		Payload:        json.RawMessage(m.Payload),
+		CreatedAt:      m.CreatedAt,
+	}
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyHeader carries the message idempotency key in webhook requests
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Webhook - a subscriber URL and the secret envelopes posted to it are signed with
+type Webhook struct {
+	URL    string
+	Secret string
+}
+
+// WebhookPublisher posts envelopes as JSON to subscriber URLs, signing every body
+// with the secret of its subscriber so that no subscriber learns the secret of another
+type WebhookPublisher struct {
+	webhooks []Webhook
+	client   *http.Client
+}
+
+// NewWebhookPublisher creates a publisher for the given subscribers
+func NewWebhookPublisher(webhooks ...Webhook) *WebhookPublisher {
+	return &WebhookPublisher{
+		webhooks: webhooks,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Publish posts the envelope to every subscriber; any failure fails the delivery
+func (p *WebhookPublisher) Publish(ctx context.Context, envelope Envelope) error {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+
+	for _, webhook := range p.webhooks {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(IdempotencyKeyHeader, envelope.IdempotencyKey)
+		req.Header.Set(middleware.SignatureHeader, middleware.SignBody(webhook.Secret, body))
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("webhook %s: %w", webhook.URL, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook %s: unexpected status %s", webhook.URL, resp.Status)
+		}
+	}
+	return nil
+}
+
+// NotifyPublisher sends envelopes through Postgres NOTIFY
+type NotifyPublisher struct {
+	db      *gorm.DB
+	channel string
+}
+
+// NewNotifyPublisher creates a publisher for a Postgres notification channel
+func NewNotifyPublisher(db *gorm.DB, channel string) *NotifyPublisher {
+	return &NotifyPublisher{
+		db:      db,
+		channel: channel,
+	}
+}
+
+// Publish notifies listeners of the channel
+func (p *NotifyPublisher) Publish(ctx context.Context, envelope Envelope) error {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+
+	if err := p.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", p.channel, string(body)).Error; err != nil {
+		return fmt.Errorf("notify %s: %w", p.channel, err)
+	}
+	return nil
+}
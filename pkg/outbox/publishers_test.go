@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+)
+
+func TestWebhookPublisherSignsBodiesWithTheSecretOfEachSubscriber(t *testing.T) {
+	envelope := Envelope{IdempotencyKey: "key", EventType: "game.finished", Payload: json.RawMessage(`{"winner":"alice"}`)}
+
+	var signatures []string
+	newSubscriber := func(secret string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+			signature := r.Header.Get(middleware.SignatureHeader)
+			if signature != want {
+				t.Errorf("signature %q, want %q", signature, want)
+			}
+			if key := r.Header.Get(IdempotencyKeyHeader); key != envelope.IdempotencyKey {
+				t.Errorf("idempotency key %q", key)
+			}
+			signatures = append(signatures, signature)
+		}))
+	}
+	first := newSubscriber("first-secret")
+	defer first.Close()
+	second := newSubscriber("second-secret")
+	defer second.Close()
+
+	publisher := NewWebhookPublisher(Webhook{URL: first.URL, Secret: "first-secret"}, Webhook{URL: second.URL, Secret: "second-secret"})
+	if err := publisher.Publish(context.Background(), envelope); err != nil {
+		t.Fatal(err)
+	}
+	if len(signatures) != 2 || signatures[0] == signatures[1] {
+		t.Fatalf("signatures %v, want one per subscriber secret", signatures)
+	}
+}
+
+func TestWebhookPublisherFailsOnErrorStatus(t *testing.T) {
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer subscriber.Close()
+
+	publisher := NewWebhookPublisher(Webhook{URL: subscriber.URL, Secret: "secret"})
+	err := publisher.Publish(context.Background(), Envelope{IdempotencyKey: "key", Payload: json.RawMessage(`{}`)})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("publishing to a refusing subscriber: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultBatchSize    = 50
+	defaultPollInterval = time.Second
+	defaultMaxAttempts  = 10
+	defaultBaseBackoff  = time.Second
+	defaultMaxBackoff   = 10 * time.Minute
+	defaultLease        = 15 * time.Minute
+)
+
+// ErrNoPublishers is returned by NewRelay without publishers, which would mark every message
+// delivered without sending it anywhere
+var ErrNoPublishers = errors.New("outbox relay needs at least one publisher")
+
+// Publisher delivers a message to subscribers
+type Publisher interface {
+	Publish(ctx context.Context, envelope Envelope) error
+}
+
+// Relay delivers pending outbox messages at least once.
+// Failed deliveries are retried with exponential backoff and moved
+// to the dead-letter table after MaxAttempts.
+type Relay struct {
+	db         *gorm.DB
+	publishers []Publisher
+
+	BatchSize    int
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// Lease is how long a claimed batch is left to one relay; it must cover publishing the whole batch
+	Lease time.Duration
+}
+
+// NewRelay creates a relay with default settings, ErrNoPublishers without publishers
+func NewRelay(db *gorm.DB, publishers ...Publisher) (*Relay, error) {
+	if len(publishers) == 0 {
+		return nil, ErrNoPublishers
+	}
+	return &Relay{
+		db:           db,
+		publishers:   publishers,
+		BatchSize:    defaultBatchSize,
+		PollInterval: defaultPollInterval,
+		MaxAttempts:  defaultMaxAttempts,
+		BaseBackoff:  defaultBaseBackoff,
+		MaxBackoff:   defaultMaxBackoff,
+		Lease:        defaultLease,
+	}, nil
+}
+
+// Run polls the outbox until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := r.ProcessBatch(ctx)
+				if err != nil {
+					log.Printf("Outbox relay error: %v", err)
+					break
+				}
+				if n < r.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// ProcessBatch delivers one batch of due messages and returns its size.
+// The batch is claimed in a short transaction by moving its next attempt past the lease,
+// so that several relay instances can run side by side and a slow subscriber holds no locks.
+// Messages of a relay that stops mid-batch are delivered again once the lease runs out.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	messages, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range messages {
+		if err := r.deliver(ctx, &messages[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(messages), nil
+}
+
+// claim locks a batch of due messages and leases them to this relay
+func (r *Relay) claim(ctx context.Context) ([]Message, error) {
+	var messages []Message
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("delivered_at IS NULL AND next_attempt_at <= ?", now).
+			Order("id").
+			Limit(r.BatchSize).
+			Find(&messages).Error
+		if err != nil {
+			return fmt.Errorf("failed to load outbox messages: %w", err)
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		err = tx.Model(&Message{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(r.Lease)).Error
+		if err != nil {
+			return fmt.Errorf("failed to claim outbox messages: %w", err)
+		}
+		return nil
+	})
+	return messages, err
+}
+
+// deliver publishes a claimed message and records the outcome in a transaction of its own
+func (r *Relay) deliver(ctx context.Context, message *Message) error {
+	publishErr := r.publish(ctx, message.Envelope())
+	now := time.Now()
+	db := r.db.WithContext(ctx)
+
+	if publishErr == nil {
+		return db.Model(message).Updates(map[string]interface{}{
+			"delivered_at": now,
+			"attempts":     message.Attempts + 1,
+			"last_error":   "",
+		}).Error
+	}
+
+	message.Attempts++
+	message.LastError = publishErr.Error()
+
+	if message.Attempts >= r.maxAttempts() {
+		log.Printf("Outbox message %s moved to dead letters: %v", message.IdempotencyKey, publishErr)
+		return db.Transaction(func(tx *gorm.DB) error {
+			return r.deadLetter(tx, message, now)
+		})
+	}
+
+	return db.Model(message).Updates(map[string]interface{}{
+		"attempts":        message.Attempts,
+		"last_error":      message.LastError,
+		"next_attempt_at": now.Add(r.backoff(message.Attempts)),
+	}).Error
+}
+
+// publish sends the envelope to every publisher
+func (r *Relay) publish(ctx context.Context, envelope Envelope) error {
+	var errs []error
+	for _, publisher := range r.publishers {
+		if err := publisher.Publish(ctx, envelope); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deadLetter moves a message to the dead-letter table
+func (r *Relay) deadLetter(tx *gorm.DB, message *Message, failedAt time.Time) error {
+	dead := DeadLetter{
+		IdempotencyKey: message.IdempotencyKey,
+		AggregateType:  message.AggregateType,
+		AggregateID:    message.AggregateID,
+		EventType:      message.EventType,
+		Payload:        message.Payload,
+		Attempts:       message.Attempts,
+		LastError:      message.LastError,
+		CreatedAt:      message.CreatedAt,
+		FailedAt:       failedAt,
+	}
+
+	if err := tx.Create(&dead).Error; err != nil {
+		return fmt.Errorf("failed to store dead letter: %w", err)
+	}
+	return tx.Delete(message).Error
+}
+
+// maxAttempts returns MaxAttempts, or the default when it is not positive,
+// which would move messages to the dead letters without a retry
+func (r *Relay) maxAttempts() int {
+	if r.MaxAttempts < 1 {
+		return defaultMaxAttempts
+	}
+	return r.MaxAttempts
+}
+
+// backoff returns the delay before the next attempt
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= r.MaxBackoff {
+			return r.MaxBackoff
+		}
+	}
+	return delay
+}
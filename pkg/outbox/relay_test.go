@@ -0,0 +1,221 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/config"
+	"github.com/your-org/go-tic-tac-toe/pkg/database"
+	"gorm.io/gorm"
+)
+
+// testDatabase connects to the database named by TEST_DB_NAME, skipping the test without one
+func testDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" {
+		t.Skip("TEST_DB_NAME is not set")
+	}
+
+	cfg := config.Load().Database
+	cfg.Name = name
+	db, err := database.Connect(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// migrateOutbox recreates the outbox tables, dropping them again after the test
+func migrateOutbox(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	tables := []interface{}{&Message{}, &DeadLetter{}, &ProcessedMessage{}}
+	if err := db.Migrator().DropTable(tables...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Migrator().DropTable(tables...) })
+
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := MigrateConsumer(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recordingPublisher records the published envelopes and fails with err
+type recordingPublisher struct {
+	mu        sync.Mutex
+	err       error
+	envelopes []Envelope
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, envelope Envelope) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.envelopes = append(p.envelopes, envelope)
+	return p.err
+}
+
+func TestBackoffDoublesUpToTheMaximum(t *testing.T) {
+	r := &Relay{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := r.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff after %d attempts %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestMaxAttemptsDefaultsWhenNotPositive(t *testing.T) {
+	for _, attempts := range []int{-1, 0} {
+		r := &Relay{MaxAttempts: attempts}
+		if got := r.maxAttempts(); got != defaultMaxAttempts {
+			t.Errorf("MaxAttempts %d allows %d attempts, want %d", attempts, got, defaultMaxAttempts)
+		}
+	}
+	if got := (&Relay{MaxAttempts: 3}).maxAttempts(); got != 3 {
+		t.Errorf("MaxAttempts 3 allows %d attempts", got)
+	}
+}
+
+func TestNewRelayNeedsPublishers(t *testing.T) {
+	if _, err := NewRelay(nil); !errors.Is(err, ErrNoPublishers) {
+		t.Fatalf("relay without publishers: %v", err)
+	}
+}
+
+func TestRelayDeliversPendingMessages(t *testing.T) {
+	db := testDatabase(t)
+	migrateOutbox(t, db)
+
+	first, err := NewMessage("game", "1", "game.finished", map[string]string{"winner": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewMessage("game", "2", "game.finished", map[string]string{"winner": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Enqueue(db, first, second); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := &recordingPublisher{}
+	relay, err := NewRelay(db, publisher)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := relay.ProcessBatch(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("processed %d messages: %v", n, err)
+	}
+	if len(publisher.envelopes) != 2 || publisher.envelopes[0].IdempotencyKey != first.IdempotencyKey {
+		t.Fatalf("published %v", publisher.envelopes)
+	}
+
+	var pending int64
+	if err := db.Model(&Message{}).Where("delivered_at IS NULL").Count(&pending).Error; err != nil {
+		t.Fatal(err)
+	}
+	if pending != 0 {
+		t.Fatalf("%d messages pending after delivery", pending)
+	}
+	if n, err := relay.ProcessBatch(context.Background()); err != nil || n != 0 {
+		t.Fatalf("delivered messages processed again: %d, %v", n, err)
+	}
+}
+
+func TestRelayMovesMessagesToDeadLettersAfterMaxAttempts(t *testing.T) {
+	db := testDatabase(t)
+	migrateOutbox(t, db)
+
+	message, err := NewMessage("game", "1", "game.finished", map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Enqueue(db, message); err != nil {
+		t.Fatal(err)
+	}
+
+	relay, err := NewRelay(db, &recordingPublisher{err: errors.New("subscriber down")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	relay.MaxAttempts = 2
+
+	if _, err := relay.ProcessBatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var stored Message
+	if err := db.First(&stored, message.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Attempts != 1 || stored.LastError != "subscriber down" || !stored.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("after a failed attempt: %d attempts, error %q, next attempt at %v", stored.Attempts, stored.LastError, stored.NextAttemptAt)
+	}
+
+	// Due again without waiting for the backoff
+	if err := db.Model(&stored).Update("next_attempt_at", time.Now()).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := relay.ProcessBatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var dead DeadLetter
+	if err := db.Where("idempotency_key = ?", message.IdempotencyKey).First(&dead).Error; err != nil {
+		t.Fatalf("no dead letter: %v", err)
+	}
+	if dead.Attempts != 2 || dead.LastError != "subscriber down" {
+		t.Fatalf("dead letter with %d attempts and error %q", dead.Attempts, dead.LastError)
+	}
+	if err := db.First(&Message{}, message.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("dead message left in the outbox: %v", err)
+	}
+}
+
+func TestConsumeHandlesEveryKeyOnce(t *testing.T) {
+	db := testDatabase(t)
+	migrateOutbox(t, db)
+
+	envelope := Envelope{IdempotencyKey: "7f8c1f3e-0c4e-4d0e-9c1a-3f5b2d6e8a90", EventType: "game.finished"}
+	calls := 0
+	failing := func(tx *gorm.DB, envelope Envelope) error {
+		calls++
+		return errors.New("handler failed")
+	}
+	handle := func(tx *gorm.DB, envelope Envelope) error {
+		calls++
+		return nil
+	}
+
+	// A failed handler leaves the key unprocessed, so the redelivery is handled
+	if err := Consume(db, envelope, failing); err == nil {
+		t.Fatal("handler error swallowed")
+	}
+	for i := 0; i < 2; i++ {
+		if err := Consume(db, envelope, handle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("handler called %d times, want once after the failure", calls)
+	}
+}
@@ -7,15 +7,26 @@ import (
 )
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID      string   `json:"user_id"`
+	Email       string   `json:"email"`
+	Username    string   `json:"username,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
 	jwt.RegisteredClaims
 }
 
 func GenerateToken(userID, email, secret string, expiration time.Duration) (string, error) {
+	return GenerateTokenWithRoles(userID, email, "", nil, nil, secret, expiration)
+}
+
+// GenerateTokenWithRoles creates a token carrying the username, the roles of the user and the permissions they grant
+func GenerateTokenWithRoles(userID, email, username string, roles, permissions []string, secret string, expiration time.Duration) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:      userID,
+		Email:       email,
+		Username:    username,
+		Roles:       roles,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"auth-service/repository"
+)
+
+// maxBanReasonLength bounds ban reasons in characters
+const maxBanReasonLength = 200
+
+// Page sizes of user searches
+const (
+	defaultUserLimit = 50
+	maxUserLimit     = 100
+)
+
+// AdminHandler finds accounts and manages their roles and bans
+type AdminHandler struct {
+	users  repository.UserRepository
+	roles  repository.RoleRepository
+	tokens repository.TokenRepository
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(users repository.UserRepository, roles repository.RoleRepository, tokens repository.TokenRepository) *AdminHandler {
+	return &AdminHandler{
+		users:  users,
+		roles:  roles,
+		tokens: tokens,
+	}
+}
+
+// RoleResponse - a role with its permissions
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// SetRolesRequest - body of a role assignment, replacing all roles of the user
+type SetRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+// BanRequest - body of a ban
+type BanRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ListRoles lists all roles with their permissions
+func (h *AdminHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.roles.FindRoles()
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	response := make([]RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		response = append(response, RoleResponse{Name: role.Name, Permissions: repository.Permissions(role)})
+	}
+	return utils.SuccessResponse(c, response, "")
+}
+
+// ListUsers searches accounts by email or username with the q query parameter.
+// Pages are selected with offset and limit.
+func (h *AdminHandler) ListUsers(c *fiber.Ctx) error {
+	offset := c.QueryInt("offset", 0)
+	limit := c.QueryInt("limit", defaultUserLimit)
+	if offset < 0 || limit < 1 || limit > maxUserLimit {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "offset must not be negative and limit must be between 1 and 100")
+	}
+
+	users, err := h.users.SearchUsers(strings.TrimSpace(c.Query("q")), offset, limit)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	accounts := make([]*Account, 0, len(users))
+	for i := range users {
+		account, err := loadAccount(h.roles, &users[i])
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		accounts = append(accounts, account)
+	}
+	return utils.SuccessResponse(c, accounts, "")
+}
+
+// GetAccount returns the account of a user with their roles
+func (h *AdminHandler) GetAccount(c *fiber.Ctx) error {
+	user, err := h.user(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return h.respond(c, user, "")
+}
+
+// SetRoles replaces the roles of a user. They apply to the tokens issued from then on.
+func (h *AdminHandler) SetRoles(c *fiber.Ctx) error {
+	var req SetRolesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.user(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if err := h.roles.SetUserRoles(user.ID, req.Roles); err != nil {
+		return errorResponse(c, err)
+	}
+	return h.respond(c, user, "Roles updated")
+}
+
+// Ban keeps a user from logging in. Users with a role can only be banned by admins.
+func (h *AdminHandler) Ban(c *fiber.Ctx) error {
+	var req BanRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if utf8.RuneCountInString(reason) > maxBanReasonLength {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Reason must be at most 200 characters")
+	}
+
+	user, err := h.user(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if c.Locals("user_id") == strconv.FormatUint(uint64(user.ID), 10) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "You cannot ban yourself")
+	}
+
+	roles, err := h.roles.FindUserRoles(user.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if len(roles) > 0 && !middleware.HasRole(c, middleware.RoleAdmin) {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Only admins can ban staff")
+	}
+
+	now := time.Now()
+	user.BannedAt = &now
+	user.BanReason = reason
+	if err := h.users.Update(user); err != nil {
+		return errorResponse(c, err)
+	}
+	// The sessions of the user end with the ban, the last token expires within tokenLifetime
+	if err := h.tokens.RevokeTokens(user.ID, repository.TokenRefresh); err != nil {
+		return errorResponse(c, err)
+	}
+	return h.respond(c, user, "User banned")
+}
+
+// Unban lets a banned user log in again. Users with a role can only be unbanned by admins.
+func (h *AdminHandler) Unban(c *fiber.Ctx) error {
+	user, err := h.user(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	roles, err := h.roles.FindUserRoles(user.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if len(roles) > 0 && !middleware.HasRole(c, middleware.RoleAdmin) {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Only admins can unban staff")
+	}
+
+	user.BannedAt = nil
+	user.BanReason = ""
+	if err := h.users.Update(user); err != nil {
+		return errorResponse(c, err)
+	}
+	return h.respond(c, user, "User unbanned")
+}
+
+// user loads the user of the id route parameter
+func (h *AdminHandler) user(c *fiber.Ctx) (*models.User, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+	return h.users.FindByID(uint(id))
+}
+
+// respond returns the account of the user
+func (h *AdminHandler) respond(c *fiber.Ctx, user *models.User, message string) error {
+	account, err := loadAccount(h.roles, user)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, account, message)
+}
@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
+
+	"auth-service/mail"
+	"auth-service/repository"
+)
+
+// tokenLifetime is how long issued tokens are valid. Tokens are short-lived because they carry
+// the roles of the user: role changes and bans apply once the token is refreshed or expires.
+const tokenLifetime = 15 * time.Minute
+
+// sessionLifetime is how long a login can be refreshed, however often it is.
+// Users log in again after it.
+const sessionLifetime = 30 * 24 * time.Hour
+
+// Password length bounds, bcrypt ignores everything after 72 bytes
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+
+// ProfileCreator creates the profile of a newly registered user in the user service
+type ProfileCreator func(user *models.User) error
+
+// AuthHandler registers users, issues tokens carrying their roles and permissions after the password
+// and the second factor if enabled, and verifies email addresses and resets passwords with links sent by mailer
+type AuthHandler struct {
+	users         repository.UserRepository
+	roles         repository.RoleRepository
+	tokens        repository.TokenRepository
+	twoFactors    repository.TwoFactorRepository
+	mailer        mail.Mailer
+	secret        string
+	adminEmails   []string
+	appURL        string
+	createProfile ProfileCreator
+}
+
+// NewAuthHandler creates a new auth handler. Users with one of adminEmails are made admins when they
+// log in, so that a fresh installation has someone to assign roles. Links in emails point to appURL.
+func NewAuthHandler(users repository.UserRepository, roles repository.RoleRepository, tokens repository.TokenRepository, mailer mail.Mailer, secret string, adminEmails []string, appURL string) *AuthHandler {
+	return &AuthHandler{
+		users:       users,
+		roles:       roles,
+		tokens:      tokens,
+		mailer:      mailer,
+		secret:      secret,
+		adminEmails: adminEmails,
+		appURL:      strings.TrimRight(appURL, "/"),
+	}
+}
+
+// SetProfileCreator sets how the profiles of new users are created. Without one, only the account is stored.
+func (h *AuthHandler) SetProfileCreator(create ProfileCreator) {
+	h.createProfile = create
+}
+
+// RegisterRequest - body of a registration
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginRequest - body of a login
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Account - a user with their roles and the permissions those grant
+type Account struct {
+	ID          uint       `json:"id"`
+	Email       string     `json:"email"`
+	Username    string     `json:"username"`
+	IsActive    bool       `json:"is_active"`
+	Roles       []string   `json:"roles"`
+	Permissions []string   `json:"permissions"`
+	BannedAt    *time.Time `json:"banned_at,omitempty"`
+	BanReason   string     `json:"ban_reason,omitempty"`
+}
+
+// TokenResponse - an issued token and the account it was issued for
+type TokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Account   *Account  `json:"account"`
+
+	// RefreshToken renews the token once, handing out the next refresh token, until the session expires
+	RefreshToken     string    `json:"refresh_token"`
+	SessionExpiresAt time.Time `json:"session_expires_at"`
+}
+
+// RefreshTokenRequest - body exchanging a refresh token for new tokens
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Register creates an inactive account and emails a link to verify its address,
+// after which the user can log in
+func (h *AuthHandler) Register(c *fiber.Ctx) error {
+	var req RegisterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	username := strings.TrimSpace(req.Username)
+	errs := make(map[string]string)
+	if at := strings.Index(email, "@"); at < 1 || at == len(email)-1 {
+		errs["email"] = "must be a valid email address"
+	}
+	if !usernamePattern.MatchString(username) {
+		errs["username"] = "must be 3 to 30 letters, digits or underscores"
+	}
+	if len(req.Password) < minPasswordLength || len(req.Password) > maxPasswordLength {
+		errs["password"] = "must be 8 to 72 characters"
+	}
+	if len(errs) > 0 {
+		return utils.ValidationErrorResponse(c, errs)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to register")
+	}
+
+	user := &models.User{
+		Email:    email,
+		Username: username,
+		Password: string(hash),
+		IsActive: false,
+	}
+	if err := h.users.Create(user); err != nil {
+		return errorResponse(c, err)
+	}
+
+	if h.createProfile != nil {
+		// The account is kept either way, so that the user can still log in
+		if err := h.createProfile(user); err != nil {
+			log.Printf("Failed to create profile of user %d: %v", user.ID, err)
+		}
+	}
+
+	// The link can be sent again, so the account is kept when sending fails
+	if err := h.sendToken(user, repository.TokenVerifyEmail); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
+	account, err := loadAccount(h.roles, user)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, account, "Registered, check your email to verify your address")
+}
+
+// Login checks the credentials of a user and issues a token with their current roles,
+// or a challenge token to complete with LoginTwoFactor when the user has two-factor authentication
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.users.FindByEmail(strings.TrimSpace(req.Email))
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Invalid email or password")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Invalid email or password")
+	}
+
+	if user.BannedAt != nil {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Account is banned")
+	}
+	if !user.IsActive {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Email address is not verified")
+	}
+
+	enabled, err := h.twoFactorEnabled(user)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if enabled {
+		return h.challenge(c, user)
+	}
+
+	response, err := h.issueToken(user, time.Now().Add(sessionLifetime))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, response, "Logged in")
+}
+
+// RefreshToken exchanges the refresh token of a login session for a token with the current roles
+// of the user and the next refresh token. Refreshing does not extend the session,
+// and banned and deactivated users get no new token.
+func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
+	var req RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	stored, err := h.tokens.ConsumeToken(repository.TokenRefresh, hashToken(req.RefreshToken), time.Now())
+	if errors.Is(err, repository.ErrTokenInvalid) {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Session expired, log in again")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	user, err := h.users.FindByID(stored.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Session expired, log in again")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if user.BannedAt != nil {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Account is banned")
+	}
+	if !user.IsActive {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Email address is not verified")
+	}
+
+	response, err := h.issueToken(user, stored.ExpiresAt)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, response, "Token refreshed")
+}
+
+// Me returns the account of the current user with their current roles,
+// which may differ from those of the token until it is renewed
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	account, err := loadAccount(h.roles, user)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, account, "")
+}
+
+// issueToken grants the bootstrap admin role if due and returns a token with the roles of the user
+// and a refresh token of the session ending at sessionExpiresAt
+func (h *AuthHandler) issueToken(user *models.User, sessionExpiresAt time.Time) (*TokenResponse, error) {
+	for _, email := range h.adminEmails {
+		if strings.EqualFold(email, user.Email) {
+			if err := h.roles.AddUserRole(user.ID, middleware.RoleAdmin); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	account, err := loadAccount(h.roles, user)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(tokenLifetime)
+	token, err := utils.GenerateTokenWithRoles(strconv.FormatUint(uint64(user.ID), 10), user.Email, user.Username, account.Roles, account.Permissions, h.secret, tokenLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	err = h.tokens.AddToken(&models.AuthToken{
+		UserID:    user.ID,
+		Purpose:   repository.TokenRefresh,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: sessionExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		Account:          account,
+		RefreshToken:     refreshToken,
+		SessionExpiresAt: sessionExpiresAt,
+	}, nil
+}
+
+// loadAccount returns the account of a user with their roles and permissions
+func loadAccount(roles repository.RoleRepository, user *models.User) (*Account, error) {
+	assigned, err := roles.FindUserRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &Account{
+		ID:          user.ID,
+		Email:       user.Email,
+		Username:    user.Username,
+		IsActive:    user.IsActive,
+		Roles:       []string{},
+		Permissions: []string{},
+		BannedAt:    user.BannedAt,
+		BanReason:   user.BanReason,
+	}
+	granted := make(map[string]bool)
+	for _, role := range assigned {
+		account.Roles = append(account.Roles, role.Name)
+		for _, permission := range repository.Permissions(role) {
+			if !granted[permission] {
+				granted[permission] = true
+				account.Permissions = append(account.Permissions, permission)
+			}
+		}
+	}
+	return account, nil
+}
+
+// loadCurrentUser loads the authenticated user from users
+func loadCurrentUser(c *fiber.Ctx, users repository.UserRepository) (*models.User, error) {
+	userID, _ := c.Locals("user_id").(string)
+	id, err := strconv.ParseUint(userID, 10, 0)
+	if err != nil || id == 0 {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid user")
+	}
+	return users.FindByID(uint(id))
+}
+
+// errorResponse maps errors of handler helpers and repositories to HTTP responses
+func errorResponse(c *fiber.Ctx, err error) error {
+	var rejected *fiber.Error
+	switch {
+	case errors.As(err, &rejected):
+		return utils.ErrorResponse(c, rejected.Code, rejected.Message)
+	case errors.Is(err, repository.ErrUserNotFound):
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+	case errors.Is(err, repository.ErrEmailTaken):
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Email is already registered")
+	case errors.Is(err, repository.ErrUsernameTaken):
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Username is already taken")
+	case errors.Is(err, repository.ErrRoleNotFound):
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Unknown role")
+	case errors.Is(err, repository.ErrTokenInvalid):
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Link is invalid or expired")
+	case errors.Is(err, repository.ErrTwoFactorNotFound):
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Two-factor authentication is not set up")
+	case errors.Is(err, repository.ErrCodeInvalid):
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Invalid code")
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to access account")
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+
+	"auth-service/repository"
+)
+
+// refresh posts the refresh token and returns the status and the issued tokens
+func refresh(t *testing.T, h *AuthHandler, refreshToken string) (int, *TokenResponse) {
+	t.Helper()
+	app := fiber.New()
+	app.Post("/token/refresh", h.RefreshToken)
+	req := httptest.NewRequest(fiber.MethodPost, "/token/refresh", strings.NewReader(`{"refresh_token":"`+refreshToken+`"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		Data *TokenResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, body.Data
+}
+
+func newSession(t *testing.T, sessionExpiresAt time.Time) (*repository.MemoryRepository, *AuthHandler, *models.User, *TokenResponse) {
+	t.Helper()
+	repo := repository.NewMemoryRepository()
+	h := NewAuthHandler(repo, repo, repo, nil, "secret", nil, "")
+	user := &models.User{Email: "alice@example.com", Username: "alice", IsActive: true}
+	if err := repo.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	issued, err := h.issueToken(user, sessionExpiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, h, user, issued
+}
+
+func TestRefreshTokensAreRotated(t *testing.T) {
+	sessionExpiresAt := time.Now().Add(sessionLifetime)
+	_, h, _, issued := newSession(t, sessionExpiresAt)
+
+	status, renewed := refresh(t, h, issued.RefreshToken)
+	if status != fiber.StatusOK {
+		t.Fatalf("status %d, want %d", status, fiber.StatusOK)
+	}
+	if renewed.RefreshToken == "" || renewed.RefreshToken == issued.RefreshToken {
+		t.Fatal("refresh did not hand out the next refresh token")
+	}
+	if !renewed.SessionExpiresAt.Equal(sessionExpiresAt) {
+		t.Fatalf("session expires at %v after the refresh, want %v", renewed.SessionExpiresAt, sessionExpiresAt)
+	}
+
+	if status, _ := refresh(t, h, issued.RefreshToken); status != fiber.StatusUnauthorized {
+		t.Fatalf("used refresh token got status %d, want %d", status, fiber.StatusUnauthorized)
+	}
+	if status, _ := refresh(t, h, renewed.RefreshToken); status != fiber.StatusOK {
+		t.Fatalf("next refresh token got status %d, want %d", status, fiber.StatusOK)
+	}
+}
+
+func TestRefreshEndsWithTheSession(t *testing.T) {
+	_, h, _, issued := newSession(t, time.Now().Add(-time.Second))
+
+	if status, _ := refresh(t, h, issued.RefreshToken); status != fiber.StatusUnauthorized {
+		t.Fatalf("status %d after the session expired, want %d", status, fiber.StatusUnauthorized)
+	}
+}
+
+func TestRefreshRefusesBannedUsers(t *testing.T) {
+	repo, h, user, issued := newSession(t, time.Now().Add(sessionLifetime))
+	now := time.Now()
+	user.BannedAt = &now
+	if err := repo.Update(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if status, _ := refresh(t, h, issued.RefreshToken); status != fiber.StatusForbidden {
+		t.Fatalf("status %d for a banned user, want %d", status, fiber.StatusForbidden)
+	}
+}
+
+func TestBanRevokesSessions(t *testing.T) {
+	repo, h, user, issued := newSession(t, time.Now().Add(sessionLifetime))
+	admin := NewAdminHandler(repo, repo, repo)
+
+	app := fiber.New()
+	app.Post("/admin/users/:id/ban", admin.Ban)
+	req := httptest.NewRequest(fiber.MethodPost, "/admin/users/"+strconv.FormatUint(uint64(user.ID), 10)+"/ban", strings.NewReader(`{"reason":"cheating"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("ban status %d", resp.StatusCode)
+	}
+
+	// Unbanned again, the old session stays over
+	user.BannedAt = nil
+	if err := repo.Update(user); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := refresh(t, h, issued.RefreshToken); status != fiber.StatusUnauthorized {
+		t.Fatalf("status %d for a session of the ban, want %d", status, fiber.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
+
+	"auth-service/mail"
+	"auth-service/repository"
+)
+
+// Lifetimes of the tokens sent by email
+const (
+	verifyEmailLifetime   = 48 * time.Hour
+	resetPasswordLifetime = time.Hour
+)
+
+// EmailRequest - body of a request to send a link to an email address
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+// VerifyEmailRequest - body of an email verification
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ResetPasswordRequest - body of a password reset
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// VerifyEmail activates the account the token was sent to
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.consumeToken(repository.TokenVerifyEmail, req.Token)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	user.IsActive = true
+	if err := h.users.Update(user); err != nil {
+		return errorResponse(c, err)
+	}
+
+	account, err := loadAccount(h.roles, user)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, account, "Email address verified")
+}
+
+// ResendVerification emails a new verification link to an inactive account, replacing earlier links.
+// It responds the same whether or not the address is registered, so that it cannot be used to find accounts.
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	return h.sendLink(c, repository.TokenVerifyEmail, func(user *models.User) bool {
+		return !user.IsActive
+	})
+}
+
+// ForgotPassword emails a password reset link to a registered address, replacing earlier links.
+// It responds the same whether or not the address is registered, so that it cannot be used to find accounts.
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	return h.sendLink(c, repository.TokenResetPassword, func(user *models.User) bool {
+		return user.BannedAt == nil
+	})
+}
+
+// ResetPassword sets the password of the account the token was sent to. As the link was received
+// at the address of the account, it also verifies the address.
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if len(req.Password) < minPasswordLength || len(req.Password) > maxPasswordLength {
+		return utils.ValidationErrorResponse(c, map[string]string{"password": "must be 8 to 72 characters"})
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to reset password")
+	}
+
+	user, err := h.consumeToken(repository.TokenResetPassword, req.Token)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	user.Password = string(hash)
+	user.IsActive = true
+	if err := h.users.Update(user); err != nil {
+		return errorResponse(c, err)
+	}
+	// Sessions of whoever knew the old password end with it
+	if err := h.tokens.RevokeTokens(user.ID, repository.TokenRefresh); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, nil, "Password reset")
+}
+
+// sendLink emails a token for purpose to the user registered with the email of the request if due,
+// and accepts the request either way
+func (h *AuthHandler) sendLink(c *fiber.Ctx, purpose string, due func(user *models.User) bool) error {
+	var req EmailRequest
+	if err := c.BodyParser(&req); err != nil || strings.TrimSpace(req.Email) == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.users.FindByEmail(strings.TrimSpace(req.Email))
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return errorResponse(c, err)
+	}
+	if err == nil && due(user) {
+		// Failures are logged rather than returned, as they would tell that the address is registered
+		if err := h.sendToken(user, purpose); err != nil {
+			log.Printf("Failed to send %s email to user %d: %v", purpose, user.ID, err)
+		}
+	}
+
+	c.Status(fiber.StatusAccepted)
+	return utils.SuccessResponse(c, nil, "If the address is registered, an email is on its way")
+}
+
+// sendToken stores a new token for purpose and emails the user a link with it
+func (h *AuthHandler) sendToken(user *models.User, purpose string) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	msg := mail.Message{To: user.Email}
+	lifetime := verifyEmailLifetime
+	switch purpose {
+	case repository.TokenVerifyEmail:
+		msg.Subject = "Verify your email address"
+		msg.Body = fmt.Sprintf("Hi %s,\n\nOpen this link within 48 hours to verify your email address:\n\n%s\n",
+			user.Username, h.link("/verify-email", token))
+	case repository.TokenResetPassword:
+		lifetime = resetPasswordLifetime
+		msg.Subject = "Reset your password"
+		msg.Body = fmt.Sprintf("Hi %s,\n\nOpen this link within an hour to choose a new password:\n\n%s\n\nIf you did not ask for it, you can ignore this email.\n",
+			user.Username, h.link("/reset-password", token))
+	default:
+		return fmt.Errorf("unknown token purpose %q", purpose)
+	}
+
+	err = h.tokens.CreateToken(&models.AuthToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(lifetime),
+	})
+	if err != nil {
+		return err
+	}
+	return h.mailer.Send(msg)
+}
+
+// consumeToken uses up a token for purpose and returns the user it was sent to
+func (h *AuthHandler) consumeToken(purpose, token string) (*models.User, error) {
+	stored, err := h.tokens.ConsumeToken(purpose, hashToken(token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	user, err := h.users.FindByID(stored.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, repository.ErrTokenInvalid
+	}
+	return user, err
+}
+
+// link returns the address of a frontend page carrying token
+func (h *AuthHandler) link(path, token string) string {
+	return h.appURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// newToken returns a random token to give out, of which only the hash is stored
+func newToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashToken returns the hash a token is stored under
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
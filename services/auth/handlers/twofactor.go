@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
+
+	"auth-service/repository"
+)
+
+// TOTP parameters, the defaults of authenticator apps
+const (
+	totpIssuer = "Tic-Tac-Toe"
+	totpPeriod = 30 // seconds
+	totpSkew   = 1  // periods accepted before and after the current one
+	qrCodeSize = 256
+)
+
+// mfaChallengeLifetime is how long a login waits for the second factor
+const mfaChallengeLifetime = 5 * time.Minute
+
+// Recovery codes are given out as xxxxx-xxxxx in lowercase base32, 50 bits each
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+var totpOpts = totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+// recoveryEncoding encodes recovery codes in lowercase without the letters easily mistaken for digits
+var recoveryEncoding = base32.NewEncoding("abcdefghjkmnpqrstuvwxyz234567890").WithPadding(base32.NoPadding)
+
+// SetTwoFactorRepository enables TOTP two-factor authentication with secrets stored in factors.
+// Without one, logins only need a password.
+func (h *AuthHandler) SetTwoFactorRepository(factors repository.TwoFactorRepository) {
+	h.twoFactors = factors
+}
+
+// TwoFactorCodeRequest - body carrying a TOTP code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// DisableTwoFactorRequest - body of turning two-factor authentication off, which needs the password
+// and a TOTP or recovery code
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// LoginTwoFactorRequest - body completing a login with a TOTP or recovery code
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+// TwoFactorEnrollment - a pending TOTP secret as text, as an otpauth URI and as a QR code of the URI
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+	QRCode string `json:"qr_code"` // PNG data URL
+}
+
+// RecoveryCodesResponse - the recovery codes of a user, which are only shown once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAChallengeResponse - a login waiting for the second factor, completed with the challenge token
+type MFAChallengeResponse struct {
+	MFARequired    bool      `json:"mfa_required"`
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// EnrollTwoFactor creates a TOTP secret for the current user, replacing a pending one.
+// It takes effect once confirmed with a code.
+func (h *AuthHandler) EnrollTwoFactor(c *fiber.Ctx) error {
+	if h.twoFactors == nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Two-factor authentication is not available")
+	}
+
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	factor, err := h.twoFactors.FindTwoFactor(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return errorResponse(c, err)
+	}
+	if err == nil && factor.EnabledAt != nil {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Two-factor authentication is already enabled")
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: totpIssuer, AccountName: user.Email, Period: totpPeriod})
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to enroll")
+	}
+	image, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to enroll")
+	}
+	var qrCode bytes.Buffer
+	if err := png.Encode(&qrCode, image); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to enroll")
+	}
+
+	if err := h.twoFactors.SaveTwoFactor(&models.TwoFactor{UserID: user.ID, Secret: key.Secret()}); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, TwoFactorEnrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCode.Bytes()),
+	}, "Add the secret to an authenticator app and confirm with a code")
+}
+
+// ConfirmTwoFactor enables the pending TOTP secret of the current user with a code from it
+// and returns new recovery codes
+func (h *AuthHandler) ConfirmTwoFactor(c *fiber.Ctx) error {
+	if h.twoFactors == nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Two-factor authentication is not available")
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	factor, err := h.twoFactors.FindTwoFactor(user.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if factor.EnabledAt != nil {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Two-factor authentication is already enabled")
+	}
+
+	now := time.Now()
+	counter, ok := checkTOTP(factor, req.Code, now)
+	if !ok {
+		return errorResponse(c, repository.ErrCodeInvalid)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to enable two-factor authentication")
+	}
+	factor.EnabledAt = &now
+	factor.LastCounter = counter
+	if err := h.twoFactors.EnableTwoFactor(factor, hashes); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, RecoveryCodesResponse{RecoveryCodes: codes},
+		"Two-factor authentication enabled, keep the recovery codes somewhere safe")
+}
+
+// DisableTwoFactor turns two-factor authentication of the current user off. As a stolen token
+// should not be enough, it needs the password and a TOTP or recovery code.
+func (h *AuthHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	if h.twoFactors == nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Two-factor authentication is not available")
+	}
+
+	var req DisableTwoFactorRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Invalid password")
+	}
+
+	factor, err := h.twoFactors.FindTwoFactor(user.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if factor.EnabledAt == nil {
+		return errorResponse(c, repository.ErrTwoFactorNotFound)
+	}
+	if err := h.checkSecondFactor(factor, req.Code); err != nil {
+		return errorResponse(c, err)
+	}
+
+	if err := h.twoFactors.DeleteTwoFactor(user.ID); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, nil, "Two-factor authentication disabled")
+}
+
+// LoginTwoFactor completes a login waiting for the second factor with a TOTP or recovery code.
+// The challenge is used up by the attempt, so a wrong code means logging in again.
+func (h *AuthHandler) LoginTwoFactor(c *fiber.Ctx) error {
+	if h.twoFactors == nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Two-factor authentication is not available")
+	}
+
+	var req LoginTwoFactorRequest
+	if err := c.BodyParser(&req); err != nil || req.ChallengeToken == "" || req.Code == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.consumeToken(repository.TokenMFAChallenge, req.ChallengeToken)
+	if errors.Is(err, repository.ErrTokenInvalid) {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Login expired, log in again")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	factor, err := h.twoFactors.FindTwoFactor(user.ID)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Login expired, log in again")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if err := h.checkSecondFactor(factor, req.Code); err != nil {
+		return errorResponse(c, err)
+	}
+
+	// The account may have been banned or deactivated since the password was checked
+	user, err = h.users.FindByID(user.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if user.BannedAt != nil {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Account is banned")
+	}
+	if !user.IsActive {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Email address is not verified")
+	}
+
+	response, err := h.issueToken(user, time.Now().Add(sessionLifetime))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, response, "Logged in")
+}
+
+// challenge responds to a login of a user with two-factor authentication with a challenge token,
+// replacing earlier challenges of the user
+func (h *AuthHandler) challenge(c *fiber.Ctx, user *models.User) error {
+	token, err := newToken()
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	expiresAt := time.Now().Add(mfaChallengeLifetime)
+	err = h.tokens.CreateToken(&models.AuthToken{
+		UserID:    user.ID,
+		Purpose:   repository.TokenMFAChallenge,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, MFAChallengeResponse{
+		MFARequired:    true,
+		ChallengeToken: token,
+		ExpiresAt:      expiresAt,
+	}, "Two-factor code required")
+}
+
+// twoFactorEnabled reports whether logins of the user need a second factor
+func (h *AuthHandler) twoFactorEnabled(user *models.User) (bool, error) {
+	if h.twoFactors == nil {
+		return false, nil
+	}
+	factor, err := h.twoFactors.FindTwoFactor(user.ID)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return factor.EnabledAt != nil, nil
+}
+
+// checkSecondFactor accepts a TOTP code not used before or an unused recovery code of the enabled factor
+func (h *AuthHandler) checkSecondFactor(factor *models.TwoFactor, code string) error {
+	now := time.Now()
+	if counter, ok := checkTOTP(factor, code, now); ok {
+		// Checked against the stored counter, as a concurrent login may have used the code since factor was loaded
+		if err := h.twoFactors.UseTOTPCounter(factor.UserID, counter); err != nil {
+			return err
+		}
+		factor.LastCounter = counter
+		return nil
+	}
+	return h.twoFactors.UseRecoveryCode(factor.UserID, hashToken(normalizeRecoveryCode(code)), now)
+}
+
+// checkTOTP returns the time step code was generated for if it is a current code of factor
+func checkTOTP(factor *models.TwoFactor, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != int(otp.DigitsSix) {
+		return 0, false
+	}
+
+	for skew := -totpSkew; skew <= totpSkew; skew++ {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		expected, err := totp.GenerateCodeCustom(factor.Secret, at, totpOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return at.Unix() / totpPeriod, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes returns recovery codes to show and the hashes to store
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code := recoveryEncoding.EncodeToString(random)[:recoveryCodeLength]
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, hashToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode removes the separators and case a recovery code may have been typed with
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
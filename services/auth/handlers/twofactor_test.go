@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pquerna/otp/totp"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+
+	"auth-service/repository"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func totpCode(t *testing.T, at time.Time) string {
+	t.Helper()
+	code, err := totp.GenerateCodeCustom(testTOTPSecret, at, totpOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestCheckTOTPAcceptsOnePeriodOfSkew(t *testing.T) {
+	factor := &models.TwoFactor{UserID: 1, Secret: testTOTPSecret}
+	now := time.Unix(1_700_000_010, 0)
+	step := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name    string
+		periods int
+		ok      bool
+	}{
+		{"two periods early", -2, false},
+		{"one period early", -1, true},
+		{"current period", 0, true},
+		{"one period late", 1, true},
+		{"two periods late", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := totpCode(t, now.Add(time.Duration(tt.periods*totpPeriod)*time.Second))
+			counter, ok := checkTOTP(factor, " "+code+" ", now)
+			if ok != tt.ok {
+				t.Fatalf("accepted %v, want %v", ok, tt.ok)
+			}
+			if ok && counter != step+int64(tt.periods) {
+				t.Errorf("counter %d, want %d", counter, step+int64(tt.periods))
+			}
+		})
+	}
+
+	if _, ok := checkTOTP(factor, "12345", now); ok {
+		t.Error("accepted a code of five digits")
+	}
+}
+
+func TestCheckSecondFactorRejectsReplayedCodes(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	h := &AuthHandler{twoFactors: repo}
+	enabledAt := time.Now()
+	factor := &models.TwoFactor{UserID: 1, Secret: testTOTPSecret, EnabledAt: &enabledAt}
+	if err := repo.EnableTwoFactor(factor, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if err := h.checkSecondFactor(factor, totpCode(t, now)); err != nil {
+		t.Fatalf("current code rejected: %v", err)
+	}
+	stored, err := repo.FindTwoFactor(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.LastCounter == 0 {
+		t.Fatal("last counter was not saved")
+	}
+
+	if err := h.checkSecondFactor(stored, totpCode(t, now)); !errors.Is(err, repository.ErrCodeInvalid) {
+		t.Errorf("replayed code: %v, want ErrCodeInvalid", err)
+	}
+	if err := h.checkSecondFactor(stored, totpCode(t, now.Add(-totpPeriod*time.Second))); !errors.Is(err, repository.ErrCodeInvalid) {
+		t.Errorf("code of an earlier period: %v, want ErrCodeInvalid", err)
+	}
+
+	// A concurrent login loaded the factor before the code was used
+	stale := *stored
+	stale.LastCounter = 0
+	if err := h.checkSecondFactor(&stale, totpCode(t, now)); !errors.Is(err, repository.ErrCodeInvalid) {
+		t.Errorf("code replayed with a stale factor: %v, want ErrCodeInvalid", err)
+	}
+}
+
+func TestRecoveryCodesAreUsedOnce(t *testing.T) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("%d codes with %d hashes, want %d", len(codes), len(hashes), recoveryCodeCount)
+	}
+	for i, code := range codes {
+		if len(code) != recoveryCodeLength+1 || code[recoveryCodeLength/2] != '-' {
+			t.Errorf("code %q is not xxxxx-xxxxx", code)
+		}
+		if hashToken(normalizeRecoveryCode(code)) != hashes[i] {
+			t.Errorf("hash of code %q does not match", code)
+		}
+	}
+
+	repo := repository.NewMemoryRepository()
+	h := &AuthHandler{twoFactors: repo}
+	enabledAt := time.Now()
+	factor := &models.TwoFactor{UserID: 1, Secret: testTOTPSecret, EnabledAt: &enabledAt}
+	if err := repo.EnableTwoFactor(factor, hashes); err != nil {
+		t.Fatal(err)
+	}
+
+	// Typed in uppercase with spaces instead of the dash
+	typed := strings.ToUpper(strings.Replace(codes[0], "-", " ", 1))
+	if err := h.checkSecondFactor(factor, typed); err != nil {
+		t.Fatalf("recovery code %q rejected: %v", typed, err)
+	}
+	if err := h.checkSecondFactor(factor, codes[0]); !errors.Is(err, repository.ErrCodeInvalid) {
+		t.Errorf("used recovery code: %v, want ErrCodeInvalid", err)
+	}
+	if err := h.checkSecondFactor(factor, codes[1]); err != nil {
+		t.Errorf("other recovery code rejected: %v", err)
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := map[string]string{
+		"abcde-fghjk":   "abcdefghjk",
+		"ABCDE-FGHJK":   "abcdefghjk",
+		" abcde fghjk ": "abcdefghjk",
+		"abcdefghjk":    "abcdefghjk",
+	}
+	for code, want := range tests {
+		if got := normalizeRecoveryCode(code); got != want {
+			t.Errorf("normalizeRecoveryCode(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+// banningTwoFactors changes the user when a TOTP code is accepted, as if an admin acted during the login
+type banningTwoFactors struct {
+	*repository.MemoryRepository
+	change func(user *models.User)
+}
+
+func (r banningTwoFactors) UseTOTPCounter(userID uint, counter int64) error {
+	user, err := r.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	r.change(user)
+	if err := r.Update(user); err != nil {
+		return err
+	}
+	return r.MemoryRepository.UseTOTPCounter(userID, counter)
+}
+
+func TestLoginTwoFactorRejectsAccountsChangedDuringLogin(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(user *models.User)
+		status int
+	}{
+		{"unchanged", func(user *models.User) {}, fiber.StatusOK},
+		{"banned", func(user *models.User) { now := time.Now(); user.BannedAt = &now }, fiber.StatusForbidden},
+		{"deactivated", func(user *models.User) { user.IsActive = false }, fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			h := NewAuthHandler(repo, repo, repo, nil, "secret", nil, "")
+			h.SetTwoFactorRepository(banningTwoFactors{repo, tt.change})
+
+			user := &models.User{Email: "alice@example.com", Username: "alice", IsActive: true}
+			if err := repo.Create(user); err != nil {
+				t.Fatal(err)
+			}
+			enabledAt := time.Now()
+			if err := repo.EnableTwoFactor(&models.TwoFactor{UserID: user.ID, Secret: testTOTPSecret, EnabledAt: &enabledAt}, nil); err != nil {
+				t.Fatal(err)
+			}
+			err := repo.CreateToken(&models.AuthToken{
+				UserID:    user.ID,
+				Purpose:   repository.TokenMFAChallenge,
+				TokenHash: hashToken("challenge"),
+				ExpiresAt: time.Now().Add(mfaChallengeLifetime),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			app := fiber.New()
+			app.Post("/login/2fa", h.LoginTwoFactor)
+			body := `{"challenge_token":"challenge","code":"` + totpCode(t, time.Now()) + `"}`
+			req := httptest.NewRequest(fiber.MethodPost, "/login/2fa", strings.NewReader(body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -2,12 +2,33 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/your-org/go-tic-tac-toe/pkg/config"
+	"github.com/your-org/go-tic-tac-toe/pkg/database"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+
+	"auth-service/handlers"
+	"auth-service/mail"
+	"auth-service/repository"
+	"auth-service/routes"
 )
 
 func main() {
+	cfg := config.Load()
+
+	repo := newRepository(cfg)
+	if err := repo.EnsureRoles(middleware.DefaultRoles); err != nil {
+		log.Fatal(err)
+	}
+	authHandler := handlers.NewAuthHandler(repo, repo, repo, newMailer(&cfg.Mail), cfg.JWT.Secret, cfg.Admin.Emails, cfg.Mail.AppURL)
+	authHandler.SetTwoFactorRepository(repo)
+	authHandler.SetProfileCreator(userServiceProfiles(serviceURL("USER_SERVICE_URL", "http://localhost:8082"), cfg.Service.Secret))
+	adminHandler := handlers.NewAdminHandler(repo, repo, repo)
+
 	app := fiber.New()
 
 	// CORS middleware
@@ -16,7 +37,7 @@ func main() {
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
-			"status": "ok",
+			"status":  "ok",
 			"service": "auth-service",
 		})
 	})
@@ -25,23 +46,58 @@ func main() {
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"message": "Auth Service",
-			"status": "running",
-		})
-	})
-
-	app.Post("/login", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "Login endpoint",
-			"status": "not implemented yet",
+			"status":  "running",
 		})
 	})
 
-	app.Post("/register", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "Register endpoint",
-			"status": "not implemented yet",
-		})
-	})
+	routes.Setup(app, authHandler, adminHandler, cfg.JWT.Secret)
 
 	log.Fatal(app.Listen(":8081"))
-} 
\ No newline at end of file
+}
+
+// authRepository stores accounts, their roles, the tokens sent to them and their second factors
+type authRepository interface {
+	repository.UserRepository
+	repository.RoleRepository
+	repository.TokenRepository
+	repository.TwoFactorRepository
+}
+
+// newRepository returns a Postgres repository, or an in-memory repository if asked to
+// with STORAGE=memory. It exits when the database is unavailable rather than losing roles and bans.
+func newRepository(cfg *config.Config) authRepository {
+	if cfg.Database.InMemory {
+		log.Println("STORAGE=memory, accounts and their roles are lost on exit")
+		return repository.NewMemoryRepository()
+	}
+
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Database unavailable: %v", err)
+	}
+
+	if err := database.AutoMigrate(db, &models.User{}, &models.Role{}, &models.UserRole{}, &models.AuthToken{}, &models.TwoFactor{}, &models.RecoveryCode{}); err != nil {
+		log.Fatal(err)
+	}
+	return repository.NewPostgresRepository(db)
+}
+
+// newMailer returns an SMTP mailer when a server is configured,
+// or a mailer writing emails to a file or the log for local development
+func newMailer(cfg *config.MailConfig) mail.Mailer {
+	if cfg.SMTPHost != "" {
+		return mail.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From)
+	}
+	if cfg.File == "" {
+		log.Println("SMTP_HOST and MAIL_FILE not set, logging emails")
+	}
+	return mail.NewFileMailer(cfg.File, cfg.From)
+}
+
+// serviceURL returns the base URL of another service from the environment
+func serviceURL(key, fallback string) string {
+	if url := os.Getenv(key); url != "" {
+		return url
+	}
+	return fallback
+}
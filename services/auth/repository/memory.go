@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+)
+
+// MemoryRepository keeps accounts, roles, tokens and second factors in process memory
+type MemoryRepository struct {
+	users         map[uint]models.User
+	roles         map[string]models.Role
+	userRoles     map[uint]map[uint]bool // role IDs by user ID
+	tokens        []models.AuthToken
+	twoFactors    map[uint]models.TwoFactor
+	recoveryCodes map[uint][]models.RecoveryCode // by user ID
+
+	nextUserID  uint
+	nextRoleID  uint
+	nextTokenID uint
+	nextCodeID  uint
+	mu          sync.RWMutex
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		users:         make(map[uint]models.User),
+		roles:         make(map[string]models.Role),
+		userRoles:     make(map[uint]map[uint]bool),
+		twoFactors:    make(map[uint]models.TwoFactor),
+		recoveryCodes: make(map[uint][]models.RecoveryCode),
+		nextUserID:    1,
+		nextRoleID:    1,
+	}
+}
+
+// Create stores a new user and assigns its ID
+func (r *MemoryRepository) Create(user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if strings.EqualFold(existing.Email, user.Email) {
+			return ErrEmailTaken
+		}
+		if existing.Username == user.Username {
+			return ErrUsernameTaken
+		}
+	}
+
+	user.ID = r.nextUserID
+	r.nextUserID++
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	r.users[user.ID] = *user
+	return nil
+}
+
+// FindByID returns the user with the given ID
+func (r *MemoryRepository) FindByID(id uint) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &user, nil
+}
+
+// FindByEmail returns the user registered with the email, ignoring case
+func (r *MemoryRepository) FindByEmail(email string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return &user, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+// SearchUsers returns limit users from offset whose email or username contains query, ignoring case
+func (r *MemoryRepository) SearchUsers(query string, offset, limit int) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query = strings.ToLower(query)
+	var users []models.User
+	for _, user := range r.users {
+		if strings.Contains(strings.ToLower(user.Email), query) || strings.Contains(strings.ToLower(user.Username), query) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	if offset > len(users) {
+		offset = len(users)
+	}
+	return users[offset:min(offset+limit, len(users))], nil
+}
+
+// Update saves the ban, activation and password of an existing user
+func (r *MemoryRepository) Update(user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	stored.BannedAt = user.BannedAt
+	stored.BanReason = user.BanReason
+	stored.IsActive = user.IsActive
+	stored.Password = user.Password
+	stored.UpdatedAt = time.Now()
+	r.users[user.ID] = stored
+	return nil
+}
+
+// EnsureRoles creates the roles that do not exist yet and updates the permissions of the others
+func (r *MemoryRepository) EnsureRoles(roles map[string][]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, permissions := range roles {
+		now := time.Now()
+		if role, ok := r.roles[name]; ok {
+			role.Permissions = strings.Join(permissions, ",")
+			role.UpdatedAt = now
+			r.roles[name] = role
+			continue
+		}
+		r.roles[name] = models.Role{
+			ID:          r.nextRoleID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Name:        name,
+			Permissions: strings.Join(permissions, ","),
+		}
+		r.nextRoleID++
+	}
+	return nil
+}
+
+// FindRoles returns all roles by name
+func (r *MemoryRepository) FindRoles() ([]models.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	roles := make([]models.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, role)
+	}
+	sortRoles(roles)
+	return roles, nil
+}
+
+// FindUserRoles returns the roles of a user by name
+func (r *MemoryRepository) FindUserRoles(userID uint) ([]models.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var roles []models.Role
+	for _, role := range r.roles {
+		if r.userRoles[userID][role.ID] {
+			roles = append(roles, role)
+		}
+	}
+	sortRoles(roles)
+	return roles, nil
+}
+
+// SetUserRoles replaces the roles of a user with the named ones
+func (r *MemoryRepository) SetUserRoles(userID uint, names []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assigned := make(map[uint]bool, len(names))
+	for _, name := range names {
+		role, ok := r.roles[name]
+		if !ok {
+			return ErrRoleNotFound
+		}
+		assigned[role.ID] = true
+	}
+	r.userRoles[userID] = assigned
+	return nil
+}
+
+// AddUserRole assigns the named role to a user who may already have it
+func (r *MemoryRepository) AddUserRole(userID uint, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	role, ok := r.roles[name]
+	if !ok {
+		return ErrRoleNotFound
+	}
+	if r.userRoles[userID] == nil {
+		r.userRoles[userID] = make(map[uint]bool)
+	}
+	r.userRoles[userID][role.ID] = true
+	return nil
+}
+
+// sortRoles orders roles by name, as the Postgres repository does
+func sortRoles(roles []models.Role) {
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+}
+
+// CreateToken stores a token, assigning its ID, and marks the unused tokens of the user
+// for the same purpose used
+func (r *MemoryRepository) CreateToken(token *models.AuthToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := range r.tokens {
+		stored := &r.tokens[i]
+		if stored.UserID == token.UserID && stored.Purpose == token.Purpose && stored.UsedAt == nil {
+			stored.UsedAt = &now
+		}
+	}
+
+	r.nextTokenID++
+	token.ID = r.nextTokenID
+	token.CreatedAt = now
+	r.tokens = append(r.tokens, *token)
+	return nil
+}
+
+// AddToken stores a token, assigning its ID, next to the other tokens of the user
+func (r *MemoryRepository) AddToken(token *models.AuthToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextTokenID++
+	token.ID = r.nextTokenID
+	token.CreatedAt = time.Now()
+	r.tokens = append(r.tokens, *token)
+	return nil
+}
+
+// RevokeTokens marks the unused tokens of the user for the purpose used
+func (r *MemoryRepository) RevokeTokens(userID uint, purpose string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := range r.tokens {
+		stored := &r.tokens[i]
+		if stored.UserID == userID && stored.Purpose == purpose && stored.UsedAt == nil {
+			stored.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+// ConsumeToken marks the unused, unexpired token with the hash and purpose used and returns it
+func (r *MemoryRepository) ConsumeToken(purpose, hash string, now time.Time) (*models.AuthToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.tokens {
+		stored := &r.tokens[i]
+		if stored.TokenHash != hash || stored.Purpose != purpose {
+			continue
+		}
+		if stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+			return nil, ErrTokenInvalid
+		}
+		stored.UsedAt = &now
+		token := *stored
+		return &token, nil
+	}
+	return nil, ErrTokenInvalid
+}
+
+// FindTwoFactor returns the TOTP secret of a user
+func (r *MemoryRepository) FindTwoFactor(userID uint) (*models.TwoFactor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	factor, ok := r.twoFactors[userID]
+	if !ok {
+		return nil, ErrTwoFactorNotFound
+	}
+	return &factor, nil
+}
+
+// SaveTwoFactor stores the TOTP secret of a user, replacing the previous one
+func (r *MemoryRepository) SaveTwoFactor(factor *models.TwoFactor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.saveTwoFactor(factor)
+	return nil
+}
+
+// UseTOTPCounter records the time step of an accepted TOTP code unless a later one was used
+func (r *MemoryRepository) UseTOTPCounter(userID uint, counter int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	factor, ok := r.twoFactors[userID]
+	if !ok || factor.LastCounter >= counter {
+		return ErrCodeInvalid
+	}
+	factor.LastCounter = counter
+	factor.UpdatedAt = time.Now()
+	r.twoFactors[userID] = factor
+	return nil
+}
+
+// EnableTwoFactor saves the enabled secret and replaces the recovery codes of its user
+func (r *MemoryRepository) EnableTwoFactor(factor *models.TwoFactor, codeHashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.saveTwoFactor(factor)
+	codes := make([]models.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		r.nextCodeID++
+		codes = append(codes, models.RecoveryCode{ID: r.nextCodeID, UserID: factor.UserID, CodeHash: hash})
+	}
+	r.recoveryCodes[factor.UserID] = codes
+	return nil
+}
+
+// saveTwoFactor stores factor, keeping its creation time. The caller holds the lock.
+func (r *MemoryRepository) saveTwoFactor(factor *models.TwoFactor) {
+	now := time.Now()
+	if stored, ok := r.twoFactors[factor.UserID]; ok {
+		factor.CreatedAt = stored.CreatedAt
+	} else {
+		factor.CreatedAt = now
+	}
+	factor.UpdatedAt = now
+	r.twoFactors[factor.UserID] = *factor
+}
+
+// DeleteTwoFactor removes the TOTP secret and the recovery codes of a user
+func (r *MemoryRepository) DeleteTwoFactor(userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.twoFactors, userID)
+	delete(r.recoveryCodes, userID)
+	return nil
+}
+
+// UseRecoveryCode marks the unused recovery code of the user with the hash used
+func (r *MemoryRepository) UseRecoveryCode(userID uint, hash string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := r.recoveryCodes[userID]
+	for i := range codes {
+		if codes[i].CodeHash == hash && codes[i].UsedAt == nil {
+			codes[i].UsedAt = &now
+			return nil
+		}
+	}
+	return ErrCodeInvalid
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// PostgresRepository stores accounts, roles, tokens and second factors in Postgres
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a Postgres-backed repository
+func NewPostgresRepository(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// Create stores a new user and assigns its ID
+func (r *PostgresRepository) Create(user *models.User) error {
+	if taken, err := r.taken("LOWER(email)", strings.ToLower(user.Email)); err != nil || taken {
+		return takenError(err, ErrEmailTaken)
+	}
+	if taken, err := r.taken("username", user.Username); err != nil || taken {
+		return takenError(err, ErrUsernameTaken)
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if user.IsActive {
+			return nil
+		}
+		// is_active defaults to true, which gorm uses in place of false
+		return tx.Model(user).Update("is_active", false).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrEmailTaken
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// taken reports whether a user has value in column
+func (r *PostgresRepository) taken(column, value string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.User{}).Where(column+" = ?", value).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", column, err)
+	}
+	return count > 0, nil
+}
+
+// takenError returns err if the check failed and taken otherwise
+func takenError(err, taken error) error {
+	if err != nil {
+		return err
+	}
+	return taken
+}
+
+// FindByID returns the user with the given ID
+func (r *PostgresRepository) FindByID(id uint) (*models.User, error) {
+	return r.find(r.db.Where("id = ?", id))
+}
+
+// FindByEmail returns the user registered with the email, ignoring case
+func (r *PostgresRepository) FindByEmail(email string) (*models.User, error) {
+	return r.find(r.db.Where("LOWER(email) = ?", strings.ToLower(email)))
+}
+
+// SearchUsers returns limit users from offset whose email or username contains query, ignoring case
+func (r *PostgresRepository) SearchUsers(query string, offset, limit int) ([]models.User, error) {
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+
+	var users []models.User
+	err := r.db.Where("LOWER(email) LIKE ? OR LOWER(username) LIKE ?", pattern, pattern).
+		Order("id").Offset(offset).Limit(limit).Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	return users, nil
+}
+
+// find loads the first user matching query
+func (r *PostgresRepository) find(query *gorm.DB) (*models.User, error) {
+	var user models.User
+	err := query.First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	return &user, nil
+}
+
+// Update saves the ban, activation and password of an existing user
+func (r *PostgresRepository) Update(user *models.User) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"banned_at":  user.BannedAt,
+		"ban_reason": user.BanReason,
+		"is_active":  user.IsActive,
+		"password":   user.Password,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to save user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// EnsureRoles creates the roles that do not exist yet and updates the permissions of the others
+func (r *PostgresRepository) EnsureRoles(roles map[string][]string) error {
+	for name, permissions := range roles {
+		role := models.Role{Name: name, Permissions: strings.Join(permissions, ",")}
+		err := r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"permissions", "updated_at"}),
+		}).Create(&role).Error
+		if err != nil {
+			return fmt.Errorf("failed to create role %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// FindRoles returns all roles by name
+func (r *PostgresRepository) FindRoles() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Order("name").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+	return roles, nil
+}
+
+// FindUserRoles returns the roles of a user by name
+func (r *PostgresRepository) FindUserRoles(userID uint) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roles).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user roles: %w", err)
+	}
+	return roles, nil
+}
+
+// SetUserRoles replaces the roles of a user with the named ones in one transaction
+func (r *PostgresRepository) SetUserRoles(userID uint, names []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var roles []models.Role
+		if len(names) > 0 {
+			if err := tx.Where("name IN ?", names).Find(&roles).Error; err != nil {
+				return fmt.Errorf("failed to load roles: %w", err)
+			}
+		}
+		if len(roles) != len(unique(names)) {
+			return ErrRoleNotFound
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return fmt.Errorf("failed to remove user roles: %w", err)
+		}
+		for _, role := range roles {
+			if err := tx.Create(&models.UserRole{UserID: userID, RoleID: role.ID, CreatedAt: time.Now()}).Error; err != nil {
+				return fmt.Errorf("failed to assign role %s: %w", role.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// AddUserRole assigns the named role to a user who may already have it
+func (r *PostgresRepository) AddUserRole(userID uint, name string) error {
+	var role models.Role
+	err := r.db.Where("name = ?", name).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRoleNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load role: %w", err)
+	}
+
+	assignment := models.UserRole{UserID: userID, RoleID: role.ID, CreatedAt: time.Now()}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignment).Error; err != nil {
+		return fmt.Errorf("failed to assign role %s: %w", name, err)
+	}
+	return nil
+}
+
+// CreateToken stores a token and marks the unused tokens of the user for the same purpose used
+func (r *PostgresRepository) CreateToken(token *models.AuthToken) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.AuthToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+	return nil
+}
+
+// AddToken stores a token next to the other tokens of the user
+func (r *PostgresRepository) AddToken(token *models.AuthToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+	return nil
+}
+
+// RevokeTokens marks the unused tokens of the user for the purpose used
+func (r *PostgresRepository) RevokeTokens(userID uint, purpose string) error {
+	err := r.db.Model(&models.AuthToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+	return nil
+}
+
+// ConsumeToken marks the unused, unexpired token with the hash and purpose used and returns it.
+// The token is locked so that concurrent requests cannot both use it.
+func (r *PostgresRepository) ConsumeToken(purpose, hash string, now time.Time) (*models.AuthToken, error) {
+	var token models.AuthToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", hash, purpose, now).
+			First(&token).Error
+		if err != nil {
+			return err
+		}
+		token.UsedAt = &now
+		return tx.Model(&token).Update("used_at", now).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume token: %w", err)
+	}
+	return &token, nil
+}
+
+// FindTwoFactor returns the TOTP secret of a user
+func (r *PostgresRepository) FindTwoFactor(userID uint) (*models.TwoFactor, error) {
+	var factor models.TwoFactor
+	err := r.db.Where("user_id = ?", userID).First(&factor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTwoFactorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load two-factor authentication: %w", err)
+	}
+	return &factor, nil
+}
+
+// SaveTwoFactor stores the TOTP secret of a user, replacing the previous one
+func (r *PostgresRepository) SaveTwoFactor(factor *models.TwoFactor) error {
+	if err := saveTwoFactor(r.db, factor); err != nil {
+		return fmt.Errorf("failed to save two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// UseTOTPCounter records the time step of an accepted TOTP code. The update only matches
+// earlier steps, so concurrent requests cannot both use one code.
+func (r *PostgresRepository) UseTOTPCounter(userID uint, counter int64) error {
+	result := r.db.Model(&models.TwoFactor{}).
+		Where("user_id = ? AND last_counter < ?", userID, counter).
+		Updates(map[string]interface{}{"last_counter": counter, "updated_at": time.Now()})
+	if result.Error != nil {
+		return fmt.Errorf("failed to use TOTP code: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrCodeInvalid
+	}
+	return nil
+}
+
+// EnableTwoFactor saves the enabled secret and replaces the recovery codes of its user in one transaction
+func (r *PostgresRepository) EnableTwoFactor(factor *models.TwoFactor, codeHashes []string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveTwoFactor(tx, factor); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", factor.UserID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]models.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, models.RecoveryCode{UserID: factor.UserID, CodeHash: hash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// saveTwoFactor upserts factor with db
+func saveTwoFactor(db *gorm.DB, factor *models.TwoFactor) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_counter", "updated_at"}),
+	}).Create(factor).Error
+}
+
+// DeleteTwoFactor removes the TOTP secret and the recovery codes of a user in one transaction
+func (r *PostgresRepository) DeleteTwoFactor(userID uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.TwoFactor{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// UseRecoveryCode marks the unused recovery code of the user with the hash used.
+// The update only matches unused codes, so concurrent requests cannot both use one.
+func (r *PostgresRepository) UseRecoveryCode(userID uint, hash string, now time.Time) error {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", now)
+	if result.Error != nil {
+		return fmt.Errorf("failed to use recovery code: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrCodeInvalid
+	}
+	return nil
+}
+
+// unique returns the distinct values
+func unique(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
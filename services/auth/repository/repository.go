@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+)
+
+var (
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrEmailTaken is returned when another user registered with the email
+	ErrEmailTaken = errors.New("email is already registered")
+
+	// ErrUsernameTaken is returned when another user already has the username
+	ErrUsernameTaken = errors.New("username is already taken")
+
+	// ErrRoleNotFound is returned when a role does not exist
+	ErrRoleNotFound = errors.New("role not found")
+
+	// ErrTokenInvalid is returned for tokens that do not exist, expired or were used
+	ErrTokenInvalid = errors.New("token is invalid or expired")
+
+	// ErrTwoFactorNotFound is returned when a user has no TOTP secret, pending or enabled
+	ErrTwoFactorNotFound = errors.New("two-factor authentication not set up")
+
+	// ErrCodeInvalid is returned for wrong or reused TOTP and recovery codes
+	ErrCodeInvalid = errors.New("code is invalid")
+)
+
+// Purposes of tokens sent by email
+const (
+	TokenVerifyEmail   = "verify_email"
+	TokenResetPassword = "reset_password"
+	TokenMFAChallenge  = "mfa_challenge"
+	// TokenRefresh renews the access token of a login session, a user has one per session
+	TokenRefresh = "refresh"
+)
+
+// UserRepository stores the accounts users log in with
+type UserRepository interface {
+	Create(user *models.User) error
+	FindByID(id uint) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
+	// SearchUsers returns limit users from offset whose email or username contains query,
+	// ignoring case, in registration order. An empty query matches every user.
+	SearchUsers(query string, offset, limit int) ([]models.User, error)
+	// Update saves the ban, activation and password of an existing user
+	Update(user *models.User) error
+}
+
+// TokenRepository stores the single-use tokens sent by email and the refresh tokens of login sessions
+type TokenRepository interface {
+	// CreateToken stores a token and marks the unused tokens of the user for the same purpose used,
+	// so that only the latest one sent works
+	CreateToken(token *models.AuthToken) error
+	// AddToken stores a token next to the other tokens of the user for the same purpose
+	AddToken(token *models.AuthToken) error
+	// RevokeTokens marks the unused tokens of the user for the purpose used
+	RevokeTokens(userID uint, purpose string) error
+	// ConsumeToken marks the token with the hash and purpose used and returns it,
+	// ErrTokenInvalid if it does not exist, was used or expired before now
+	ConsumeToken(purpose, hash string, now time.Time) (*models.AuthToken, error)
+}
+
+// RoleRepository stores roles and who they are assigned to
+type RoleRepository interface {
+	// EnsureRoles creates the roles that do not exist yet and gives existing ones
+	// the given permissions, so that new permissions reach roles created before them
+	EnsureRoles(roles map[string][]string) error
+	// FindRoles returns all roles by name
+	FindRoles() ([]models.Role, error)
+	// FindUserRoles returns the roles of a user by name
+	FindUserRoles(userID uint) ([]models.Role, error)
+	// SetUserRoles replaces the roles of a user with the named ones,
+	// ErrRoleNotFound if one of them does not exist
+	SetUserRoles(userID uint, names []string) error
+	// AddUserRole assigns the named role to a user who may already have it
+	AddUserRole(userID uint, name string) error
+}
+
+// Permissions returns the permissions of a role
+func Permissions(role models.Role) []string {
+	var permissions []string
+	for _, permission := range strings.Split(role.Permissions, ",") {
+		if permission = strings.TrimSpace(permission); permission != "" {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
+// TwoFactorRepository stores TOTP secrets and recovery codes
+type TwoFactorRepository interface {
+	// FindTwoFactor returns the TOTP secret of a user, ErrTwoFactorNotFound without one
+	FindTwoFactor(userID uint) (*models.TwoFactor, error)
+	// SaveTwoFactor stores the TOTP secret of a user, replacing the previous one
+	SaveTwoFactor(factor *models.TwoFactor) error
+	// UseTOTPCounter records the time step of an accepted TOTP code of the user,
+	// ErrCodeInvalid if a code of the same or a later step was used already
+	UseTOTPCounter(userID uint, counter int64) error
+	// EnableTwoFactor saves the enabled secret and replaces the recovery codes of its user in one transaction
+	EnableTwoFactor(factor *models.TwoFactor, codeHashes []string) error
+	// DeleteTwoFactor removes the TOTP secret and the recovery codes of a user
+	DeleteTwoFactor(userID uint) error
+	// UseRecoveryCode marks the unused recovery code of the user with the hash used,
+	// ErrCodeInvalid if there is none
+	UseRecoveryCode(userID uint, hash string, now time.Time) error
+}
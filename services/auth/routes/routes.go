@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	"auth-service/handlers"
+)
+
+// Setup registers auth service routes
+func Setup(app *fiber.App, authHandler *handlers.AuthHandler, adminHandler *handlers.AdminHandler, jwtSecret string) {
+	auth := middleware.Auth(jwtSecret)
+
+	app.Post("/register", authHandler.Register)
+	app.Post("/login", authHandler.Login)
+	app.Post("/verify-email", authHandler.VerifyEmail)
+	app.Post("/verify-email/resend", authHandler.ResendVerification)
+	app.Post("/password/forgot", authHandler.ForgotPassword)
+	app.Post("/password/reset", authHandler.ResetPassword)
+	app.Post("/login/2fa", authHandler.LoginTwoFactor)
+	app.Post("/token/refresh", authHandler.RefreshToken)
+	app.Get("/me", auth, authHandler.Me)
+
+	twoFactor := app.Group("/2fa", auth)
+	twoFactor.Post("/enroll", authHandler.EnrollTwoFactor)
+	twoFactor.Post("/confirm", authHandler.ConfirmTwoFactor)
+	twoFactor.Post("/disable", authHandler.DisableTwoFactor)
+
+	admin := app.Group("/admin", auth)
+	admin.Get("/roles", middleware.RequirePermission(middleware.PermissionManageRoles), adminHandler.ListRoles)
+	admin.Get("/users", middleware.RequirePermission(middleware.PermissionViewUsers), adminHandler.ListUsers)
+	admin.Get("/users/:id", middleware.RequirePermission(middleware.PermissionViewUsers), adminHandler.GetAccount)
+	admin.Put("/users/:id/roles", middleware.RequirePermission(middleware.PermissionManageRoles), adminHandler.SetRoles)
+	admin.Post("/users/:id/ban", middleware.RequirePermission(middleware.PermissionBanUsers), adminHandler.Ban)
+	admin.Delete("/users/:id/ban", middleware.RequirePermission(middleware.PermissionBanUsers), adminHandler.Unban)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+
+	"auth-service/handlers"
+)
+
+// userServiceProfiles creates the profiles of new users through the internal API of the user service
+func userServiceProfiles(userServiceURL, serviceSecret string) handlers.ProfileCreator {
+	client := middleware.ServiceClient(serviceSecret, 5*time.Second)
+
+	return func(user *models.User) error {
+		body, err := json.Marshal(map[string]interface{}{
+			"id":       user.ID,
+			"email":    user.Email,
+			"username": user.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Post(userServiceURL+"/internal/users", "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("user service rejected profile: %s", resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+
+	ws "chat-service/websocket"
+)
+
+// maxAnnouncementLength bounds announcements in characters
+const maxAnnouncementLength = 500
+
+// room is a game with connected clients
+type room struct {
+	GameID  string `json:"game_id"`
+	Clients int    `json:"clients"`
+}
+
+// announcementRequest is a system announcement for every connected user
+type announcementRequest struct {
+	Content string `json:"content"`
+}
+
+// roomsHandler lists the games with connected clients and how many clients each has
+func roomsHandler(hub *ws.Hub) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		games := hub.GetActiveGames()
+		sort.Strings(games)
+
+		rooms := make([]room, 0, len(games))
+		clients := 0
+		for _, gameID := range games {
+			count := hub.GetClientCount(gameID)
+			rooms = append(rooms, room{GameID: gameID, Clients: count})
+			clients += count
+		}
+		return c.JSON(fiber.Map{
+			"rooms":   rooms,
+			"clients": clients,
+		})
+	}
+}
+
+// announcementHandler pushes a system announcement to every connection
+// and reports to how many connections it was delivered
+func announcementHandler(hub *ws.Hub) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req announcementRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid announcement",
+			})
+		}
+		content := strings.TrimSpace(req.Content)
+		if content == "" || utf8.RuneCountInString(content) > maxAnnouncementLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "content must be between 1 and 500 characters",
+			})
+		}
+
+		msg := ws.NewSystemMessage("announcement", content, "")
+		return c.JSON(fiber.Map{
+			"delivered": hub.SendToAll(msg),
+		})
+	}
+}
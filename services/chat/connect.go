@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+
+	ws "chat-service/websocket"
+)
+
+// requireUpgrade admits WebSocket upgrades naming the game to join in the game_id query parameter
+func requireUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{
+			"error": "websocket upgrade required",
+		})
+	}
+	if c.Query("game_id") == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "game_id required",
+		})
+	}
+	return c.Next()
+}
+
+// connectHandler registers the connection of an authenticated user with the hub
+// as a client of the game and serves it until it closes
+func connectHandler(hub *ws.Hub) fiber.Handler {
+	return websocket.New(func(c *websocket.Conn) {
+		userID, _ := c.Locals("user_id").(string)
+		username, _ := c.Locals("username").(string)
+		if username == "" {
+			username = "Player " + userID
+		}
+
+		client := ws.NewClient(userID, username, c.Query("game_id"), c.Conn, hub)
+		hub.Register <- client
+
+		// The connection is released when the handler returns, so it waits for both pumps
+		written := make(chan struct{})
+		go func() {
+			client.WritePump()
+			close(written)
+		}()
+		client.ReadPump()
+		<-written
+	})
+}
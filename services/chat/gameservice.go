@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	ws "chat-service/websocket"
+)
+
+// gameServiceAbandonHandler reports players who forfeit a game to the game service.
+// Forfeits the game service refuses, such as those of games that ended in the meantime,
+// count as reported, as reporting them again would not change the answer.
+func gameServiceAbandonHandler(gameServiceURL, serviceSecret string) ws.AbandonHandler {
+	client := middleware.ServiceClient(serviceSecret, 5*time.Second)
+
+	return func(gameID, playerID, reason string) error {
+		url := fmt.Sprintf("%s/internal/games/%s/abandon", gameServiceURL, gameID)
+		err := postPlayerAction(client, url, map[string]string{"player_id": playerID, "reason": reason})
+
+		var rejected *rejectedError
+		if errors.As(err, &rejected) && rejected.status < http.StatusInternalServerError && rejected.status != http.StatusConflict {
+			log.Printf("Game service refused forfeit of player %s in game %s: %v", playerID, gameID, err)
+			return nil
+		}
+		return err
+	}
+}
+
+// Delays before asking the game service for the seats of active games again, doubled after every failure
+const (
+	seatsRetryDelay    = 5 * time.Second
+	maxSeatsRetryDelay = 2 * time.Minute
+)
+
+// restoreGameServiceSeats loads the seated players of active games from the game service into the hub,
+// retrying until it succeeds, so that forfeits pending when the chat service stopped are not lost
+func restoreGameServiceSeats(hub *ws.Hub, gameServiceURL, serviceSecret string) {
+	client := middleware.ServiceClient(serviceSecret, 10*time.Second)
+
+	for delay := seatsRetryDelay; ; delay = min(delay*2, maxSeatsRetryDelay) {
+		seats, err := fetchActiveSeats(client, gameServiceURL)
+		if err == nil {
+			for _, game := range seats {
+				hub.RestoreSeats(game.GameID, game.Version, game.PlayerIDs)
+			}
+			log.Printf("Restored seats of %d active games", len(seats))
+			return
+		}
+
+		log.Printf("Error loading seats of active games, retrying in %s: %v", delay, err)
+		time.Sleep(delay)
+	}
+}
+
+// gameSeats - the players seated in an active game of the game service
+type gameSeats struct {
+	GameID    string   `json:"game_id"`
+	Version   int      `json:"version"`
+	PlayerIDs []string `json:"player_ids"`
+}
+
+// fetchActiveSeats returns the seated players of every active game of the game service
+func fetchActiveSeats(client *http.Client, gameServiceURL string) ([]gameSeats, error) {
+	resp, err := client.Get(gameServiceURL + "/internal/games/active/seats")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("game service rejected request: %s", resp.Status)
+	}
+
+	var result struct {
+		Data []gameSeats `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode seats: %w", err)
+	}
+	return result.Data, nil
+}
+
+// gameServiceTakebackHandler forwards takeback actions to the game service
+func gameServiceTakebackHandler(gameServiceURL, serviceSecret string) ws.TakebackHandler {
+	client := middleware.ServiceClient(serviceSecret, 5*time.Second)
+
+	return func(gameID, playerID, action string) error {
+		url := fmt.Sprintf("%s/internal/games/%s/takeback/%s", gameServiceURL, gameID, action)
+		return postPlayerAction(client, url, map[string]string{"player_id": playerID})
+	}
+}
+
+// rejectedError - the error message of a request the game service rejected with status
+type rejectedError struct {
+	status  int
+	message string
+}
+
+func (e *rejectedError) Error() string {
+	return e.message
+}
+
+// postPlayerAction posts an action of a player to the game service
+// and returns the service error message if it was rejected
+func postPlayerAction(client *http.Client, url string, action map[string]string) error {
+	body, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		rejected := &rejectedError{status: resp.StatusCode, message: fmt.Sprintf("game service rejected request: %s", resp.Status)}
+		var result struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err == nil && result.Error != "" {
+			rejected.message = result.Error
+		}
+		return rejected
+	}
+
+	return nil
+}
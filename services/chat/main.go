@@ -2,13 +2,37 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/websocket/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/config"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	ws "chat-service/websocket"
 )
 
 func main() {
+	cfg := config.Load()
+
+	gameServiceURL := os.Getenv("GAME_SERVICE_URL")
+	if gameServiceURL == "" {
+		gameServiceURL = "http://localhost:8083"
+	}
+
+	userServiceURL := os.Getenv("USER_SERVICE_URL")
+	if userServiceURL == "" {
+		userServiceURL = "http://localhost:8082"
+	}
+
+	hub := ws.NewHub()
+	hub.SetAbandonHandler(gameServiceAbandonHandler(gameServiceURL, cfg.Service.Secret))
+	hub.SetTakebackHandler(gameServiceTakebackHandler(gameServiceURL, cfg.Service.Secret))
+	hub.SetBlockChecker(userServiceBlockChecker(userServiceURL, cfg.Service.Secret))
+	hub.SetChatHandler(userServiceChatHandler(userServiceURL, cfg.Service.Secret))
+	go hub.Run()
+	go restoreGameServiceSeats(hub, gameServiceURL, cfg.Service.Secret)
+
 	app := fiber.New()
 
 	// CORS middleware
@@ -30,24 +54,8 @@ func main() {
 		})
 	})
 
-	// WebSocket endpoint
-	app.Get("/ws", websocket.New(func(c *websocket.Conn) {
-		// Simple WebSocket handler
-		for {
-			messageType, message, err := c.ReadMessage()
-			if err != nil {
-				log.Println("read:", err)
-				break
-			}
-			
-			// Echo the message back
-			err = c.WriteMessage(messageType, message)
-			if err != nil {
-				log.Println("write:", err)
-				break
-			}
-		}
-	}))
+	// WebSocket endpoint, joined with /ws?game_id=...&token=...
+	app.Get("/ws", middleware.WebSocketAuth(cfg.JWT.Secret), requireUpgrade, connectHandler(hub))
 
 	app.Get("/messages", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -63,5 +71,16 @@ func main() {
 		})
 	})
 
+	// Admin endpoints
+	admin := app.Group("/admin", middleware.Auth(cfg.JWT.Secret))
+	admin.Get("/rooms", middleware.RequirePermission(middleware.PermissionViewRooms), roomsHandler(hub))
+	admin.Post("/announcements", middleware.RequirePermission(middleware.PermissionAnnounce), announcementHandler(hub))
+
+	// Service-to-service endpoints, authenticated with the shared service secret
+	internal := app.Group("/internal", middleware.ServiceAuth(cfg.Service.Secret))
+	internal.Get("/presence", presenceHandler(hub))
+	internal.Post("/users/:id/messages", notificationHandler(hub))
+	internal.Put("/games/:id/seats", seatsHandler(hub))
+
 	log.Fatal(app.Listen(":8084"))
 } 
\ No newline at end of file
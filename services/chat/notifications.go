@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+
+	ws "chat-service/websocket"
+)
+
+// notificationRequest is a system message another service sends to a user
+type notificationRequest struct {
+	Action  string          `json:"action"`
+	Content string          `json:"content"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// notificationHandler pushes a system message to every connection of the user :id
+// and reports to how many connections it was delivered
+func notificationHandler(hub *ws.Hub) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req notificationRequest
+		if err := c.BodyParser(&req); err != nil || req.Action == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid notification",
+			})
+		}
+
+		msg := ws.NewSystemMessage(req.Action, req.Content, "")
+		if len(req.Data) > 0 {
+			msg.Data = req.Data
+		}
+		return c.JSON(fiber.Map{
+			"delivered": hub.SendToUser(c.Params("id"), msg),
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	ws "chat-service/websocket"
+)
+
+// maxPresenceUsers bounds the users of a single presence lookup
+const maxPresenceUsers = 500
+
+// presenceHandler reports which of the comma separated user_ids are connected to the hub
+func presenceHandler(hub *ws.Hub) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var userIDs []string
+		for _, id := range strings.Split(c.Query("user_ids"), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				userIDs = append(userIDs, id)
+			}
+		}
+		if len(userIDs) > maxPresenceUsers {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "too many user_ids",
+			})
+		}
+
+		online := make(map[string]bool, len(userIDs))
+		for _, id := range userIDs {
+			online[id] = hub.IsOnline(id)
+		}
+		return c.JSON(fiber.Map{
+			"online": online,
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	ws "chat-service/websocket"
+)
+
+// seatsRequest - the players seated in a game, reported by the game service whenever they change
+type seatsRequest struct {
+	Active    bool     `json:"active"`
+	Version   int      `json:"version"`
+	PlayerIDs []string `json:"player_ids"`
+}
+
+// seatsHandler records the seated players of the game :id, who forfeit it when they stay away
+func seatsHandler(hub *ws.Hub) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req seatsRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid seats",
+			})
+		}
+
+		hub.SetSeats(c.Params("id"), req.Version, req.Active, req.PlayerIDs)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
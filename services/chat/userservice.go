@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	ws "chat-service/websocket"
+)
+
+// blockCacheTTL is how long blocks of a user are reused before asking the user service again
+const blockCacheTTL = 30 * time.Second
+
+// cachedBlocks are the blocks of a user as of fetchedAt
+type cachedBlocks struct {
+	userIDs   []string
+	fetchedAt time.Time
+}
+
+// blockCache holds the blocks of the users who chatted recently. Expired entries are
+// pruned on write at most once per blockCacheTTL, so the cache stays as large as
+// the users seen within two TTLs.
+type blockCache struct {
+	mu       sync.Mutex
+	entries  map[string]cachedBlocks
+	prunedAt time.Time
+}
+
+func newBlockCache() *blockCache {
+	return &blockCache{entries: make(map[string]cachedBlocks), prunedAt: time.Now()}
+}
+
+// get returns the blocks of a user fetched less than blockCacheTTL before now
+func (c *blockCache) get(userID string, now time.Time) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.entries[userID]
+	if !ok || now.Sub(cached.fetchedAt) >= blockCacheTTL {
+		return nil, false
+	}
+	return cached.userIDs, true
+}
+
+// put stores the blocks of a user fetched at now
+func (c *blockCache) put(userID string, userIDs []string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.prunedAt) >= blockCacheTTL {
+		for id, cached := range c.entries {
+			if now.Sub(cached.fetchedAt) >= blockCacheTTL {
+				delete(c.entries, id)
+			}
+		}
+		c.prunedAt = now
+	}
+	c.entries[userID] = cachedBlocks{userIDs: userIDs, fetchedAt: now}
+}
+
+// userServiceBlockChecker looks up blocks with the user service.
+// Lookups are cached briefly, as every chat message needs the blocks of its sender.
+func userServiceBlockChecker(userServiceURL, serviceSecret string) ws.BlockChecker {
+	client := middleware.ServiceClient(serviceSecret, 5*time.Second)
+	cache := newBlockCache()
+
+	return func(userID string) ([]string, error) {
+		if userIDs, ok := cache.get(userID, time.Now()); ok {
+			return userIDs, nil
+		}
+
+		resp, err := client.Get(fmt.Sprintf("%s/internal/users/%s/blocks", userServiceURL, userID))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("user service rejected request: %s", resp.Status)
+		}
+
+		var result struct {
+			Data struct {
+				UserIDs []string `json:"user_ids"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode blocks: %w", err)
+		}
+
+		cache.put(userID, result.Data.UserIDs, time.Now())
+		return result.Data.UserIDs, nil
+	}
+}
+
+// chatMessageSentEvent is the event type reporting chat messages to the user service
+const chatMessageSentEvent = "chat.message_sent"
+
+// chatEventQueueSize bounds the chat events waiting for delivery; further events are dropped
+const chatEventQueueSize = 256
+
+// Deliveries of a chat event, waiting chatEventRetryDelay after the first failure and twice as long after every other
+const (
+	chatEventMaxAttempts = 5
+	chatEventRetryDelay  = time.Second
+)
+
+// eventEnvelope mirrors the outbox envelope the user service receives events in
+type eventEnvelope struct {
+	IdempotencyKey string          `json:"idempotency_key"`
+	AggregateType  string          `json:"aggregate_type"`
+	AggregateID    string          `json:"aggregate_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// userServiceChatHandler reports delivered chat messages to the user service events endpoint.
+// Events are keyed by their game and message, so the user service counts a retried one once.
+// They are sent in the background so a slow user service never delays chat, which makes them
+// best effort rather than covered by the outbox guarantees: an event is dropped when the queue is full,
+// after chatEventMaxAttempts failed deliveries or when the chat service stops before sending it.
+func userServiceChatHandler(userServiceURL, serviceSecret string) ws.ChatHandler {
+	client := middleware.ServiceClient(serviceSecret, 5*time.Second)
+	queue := make(chan eventEnvelope, chatEventQueueSize)
+
+	go func() {
+		for envelope := range queue {
+			deliverChatEvent(client, userServiceURL, envelope)
+		}
+	}()
+
+	return func(gameID, playerID, messageID string) {
+		payload, err := json.Marshal(map[string]string{
+			"user_id": playerID,
+			"game_id": gameID,
+		})
+		if err != nil {
+			return
+		}
+
+		select {
+		case queue <- eventEnvelope{
+			IdempotencyKey: fmt.Sprintf("chat:%s:%s", gameID, messageID),
+			AggregateType:  "chat",
+			AggregateID:    gameID,
+			EventType:      chatMessageSentEvent,
+			Payload:        payload,
+			CreatedAt:      time.Now(),
+		}:
+		default:
+			log.Printf("Chat event queue full, dropping message event of %s", playerID)
+		}
+	}
+}
+
+// deliverChatEvent posts a chat event to the user service, retrying failed deliveries with backoff
+func deliverChatEvent(client *http.Client, userServiceURL string, envelope eventEnvelope) {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		log.Printf("Error marshaling chat event: %v", err)
+		return
+	}
+
+	delay := chatEventRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := postChatEvent(client, userServiceURL, body)
+		if err == nil {
+			return
+		}
+		if attempt == chatEventMaxAttempts {
+			log.Printf("Dropping chat event %s after %d attempts: %v", envelope.IdempotencyKey, attempt, err)
+			return
+		}
+
+		log.Printf("Error reporting chat message, retrying in %s: %v", delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// postChatEvent posts an encoded chat event to the user service events endpoint
+func postChatEvent(client *http.Client, userServiceURL string, body []byte) error {
+	resp, err := client.Post(userServiceURL+"/internal/events", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("user service rejected chat event: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlockCachePrunesExpiredEntries(t *testing.T) {
+	cache := newBlockCache()
+	start := time.Now()
+
+	cache.put("1", []string{"2"}, start)
+	if blocked, ok := cache.get("1", start.Add(blockCacheTTL-time.Second)); !ok || len(blocked) != 1 {
+		t.Fatalf("fresh blocks %v, %v", blocked, ok)
+	}
+	if _, ok := cache.get("1", start.Add(blockCacheTTL)); ok {
+		t.Fatal("expired blocks returned")
+	}
+
+	cache.put("3", nil, start.Add(blockCacheTTL))
+	if _, ok := cache.entries["1"]; ok {
+		t.Fatal("expired entry kept after a write")
+	}
+	if len(cache.entries) != 1 {
+		t.Fatalf("%d entries cached, want 1", len(cache.entries))
+	}
+}
@@ -2,10 +2,9 @@ package websocket
 
 import (
 	"log"
-	"sync"
 	"time"
 
-	"github.com/gorilla/websocket"
+	"github.com/fasthttp/websocket"
 )
 
 // Client represents a WebSocket client
@@ -16,7 +15,6 @@ type Client struct {
 	Conn     *websocket.Conn
 	Hub      *Hub
 	Send     chan []byte
-	mu       sync.Mutex
 }
 
 // NewClient creates a new client
@@ -39,9 +37,9 @@ func (c *Client) ReadPump() {
 	}()
 
 	c.Conn.SetReadLimit(maxMessageSize)
-	c.Conn.SetReadDeadline(pongWait)
+	c.Conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.Conn.SetPongHandler(func(string) error {
-		c.Conn.SetReadDeadline(pongWait)
+		c.Conn.SetReadDeadline(time.Now().Add(pongWait))
 		return nil
 	})
 
@@ -70,7 +68,7 @@ func (c *Client) WritePump() {
 	for {
 		select {
 		case message, ok := <-c.Send:
-			c.Conn.SetWriteDeadline(writeWait)
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
 				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
@@ -93,7 +91,7 @@ func (c *Client) WritePump() {
 				return
 			}
 		case <-ticker.C:
-			c.Conn.SetWriteDeadline(writeWait)
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
@@ -101,16 +99,12 @@ func (c *Client) WritePump() {
 	}
 }
 
-// SendMessage sends a message to client
+// SendMessage sends a message to client, dropping it when the send buffer is full.
+// The channel is only closed by the hub when the client unregisters.
 func (c *Client) SendMessage(message []byte) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	
 	select {
 	case c.Send <- message:
 	default:
-		close(c.Send)
-		c.Hub.Unregister <- c
 	}
 }
 
@@ -124,24 +118,7 @@ func (c *Client) GetUsername() string {
 	return c.Username
 }
 
-// IsConnected checks if client is connected
-func (c *Client) IsConnected() bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	
-	select {
-	case <-c.Send:
-		return false
-	default:
-		return true
-	}
-}
-
-// Close closes the client connection
+// Close closes the client connection, which ends ReadPump and unregisters the client
 func (c *Client) Close() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	
-	close(c.Send)
 	c.Conn.Close()
 } 
\ No newline at end of file
@@ -19,8 +19,44 @@ const (
 
 	// Maximum message size
 	maxMessageSize = 512
+
+	// Time a seated player may stay away, or take to connect, before forfeiting
+	defaultAbandonTimeout = 2 * time.Minute
+
+	// Period for checking disconnected players
+	abandonCheckPeriod = 10 * time.Second
+
+	// Delays before reporting a forfeit the game service failed to take again, doubled after every failure
+	abandonRetryDelay    = 10 * time.Second
+	maxAbandonRetryDelay = 5 * time.Minute
+)
+
+// Reasons a seated player forfeits, passed to the AbandonHandler
+const (
+	AbandonReasonAbandon = "abandon" // the player disconnected and stayed away
+	AbandonReasonTimeout = "timeout" // the player never connected after being seated
 )
 
+// AbandonHandler is called when a seated player of an active game has been away for too long.
+// The forfeit is reported again with backoff until the handler returns nil.
+type AbandonHandler func(gameID, playerID, reason string) error
+
+// TakebackHandler forwards a takeback action (request, accept or decline) of a player to the game
+type TakebackHandler func(gameID, playerID, action string) error
+
+// BlockChecker returns the IDs of users who blocked the user or whom the user blocked
+type BlockChecker func(userID string) ([]string, error)
+
+// ChatHandler is called after a chat message of a player was delivered with the ID of the message. It must not block.
+type ChatHandler func(gameID, playerID, messageID string)
+
+// takebackMessages are the system messages announcing takeback actions
+var takebackMessages = map[string]string{
+	"request": "A takeback was requested",
+	"accept":  "The takeback was accepted",
+	"decline": "The takeback was declined",
+}
+
 // Hub manages WebSocket connections
 type Hub struct {
 	// Registered clients by games
@@ -33,10 +69,50 @@ type Hub struct {
 	// Channel for sending messages
 	Broadcast chan *Message
 
+	// Seated players of active games as reported by the game service
+	seats map[string]*seating
+
+	// Seated players who are away, by games
+	disconnected map[string]map[string]*absence
+
+	// Threshold after which a seated player who is away forfeits
+	AbandonTimeout time.Duration
+
+	// Callback for players who exceeded AbandonTimeout
+	onAbandon AbandonHandler
+
+	// Callback for takeback actions
+	onTakeback TakebackHandler
+
+	// Lookup of blocks hiding chat messages between users
+	blocks BlockChecker
+
+	// Callback for delivered chat messages
+	onChat ChatHandler
+
 	// Mutex for safe access to clients
 	mu sync.RWMutex
 }
 
+// seating - the players seated in an active game
+type seating struct {
+	// version of the game the seats were reported at, older reports are ignored
+	version int
+	// connected tells for every seated player whether they connected since they were seated
+	connected map[string]bool
+}
+
+// absence - a seated player who is away from a game
+type absence struct {
+	// since the player was seated or lost their last connection
+	since time.Time
+	// reporting tells whether the forfeit is being reported to the AbandonHandler
+	reporting bool
+	// failures of reporting the forfeit, which is not reported again before retryAt
+	failures int
+	retryAt  time.Time
+}
+
 // NewHub creates a new hub
 func NewHub() *Hub {
 	return &Hub{
@@ -44,11 +120,107 @@ func NewHub() *Hub {
 		Register:   make(chan *Client),
 		Unregister: make(chan *Client),
 		Broadcast:  make(chan *Message),
+
+		seats:          make(map[string]*seating),
+		disconnected:   make(map[string]map[string]*absence),
+		AbandonTimeout: defaultAbandonTimeout,
+	}
+}
+
+// SetAbandonHandler sets the callback for players who stayed disconnected too long
+func (h *Hub) SetAbandonHandler(handler AbandonHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.onAbandon = handler
+}
+
+// SetTakebackHandler sets the callback for takeback actions
+func (h *Hub) SetTakebackHandler(handler TakebackHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.onTakeback = handler
+}
+
+// SetBlockChecker sets the lookup of blocked users whose chat messages are not delivered
+func (h *Hub) SetBlockChecker(check BlockChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.blocks = check
+}
+
+// SetChatHandler sets the callback for delivered chat messages
+func (h *Hub) SetChatHandler(handler ChatHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.onChat = handler
+}
+
+// SetSeats records the players seated in a game reported at version. Seated players who are not
+// connected have AbandonTimeout from now to connect, and games that are not active are no longer watched.
+func (h *Hub) SetSeats(gameID string, version int, active bool, playerIDs []string) {
+	h.setSeats(gameID, version, active, playerIDs, false)
+}
+
+// RestoreSeats records the players seated in an active game the hub lost track of, as on a restart.
+// Players who are not connected have AbandonTimeout from now to come back. It is not known
+// whether they connected before, so they abandon the game rather than time out if they do not.
+func (h *Hub) RestoreSeats(gameID string, version int, playerIDs []string) {
+	h.setSeats(gameID, version, true, playerIDs, true)
+}
+
+// setSeats records the players seated in a game, who count as having connected before if restored
+func (h *Hub) setSeats(gameID string, version int, active bool, playerIDs []string, restored bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	current := h.seats[gameID]
+	if current != nil && version < current.version {
+		return
+	}
+	if !active {
+		delete(h.seats, gameID)
+		delete(h.disconnected, gameID)
+		return
+	}
+	if current == nil {
+		current = &seating{connected: make(map[string]bool)}
+		h.seats[gameID] = current
+	}
+	current.version = version
+
+	seated := make(map[string]bool, len(playerIDs))
+	for _, playerID := range playerIDs {
+		seated[playerID] = true
+	}
+	for playerID := range current.connected {
+		if !seated[playerID] {
+			delete(current.connected, playerID)
+			delete(h.disconnected[gameID], playerID)
+		}
+	}
+
+	now := time.Now()
+	for playerID := range seated {
+		if _, ok := current.connected[playerID]; ok {
+			continue
+		}
+		connected := h.hasClientID(gameID, playerID)
+		current.connected[playerID] = connected || restored
+		if !connected {
+			h.markDisconnected(gameID, playerID, now)
+		}
 	}
 }
 
 // Run starts the hub
 func (h *Hub) Run() {
+	ticker := time.NewTicker(abandonCheckPeriod)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case client := <-h.Register:
@@ -59,6 +231,9 @@ func (h *Hub) Run() {
 
 		case message := <-h.Broadcast:
 			h.broadcastMessage(message)
+
+		case now := <-ticker.C:
+			h.checkAbandoned(now)
 		}
 	}
 }
@@ -66,16 +241,24 @@ func (h *Hub) Run() {
 // registerClient registers a new client
 func (h *Hub) registerClient(client *Client) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	gameID := client.GetGameID()
 	if h.clients[gameID] == nil {
 		h.clients[gameID] = make(map[*Client]bool)
 	}
 	h.clients[gameID][client] = true
 
+	// Seated player is back, or has connected, before the abandonment threshold
+	if seating := h.seats[gameID]; seating != nil {
+		if _, ok := seating.connected[client.ID]; ok {
+			seating.connected[client.ID] = true
+			delete(h.disconnected[gameID], client.ID)
+		}
+	}
+	playerCount := len(h.clients[gameID])
+	h.mu.Unlock()
+
 	// Send join message
-	joinMsg := NewJoinMessage(client.GetUsername(), gameID, len(h.clients[gameID]))
+	joinMsg := NewJoinMessage(client.GetUsername(), gameID, playerCount)
 	h.broadcastToGame(gameID, joinMsg)
 
 	log.Printf("Client %s joined game %s", client.GetUsername(), gameID)
@@ -84,26 +267,139 @@ func (h *Hub) registerClient(client *Client) {
 // unregisterClient unregisters a client
 func (h *Hub) unregisterClient(client *Client) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	gameID := client.GetGameID()
-	if h.clients[gameID] != nil {
-		if _, ok := h.clients[gameID][client]; ok {
-			delete(h.clients[gameID], client)
-			close(client.Send)
-
-			// Send leave message
-			leaveMsg := NewLeaveMessage(client.GetUsername(), gameID, len(h.clients[gameID]))
-			h.broadcastToGame(gameID, leaveMsg)
-
-			// Remove game if no clients
-			if len(h.clients[gameID]) == 0 {
-				delete(h.clients, gameID)
+	if _, ok := h.clients[gameID][client]; !ok {
+		h.mu.Unlock()
+		return
+	}
+
+	delete(h.clients[gameID], client)
+	close(client.Send)
+
+	// Spectators may come and go, seated players have to come back
+	if _, seated := h.seats[gameID].seated(client.ID); seated && !h.hasClientID(gameID, client.ID) {
+		h.markDisconnected(gameID, client.ID, time.Now())
+	}
+
+	// Remove game if no clients
+	playerCount := len(h.clients[gameID])
+	if playerCount == 0 {
+		delete(h.clients, gameID)
+	}
+	h.mu.Unlock()
+
+	// Send leave message
+	leaveMsg := NewLeaveMessage(client.GetUsername(), gameID, playerCount)
+	h.broadcastToGame(gameID, leaveMsg)
+
+	log.Printf("Client %s left game %s", client.GetUsername(), gameID)
+}
+
+// hasClientID checks if a client with the given ID is still connected to game
+func (h *Hub) hasClientID(gameID, clientID string) bool {
+	for c := range h.clients[gameID] {
+		if c.ID == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// seated reports whether the player connected since they were seated and whether they are seated
+func (s *seating) seated(playerID string) (connected, ok bool) {
+	if s == nil {
+		return false, false
+	}
+	connected, ok = s.connected[playerID]
+	return connected, ok
+}
+
+// markDisconnected records when a seated player was seated or lost their last connection to game
+func (h *Hub) markDisconnected(gameID, clientID string, at time.Time) {
+	if h.disconnected[gameID] == nil {
+		h.disconnected[gameID] = make(map[string]*absence)
+	}
+	h.disconnected[gameID][clientID] = &absence{since: at}
+}
+
+// abandonment - the forfeit of a seated player who stayed away
+type abandonment struct {
+	gameID   string
+	playerID string
+	reason   string
+	absence  *absence
+	// checkedAt is when the forfeit was found, retries are scheduled from it
+	checkedAt time.Time
+}
+
+// checkAbandoned reports seated players away for longer than AbandonTimeout.
+// Players stay away until their forfeit is reported, failed reports are retried with backoff.
+func (h *Hub) checkAbandoned(now time.Time) {
+	h.mu.Lock()
+	var abandoned []abandonment
+	for gameID, players := range h.disconnected {
+		for playerID, away := range players {
+			if away.reporting || now.Sub(away.since) < h.AbandonTimeout || now.Before(away.retryAt) {
+				continue
 			}
+			reason := AbandonReasonTimeout
+			if connected, _ := h.seats[gameID].seated(playerID); connected {
+				reason = AbandonReasonAbandon
+			}
+			away.reporting = true
+			abandoned = append(abandoned, abandonment{gameID, playerID, reason, away, now})
+		}
+	}
+	handler := h.onAbandon
+	h.mu.Unlock()
 
-			log.Printf("Client %s left game %s", client.GetUsername(), gameID)
+	for _, a := range abandoned {
+		if handler == nil {
+			h.abandoned(a)
+			continue
 		}
+		go func(a abandonment) {
+			if err := handler(a.gameID, a.playerID, a.reason); err != nil {
+				h.abandonFailed(a, err)
+				return
+			}
+			h.abandoned(a)
+		}(a)
+	}
+}
+
+// abandoned stops watching a player whose forfeit was reported and tells the game about it
+func (h *Hub) abandoned(a abandonment) {
+	h.mu.Lock()
+	// The player may have come back or left their seat while the forfeit was reported
+	if players := h.disconnected[a.gameID]; players[a.playerID] == a.absence {
+		delete(players, a.playerID)
+		if len(players) == 0 {
+			delete(h.disconnected, a.gameID)
+		}
+	}
+	h.mu.Unlock()
+
+	log.Printf("Player %s forfeits game %s: %s", a.playerID, a.gameID, a.reason)
+
+	content := "A player abandoned the game"
+	if a.reason == AbandonReasonTimeout {
+		content = "A player did not connect in time"
 	}
+	h.broadcastToGame(a.gameID, NewSystemMessage(a.reason, content, a.gameID))
+}
+
+// abandonFailed schedules the next report of a forfeit the AbandonHandler failed to take
+func (h *Hub) abandonFailed(a abandonment, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	a.absence.reporting = false
+	a.absence.failures++
+	delay := min(abandonRetryDelay<<min(a.absence.failures-1, 5), maxAbandonRetryDelay)
+	a.absence.retryAt = a.checkedAt.Add(delay)
+
+	log.Printf("Error reporting forfeit of player %s in game %s, retrying in %s: %v", a.playerID, a.gameID, delay, err)
 }
 
 // broadcastMessage sends message to all clients in game
@@ -113,6 +409,11 @@ func (h *Hub) broadcastMessage(message *Message) {
 
 // broadcastToGame sends message to all clients in specific game
 func (h *Hub) broadcastToGame(gameID string, message interface{}) {
+	h.broadcastToGameExcept(gameID, message, nil)
+}
+
+// broadcastToGameExcept sends message to the clients in game for which skip is false
+func (h *Hub) broadcastToGameExcept(gameID string, message interface{}, skip func(*Client) bool) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
@@ -128,13 +429,14 @@ func (h *Hub) broadcastToGame(gameID string, message interface{}) {
 		return
 	}
 
-	// Send message to all clients in game
+	// Send message to all clients in game, skipping those with a full send buffer
 	for client := range clients {
+		if skip != nil && skip(client) {
+			continue
+		}
 		select {
 		case client.Send <- jsonData:
 		default:
-			close(client.Send)
-			delete(clients, client)
 		}
 	}
 }
@@ -162,6 +464,8 @@ func (h *Hub) HandleMessage(client *Client, message []byte) {
 		h.handleChatMessage(client, &msg)
 	case MessageTypeGameMove:
 		h.handleGameMoveMessage(client, &msg)
+	case MessageTypeTakeback:
+		h.handleTakebackMessage(client, &msg)
 	case MessageTypeJoin:
 		h.handleJoinMessage(client, &msg)
 	case MessageTypeLeave:
@@ -188,9 +492,39 @@ func (h *Hub) handleChatMessage(client *Client, msg *Message) {
 
 	// Create chat message
 	chatMsg := NewChatMessage(msg.Content, client.GetUsername(), client.GetGameID(), false)
-	
+
+	h.mu.RLock()
+	check, onChat := h.blocks, h.onChat
+	h.mu.RUnlock()
+
+	var skip func(*Client) bool
+	if check != nil {
+		// Users who blocked the sender, or whom the sender blocked, do not see the message
+		blocked, err := check(client.ID)
+		if err != nil {
+			log.Printf("Error checking blocks of %s: %v", client.ID, err)
+			errorMsg := NewErrorMessage("CHAT_UNAVAILABLE", "Message could not be delivered", client.GetGameID())
+			if jsonData, err := errorMsg.ToJSON(); err == nil {
+				client.SendMessage(jsonData)
+			}
+			return
+		}
+
+		hidden := make(map[string]bool, len(blocked))
+		for _, id := range blocked {
+			hidden[id] = true
+		}
+		skip = func(recipient *Client) bool {
+			return hidden[recipient.ID]
+		}
+	}
+
 	// Send message to all clients in game
-	h.broadcastToGame(client.GetGameID(), chatMsg)
+	h.broadcastToGameExcept(client.GetGameID(), chatMsg, skip)
+
+	if onChat != nil {
+		onChat(client.GetGameID(), client.ID, chatMsg.ID)
+	}
 }
 
 // handleGameMoveMessage processes game move messages
@@ -200,6 +534,35 @@ func (h *Hub) handleGameMoveMessage(client *Client, msg *Message) {
 	h.broadcastToGame(client.GetGameID(), gameMoveMsg)
 }
 
+// handleTakebackMessage forwards takeback actions to the game and announces them
+func (h *Hub) handleTakebackMessage(client *Client, msg *Message) {
+	announcement, ok := takebackMessages[msg.Content]
+	if !ok {
+		errorMsg := NewErrorMessage("INVALID_TAKEBACK", "Unknown takeback action", client.GetGameID())
+		if jsonData, err := errorMsg.ToJSON(); err == nil {
+			client.SendMessage(jsonData)
+		}
+		return
+	}
+
+	h.mu.RLock()
+	handler := h.onTakeback
+	h.mu.RUnlock()
+
+	if handler != nil {
+		if err := handler(client.GetGameID(), client.ID, msg.Content); err != nil {
+			errorMsg := NewErrorMessage("TAKEBACK_REJECTED", err.Error(), client.GetGameID())
+			if jsonData, err := errorMsg.ToJSON(); err == nil {
+				client.SendMessage(jsonData)
+			}
+			return
+		}
+	}
+
+	takebackMsg := NewSystemMessage("takeback_"+msg.Content, announcement, client.GetGameID())
+	h.broadcastToGame(client.GetGameID(), takebackMsg)
+}
+
 // handleJoinMessage processes join messages
 func (h *Hub) handleJoinMessage(client *Client, msg *Message) {
 	h.mu.RLock()
@@ -231,6 +594,57 @@ func (h *Hub) GetClientCount(gameID string) int {
 	return 0
 }
 
+// IsOnline checks if the user has a connection to any game
+func (h *Hub) IsOnline(userID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for gameID := range h.clients {
+		if h.hasClientID(gameID, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// SendToUser sends message to all connections of the user and returns their number
+func (h *Hub) SendToUser(userID string, message interface{}) int {
+	return h.sendTo(message, func(client *Client) bool { return client.ID == userID })
+}
+
+// SendToAll sends message to every connection of every game and returns their number
+func (h *Hub) SendToAll(message interface{}) int {
+	return h.sendTo(message, func(*Client) bool { return true })
+}
+
+// sendTo sends message to the connections for which match is true and returns their number.
+// Connections with a full send buffer are skipped.
+func (h *Hub) sendTo(message interface{}, match func(*Client) bool) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return 0
+	}
+
+	sent := 0
+	for _, clients := range h.clients {
+		for client := range clients {
+			if !match(client) {
+				continue
+			}
+			select {
+			case client.Send <- jsonData:
+				sent++
+			default:
+			}
+		}
+	}
+	return sent
+}
+
 // GetActiveGames returns list of active games
 func (h *Hub) GetActiveGames() []string {
 	h.mu.RLock()
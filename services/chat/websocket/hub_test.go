@@ -0,0 +1,96 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// awayPlayer returns the absence of a seated player, nil if the hub no longer watches them
+func awayPlayer(h *Hub, gameID, playerID string) *absence {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if away := h.disconnected[gameID][playerID]; away != nil {
+		copied := *away
+		return &copied
+	}
+	return nil
+}
+
+// waitFor polls until done reports true, failing the test after a second
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !done(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestCheckAbandonedRetriesFailedForfeits(t *testing.T) {
+	hub := NewHub()
+	calls := make(chan string, 3)
+	failures := 1
+	hub.SetAbandonHandler(func(gameID, playerID, reason string) error {
+		calls <- reason
+		if failures > 0 {
+			failures--
+			return errors.New("game service unavailable")
+		}
+		return nil
+	})
+
+	seatedAt := time.Now()
+	hub.SetSeats("game", 1, true, []string{"1"})
+	expired := seatedAt.Add(hub.AbandonTimeout + time.Second)
+
+	hub.checkAbandoned(expired)
+	if reason := <-calls; reason != AbandonReasonTimeout {
+		t.Fatalf("forfeit reported with reason %q, want %q", reason, AbandonReasonTimeout)
+	}
+	waitFor(t, "the failure to be recorded", func() bool {
+		away := awayPlayer(hub, "game", "1")
+		return away != nil && away.failures == 1 && !away.reporting
+	})
+
+	// Not reported again before the retry delay
+	hub.checkAbandoned(expired.Add(abandonRetryDelay / 2))
+	select {
+	case <-calls:
+		t.Fatal("failed forfeit reported again before the retry delay")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	hub.checkAbandoned(expired.Add(abandonRetryDelay))
+	if reason := <-calls; reason != AbandonReasonTimeout {
+		t.Fatalf("retried forfeit reported with reason %q", reason)
+	}
+	waitFor(t, "the player to be released", func() bool { return awayPlayer(hub, "game", "1") == nil })
+
+	hub.checkAbandoned(expired.Add(time.Hour))
+	select {
+	case <-calls:
+		t.Fatal("forfeit reported again after the game service took it")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestRestoredSeatsAbandonTheGame(t *testing.T) {
+	hub := NewHub()
+	calls := make(chan string, 1)
+	hub.SetAbandonHandler(func(gameID, playerID, reason string) error {
+		calls <- reason
+		return nil
+	})
+
+	restoredAt := time.Now()
+	hub.RestoreSeats("game", 3, []string{"1"})
+	// Reports older than the restored seats are ignored
+	hub.SetSeats("game", 2, false, nil)
+
+	hub.checkAbandoned(restoredAt.Add(hub.AbandonTimeout + time.Second))
+	if reason := <-calls; reason != AbandonReasonAbandon {
+		t.Fatalf("forfeit reported with reason %q, want %q", reason, AbandonReasonAbandon)
+	}
+}
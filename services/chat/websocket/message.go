@@ -3,6 +3,8 @@ package websocket
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // MessageType message type
@@ -13,6 +15,7 @@ const (
 	MessageTypeJoin     MessageType = "join"
 	MessageTypeLeave    MessageType = "leave"
 	MessageTypeGameMove MessageType = "game_move"
+	MessageTypeTakeback MessageType = "takeback"
 	MessageTypeSystem   MessageType = "system"
 	MessageTypeError    MessageType = "error"
 )
@@ -56,13 +59,15 @@ func FromJSON(data []byte) (*Message, error) {
 // ChatMessage structure for chat messages
 type ChatMessage struct {
 	Message
-	IsPrivate bool `json:"is_private"`
+	ID        string `json:"id"` // unique per chat message
+	IsPrivate bool   `json:"is_private"`
 }
 
 // NewChatMessage creates a new chat message
 func NewChatMessage(content, username, gameID string, isPrivate bool) *ChatMessage {
 	return &ChatMessage{
 		Message:   *NewMessage(MessageTypeChat, content, username, gameID),
+		ID:        uuid.NewString(),
 		IsPrivate: isPrivate,
 	}
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	"game-service/domain"
+	"game-service/repository"
+)
+
+// chatSeatQueueSize bounds the seat updates waiting for delivery; further updates are dropped
+const chatSeatQueueSize = 256
+
+// seatsRequest tells the chat service which players are seated in a game
+type seatsRequest struct {
+	Active    bool     `json:"active"`
+	Version   int      `json:"version"`
+	PlayerIDs []string `json:"player_ids"`
+}
+
+// chatServiceSeats returns a function reporting the seated players of a game to the chat service,
+// which forfeits those of active games who do not connect or stay away. Updates are sent one at a time
+// in the background with the state of the game when they are sent, so a slow chat service never delays games.
+func chatServiceSeats(chatServiceURL, serviceSecret string, games repository.GameRepository) func(gameID string) {
+	client := middleware.ServiceClient(serviceSecret, 5*time.Second)
+	queue := make(chan string, chatSeatQueueSize)
+
+	go func() {
+		for gameID := range queue {
+			if err := reportSeats(client, chatServiceURL, games, gameID); err != nil {
+				log.Printf("Error reporting seats of game %s: %v", gameID, err)
+			}
+		}
+	}()
+
+	return func(gameID string) {
+		select {
+		case queue <- gameID:
+		default:
+			log.Printf("Seat queue full, dropping update of game %s", gameID)
+		}
+	}
+}
+
+// reportSeats sends the players of the game who are still in it to the chat service
+func reportSeats(client *http.Client, chatServiceURL string, games repository.GameRepository, gameID string) error {
+	game, err := games.FindByID(gameID)
+	if err != nil {
+		return err
+	}
+
+	seats := seatsRequest{Active: game.Status == domain.GameStatusActive, Version: game.Version, PlayerIDs: game.SeatedPlayerIDs()}
+	body, err := json.Marshal(seats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal seats: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/internal/games/%s/seats", chatServiceURL, gameID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("chat service rejected seats: %s", resp.Status)
+	}
+	return nil
+}
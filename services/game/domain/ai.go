@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// AIDifficulty - strength of the computer opponent of a solo game
+type AIDifficulty string
+
+const (
+	AIEasy   AIDifficulty = "easy"
+	AIMedium AIDifficulty = "medium"
+	AIHard   AIDifficulty = "hard"
+)
+
+const (
+	// AIPlayerID - player ID of the computer opponent.
+	// It is no user ID, so games against the AI are never rated.
+	AIPlayerID = "0"
+	// AIUsername - name the computer opponent plays under
+	AIUsername = "AI"
+	// AIAnalysisNodes - search budget of every move the AI thinks about
+	AIAnalysisNodes = 200_000
+)
+
+// Valid reports whether the difficulty is known
+func (d AIDifficulty) Valid() bool {
+	switch d {
+	case AIEasy, AIMedium, AIHard:
+		return true
+	}
+	return false
+}
+
+// mistakeRate returns the share of moves played at random instead of a best one
+func (d AIDifficulty) mistakeRate() float64 {
+	switch d {
+	case AIEasy:
+		return 1
+	case AIMedium:
+		return 0.3
+	}
+	return 0
+}
+
+// IsAI reports whether the player is the computer opponent
+func (p *Player) IsAI() bool {
+	return p.ID == AIPlayerID
+}
+
+// AIPlayer returns the computer opponent of a solo game, nil in games between people
+func (g *Game) AIPlayer() *Player {
+	if g.Settings.AI == "" {
+		return nil
+	}
+	return g.PlayerByID(AIPlayerID)
+}
+
+// chooseAIMove picks the move the computer opponent plays in the current position
+func (g *Game) chooseAIMove() (int, error) {
+	legal := g.LegalPositions()
+	if len(legal) == 0 {
+		return 0, errors.New("no legal move left for the AI")
+	}
+
+	if rand.Float64() < g.Settings.AI.mistakeRate() {
+		return legal[rand.Intn(len(legal))], nil
+	}
+
+	pos, err := g.positionAfter(len(g.Moves), g.CurrentTurn.Symbol)
+	if err != nil {
+		return 0, err
+	}
+	analysis, err := Analyze(pos, AIAnalysisNodes)
+	if err != nil {
+		return 0, err
+	}
+
+	best := analysis.BestMoves()
+	return best[rand.Intn(len(best))], nil
+}
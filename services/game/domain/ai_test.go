@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+// newAIGame creates a classic game of player 1 against the AI
+func newAIGame(t *testing.T, difficulty AIDifficulty) (*GameService, *Game) {
+	t.Helper()
+	settings := DefaultGameSettings()
+	settings.AI = difficulty
+
+	service := NewGameService()
+	game, err := service.CreateGameWithSettings(NewPlayer("1", "alice", ""), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.Status != GameStatusActive || game.AIPlayer() == nil {
+		t.Fatalf("game against the AI is %s with players %v", game.Status, game.Players)
+	}
+	return service, game
+}
+
+func TestHardAIRepliesWithBestMoves(t *testing.T) {
+	service, game := newAIGame(t, AIHard)
+	player := game.PlayerByID("1")
+
+	if err := service.MakeMove(game, player, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Against a corner opening every reply but the center loses
+	if len(game.Moves) != 2 || game.LastMove.PlayerID != AIPlayerID || game.LastMove.Position != 4 {
+		t.Fatalf("AI replied with %v", game.LastMove)
+	}
+
+	if err := service.MakeMove(game, player, 1); err != nil {
+		t.Fatal(err)
+	}
+	if game.LastMove.Position != 2 {
+		t.Fatalf("AI did not block the row, replied with %d", game.LastMove.Position)
+	}
+	if game.CurrentTurn.ID != "1" {
+		t.Fatalf("turn of %s after the AI replied", game.CurrentTurn.ID)
+	}
+}
+
+func TestTakebacksAgainstTheAIAreUnlimited(t *testing.T) {
+	service, game := newAIGame(t, AIEasy)
+	player := game.PlayerByID("1")
+
+	for i := 0; i <= DefaultTakebackLimit; i++ {
+		if err := service.MakeMove(game, player, 4); err != nil {
+			t.Fatal(err)
+		}
+		if err := service.RequestTakeback(game, player); err != nil {
+			t.Fatalf("takeback %d: %v", i+1, err)
+		}
+		// The AI accepts at once, taking back its reply as well
+		if len(game.Moves) != 0 || game.CurrentTurn.ID != "1" || game.TakebackRequestedBy != nil {
+			t.Fatalf("after takeback %d: %d moves, turn of %s", i+1, len(game.Moves), game.CurrentTurn.ID)
+		}
+	}
+
+	if game.Settings.TakebackLimit != UnlimitedTakebacks || game.TakebacksUsed["1"] != DefaultTakebackLimit+1 {
+		t.Fatalf("takeback limit %d with %d used", game.Settings.TakebackLimit, game.TakebacksUsed["1"])
+	}
+	if ids := game.SeatedPlayerIDs(); !slices.Equal(ids, []string{"1"}) {
+		t.Fatalf("seated players %v, want the AI left out", ids)
+	}
+}
+
+func TestAIRejectsUnsupportedSettings(t *testing.T) {
+	for _, variant := range []Variant{VariantMisere, VariantWild, VariantUltimate} {
+		settings := DefaultGameSettings()
+		settings.Variant = variant
+		settings.AI = AIMedium
+		if err := settings.Validate(); err == nil {
+			t.Errorf("%s game against the AI accepted", variant)
+		}
+	}
+
+	settings := DefaultGameSettings()
+	settings.AI = "grandmaster"
+	if err := settings.Validate(); err == nil {
+		t.Error("unknown difficulty accepted")
+	}
+}
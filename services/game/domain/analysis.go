@@ -0,0 +1,710 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// Outcome - game-theoretic value of a position for the side to move
+type Outcome string
+
+const (
+	OutcomeWin  Outcome = "win"
+	OutcomeDraw Outcome = "draw"
+	OutcomeLoss Outcome = "loss"
+	// OutcomeUnknown is reported when the search ran out of nodes before solving the position
+	OutcomeUnknown Outcome = "unknown"
+)
+
+// Annotation - verdict on a played move compared to perfect play
+type Annotation string
+
+const (
+	AnnotationBest      Annotation = "best"
+	AnnotationGood      Annotation = "good"
+	AnnotationMissedWin Annotation = "missed_win"
+	AnnotationBlunder   Annotation = "blunder"
+	AnnotationUnknown   Annotation = "unknown"
+)
+
+const (
+	// DefaultAnalysisNodes - search budget of a single analysis
+	DefaultAnalysisNodes = 1_000_000
+	// MaxAnalysisNodes - largest search budget a client may ask for
+	MaxAnalysisNodes = 10_000_000
+	// DefaultAnnotationNodes - search budget of every position of an annotated game
+	DefaultAnnotationNodes = 100_000
+)
+
+const (
+	// winScore is the score of winning right now; wins further away score one less per ply
+	winScore = 1 << 30
+	// forcedScore - scores beyond it are forced wins or losses
+	forcedScore = winScore - maxCells - 1
+)
+
+var (
+	ErrAnalysisUnsupported = errors.New("analysis is only available for two-player games without special rules")
+	ErrPositionDecided     = errors.New("position is already decided")
+)
+
+// Evaluation - value of a position or move for the side to move
+type Evaluation struct {
+	Outcome Outcome `json:"outcome"`
+	// Distance is the number of plies until the game ends under perfect play
+	Distance int `json:"distance,omitempty"`
+	// Heuristic scores unsolved positions, positive when the side to move stands better
+	Heuristic int `json:"heuristic,omitempty"`
+}
+
+// MoveEvaluation - evaluation of one legal move
+type MoveEvaluation struct {
+	Position           int   `json:"position"`
+	Row                int   `json:"row"`
+	Col                int   `json:"col"`
+	PrincipalVariation []int `json:"principal_variation"`
+	Evaluation
+}
+
+// Analysis - result of analyzing a position
+type Analysis struct {
+	ToMove     string     `json:"to_move"`
+	Evaluation Evaluation `json:"evaluation"`
+	// Moves are ranked from best to worst
+	Moves              []MoveEvaluation `json:"moves"`
+	PrincipalVariation []int            `json:"principal_variation"`
+	Depth              int              `json:"depth"`
+	Nodes              int              `json:"nodes"`
+}
+
+// MoveAnnotation - played move of a stored game compared to the best moves.
+// Ply is the number of moves played before it.
+type MoveAnnotation struct {
+	Ply        int        `json:"ply"`
+	Move       Move       `json:"move"`
+	Annotation Annotation `json:"annotation"`
+	Before     Evaluation `json:"before"`
+	Played     Evaluation `json:"played"`
+	BestMoves  []int      `json:"best_moves"`
+}
+
+// AnalysisPosition - two-player position of a K-in-a-row game to analyze
+type AnalysisPosition struct {
+	Board    *Board
+	ToMove   string
+	Opponent string
+	// WinLength is the K of K-in-a-row
+	WinLength int
+	// Exact positions do not count lines longer than WinLength
+	Exact   bool
+	Gravity bool
+}
+
+// NewAnalysisPosition validates a board given as rows of cells and the side to move.
+// The opponent plays the other symbol on the board, or the default opposite of toMove.
+func NewAnalysisPosition(cells [][]string, toMove string, winLength int, gravity bool) (*AnalysisPosition, error) {
+	size := len(cells)
+	if size < 3 || size > MaxBoardSize {
+		return nil, fmt.Errorf("board must have between 3 and %d rows", MaxBoardSize)
+	}
+
+	if !ValidSymbol(toMove) {
+		return nil, errors.New("side to move must be a single character")
+	}
+
+	if winLength == 0 {
+		winLength = min(size, GomokuWinLength)
+	}
+	if winLength < 3 || winLength > size {
+		return nil, fmt.Errorf("k must be between 3 and %d", size)
+	}
+
+	pos := &AnalysisPosition{
+		Board:     NewBoardOfSize(size),
+		ToMove:    toMove,
+		WinLength: winLength,
+		Gravity:   gravity,
+	}
+	for row, cols := range cells {
+		if len(cols) != size {
+			return nil, errors.New("board must be square")
+		}
+		for col, cell := range cols {
+			if cell == "" {
+				continue
+			}
+			if cell != toMove {
+				if pos.Opponent != "" && cell != pos.Opponent {
+					return nil, ErrAnalysisUnsupported
+				}
+				pos.Opponent = cell
+			}
+			if err := pos.Board.MakeMove(row*size+col, cell); err != nil {
+				return nil, fmt.Errorf("cell %d,%d: %w", row, col, err)
+			}
+		}
+	}
+
+	if pos.Opponent == "" {
+		pos.Opponent = oppositeSymbol(toMove)
+	}
+
+	if gravity && !pos.settled() {
+		return nil, errors.New("pieces must rest on the bottom row or on another piece")
+	}
+	return pos, nil
+}
+
+// oppositeSymbol returns the first default symbol other than symbol
+func oppositeSymbol(symbol string) string {
+	for _, s := range DefaultSymbols {
+		if s != symbol {
+			return s
+		}
+	}
+	return ""
+}
+
+// settled checks that no piece floats above an empty cell
+func (p *AnalysisPosition) settled() bool {
+	size := p.Board.Size()
+	for position := 0; position < size*(size-1); position++ {
+		if p.Board.CellAt(position) != "" && p.Board.CellAt(position+size) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// PositionBefore returns the position of the game before the move of ply was played
+func (g *Game) PositionBefore(ply int) (*AnalysisPosition, error) {
+	if _, _, ok := g.Settings.lineRules(); !ok || len(g.Players) != 2 {
+		return nil, ErrAnalysisUnsupported
+	}
+
+	if ply < 0 || ply >= len(g.Moves) {
+		return nil, errors.New("no move at this ply")
+	}
+
+	return g.positionAfter(ply, g.Moves[ply].Symbol)
+}
+
+// positionAfter returns the position of the game after ply moves with toMove to play
+func (g *Game) positionAfter(ply int, toMove string) (*AnalysisPosition, error) {
+	length, exact, ok := g.Settings.lineRules()
+	if !ok || len(g.Players) != 2 {
+		return nil, ErrAnalysisUnsupported
+	}
+
+	board := g.rules().NewBoard()
+	for _, move := range g.Moves[:ply] {
+		if err := board.MakeMove(move.Position, move.Symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	opponent := g.Players[0].Symbol
+	if opponent == toMove {
+		opponent = g.Players[1].Symbol
+	}
+	return &AnalysisPosition{
+		Board:     board,
+		ToMove:    toMove,
+		Opponent:  opponent,
+		WinLength: length,
+		Exact:     exact,
+		Gravity:   g.Settings.Gravity,
+	}, nil
+}
+
+// lineRules returns line length and exactness of variants that are plain K-in-a-row games
+func (s GameSettings) lineRules() (length int, exact, ok bool) {
+	if s.PlayerCount() != 2 {
+		return 0, false, false
+	}
+
+	switch s.Variant {
+	case "", VariantClassic, VariantConnect, VariantGomokuFreestyle:
+		return s.lineLength(), false, true
+	case VariantGomoku:
+		return s.lineLength(), true, true
+	}
+	return 0, false, false
+}
+
+// Analyze solves the position with iterative deepening until every move is proven
+// or the search spends maxNodes. Unproven moves are ranked by a heuristic.
+func Analyze(pos *AnalysisPosition, maxNodes int) (*Analysis, error) {
+	if maxNodes <= 0 {
+		maxNodes = DefaultAnalysisNodes
+	}
+
+	s := newSearcher(pos)
+	if s.decided() {
+		return nil, ErrPositionDecided
+	}
+
+	analysis := &Analysis{
+		ToMove:     pos.ToMove,
+		Evaluation: Evaluation{Outcome: OutcomeDraw},
+	}
+
+	moves := append([]int(nil), s.candidates(0)...)
+	if len(moves) == 0 {
+		return analysis, nil
+	}
+
+	var scores map[int]int
+	search := func(depth, budget int) bool {
+		iteration, ok := s.searchRoot(moves, depth, budget)
+		if !ok {
+			return false
+		}
+
+		scores, analysis.Depth = iteration, depth
+		sort.SliceStable(moves, func(i, j int) bool {
+			return scores[moves[i]] > scores[moves[j]]
+		})
+		return !s.allForced(scores)
+	}
+
+	// The first search always completes so that every move has a score.
+	// Solving outright often costs less than deepening step by step, as only proven
+	// results prune then, so half of the budget goes to that before deepening.
+	empties := s.empties()
+	if search(1, math.MaxInt) && empties > 1 && !search(empties, maxNodes/2) && analysis.Depth < empties {
+		for depth := 2; depth < empties && search(depth, maxNodes); depth++ {
+		}
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		if scores[moves[i]] != scores[moves[j]] {
+			return scores[moves[i]] > scores[moves[j]]
+		}
+		return moves[i] < moves[j]
+	})
+
+	size := s.board.Size()
+	solved := analysis.Depth == empties
+	for _, move := range moves {
+		analysis.Moves = append(analysis.Moves, MoveEvaluation{
+			Position:           move,
+			Row:                move / size,
+			Col:                move % size,
+			PrincipalVariation: s.principalVariation(move),
+			Evaluation:         evaluation(scores[move], solved),
+		})
+	}
+
+	analysis.Evaluation = analysis.Moves[0].Evaluation
+	analysis.PrincipalVariation = analysis.Moves[0].PrincipalVariation
+	analysis.Nodes = s.nodes
+	return analysis, nil
+}
+
+// AnnotateMove compares the move played in a position with the analysis of that position
+func AnnotateMove(analysis *Analysis, position int) (Evaluation, Annotation) {
+	var played *MoveEvaluation
+	for i := range analysis.Moves {
+		if analysis.Moves[i].Position == position {
+			played = &analysis.Moves[i]
+			break
+		}
+	}
+	if played == nil {
+		return Evaluation{Outcome: OutcomeUnknown}, AnnotationUnknown
+	}
+
+	before := analysis.Evaluation
+	switch {
+	case played.Evaluation == before:
+		return played.Evaluation, AnnotationBest
+	case before.Outcome == OutcomeUnknown || played.Outcome == OutcomeUnknown:
+		return played.Evaluation, AnnotationUnknown
+	case played.Outcome == OutcomeLoss && before.Outcome != OutcomeLoss:
+		return played.Evaluation, AnnotationBlunder
+	case before.Outcome == OutcomeWin && played.Outcome != OutcomeWin:
+		return played.Evaluation, AnnotationMissedWin
+	}
+	return played.Evaluation, AnnotationGood
+}
+
+// BestMoves returns the positions of the moves sharing the best evaluation
+func (a *Analysis) BestMoves() []int {
+	var best []int
+	for _, move := range a.Moves {
+		if move.Evaluation != a.Evaluation {
+			break
+		}
+		best = append(best, move.Position)
+	}
+	return best
+}
+
+// evaluation converts a search score; solved searches prove every non-forced score a draw
+func evaluation(score int, solved bool) Evaluation {
+	switch {
+	case score > forcedScore:
+		return Evaluation{Outcome: OutcomeWin, Distance: winScore - score}
+	case score < -forcedScore:
+		return Evaluation{Outcome: OutcomeLoss, Distance: winScore + score}
+	case solved:
+		return Evaluation{Outcome: OutcomeDraw}
+	}
+	return Evaluation{Outcome: OutcomeUnknown, Heuristic: score}
+}
+
+// Transposition table bounds
+const (
+	boundExact uint8 = iota
+	boundLower
+	boundUpper
+)
+
+// transposition - search result of a position
+type transposition struct {
+	depth int
+	score int
+	bound uint8
+	best  int
+}
+
+// searcher runs a negamax search with alpha-beta pruning and a transposition table
+type searcher struct {
+	board   *Board
+	symbols [2]string // side to move at the root and its opponent
+	slots   [2]int
+	length  int
+	exact   bool
+	gravity bool
+
+	// order lists cells from the center outwards
+	order []int
+	// moves holds a move buffer per ply
+	moves [][]int
+	table map[uint64]transposition
+
+	// nodes counts visited positions; searches abort beyond budget
+	nodes, budget int
+	aborted       bool
+}
+
+// newSearcher prepares a search of pos
+func newSearcher(pos *AnalysisPosition) *searcher {
+	s := &searcher{
+		board:   pos.Board,
+		symbols: [2]string{pos.ToMove, pos.Opponent},
+		length:  pos.WinLength,
+		exact:   pos.Exact,
+		gravity: pos.Gravity,
+		table:   make(map[uint64]transposition),
+	}
+	s.slots = [2]int{s.board.slotOf(pos.ToMove), s.board.slotOf(pos.Opponent)}
+
+	size := s.board.Size()
+	center := size - 1
+	distance := func(position int) int {
+		row, col := 2*(position/size)-center, 2*(position%size)-center
+		return max(row, -row) + max(col, -col)
+	}
+	for position := 0; position < size*size; position++ {
+		s.order = append(s.order, position)
+	}
+	sort.SliceStable(s.order, func(i, j int) bool {
+		return distance(s.order[i]) < distance(s.order[j])
+	})
+
+	s.moves = make([][]int, s.empties()+1)
+	for ply := range s.moves {
+		s.moves[ply] = make([]int, 0, size*size)
+	}
+	return s
+}
+
+// empties returns the number of free cells
+func (s *searcher) empties() int {
+	size := s.board.Size()
+	return size*size - s.board.filled
+}
+
+// candidates returns the legal moves of a ply in the buffer of that ply
+func (s *searcher) candidates(ply int) []int {
+	moves := s.moves[ply][:0]
+	if !s.gravity {
+		for _, position := range s.order {
+			if s.board.IsValidPosition(position) {
+				moves = append(moves, position)
+			}
+		}
+		return moves
+	}
+
+	size := s.board.Size()
+	for _, position := range s.order[:size*size] {
+		if position/size != size-1 {
+			continue
+		}
+		if drop, err := s.board.DropPosition(position % size); err == nil {
+			moves = append(moves, drop)
+		}
+	}
+	return moves
+}
+
+// decided checks if a line has already been completed
+func (s *searcher) decided() bool {
+	size := s.board.Size()
+	for position := 0; position < size*size; position++ {
+		if s.board.CompletesLine(position, s.length, s.exact) {
+			return true
+		}
+	}
+	return false
+}
+
+// wins checks if side completes a line by playing the empty position
+func (s *searcher) wins(position, side int) bool {
+	return s.board.completesLineWith(position, s.slots[side], s.length, s.exact)
+}
+
+// playable checks if a move may be placed on the empty position
+func (s *searcher) playable(position int) bool {
+	size := s.board.Size()
+	return !s.gravity || position+size >= size*size || s.board.CellAt(position+size) != ""
+}
+
+// allForced checks if every move has been proven a win or a loss
+func (s *searcher) allForced(scores map[int]int) bool {
+	for _, score := range scores {
+		if score >= -forcedScore && score <= forcedScore {
+			return false
+		}
+	}
+	return true
+}
+
+// searchRoot scores every root move to depth; ok is false if the search visited more than budget nodes
+func (s *searcher) searchRoot(moves []int, depth, budget int) (scores map[int]int, ok bool) {
+	s.budget, s.aborted = budget, false
+	scores = make(map[int]int, len(moves))
+	for _, move := range moves {
+		if s.wins(move, 0) {
+			scores[move] = winScore - 1
+			continue
+		}
+
+		s.board.MakeMove(move, s.symbols[0])
+		scores[move] = -s.negamax(1, depth-1, -winScore, winScore, 1)
+		s.board.Undo(move)
+		if s.aborted {
+			return nil, false
+		}
+	}
+	return scores, true
+}
+
+// negamax scores the position for side. Forced results count plies from the root.
+func (s *searcher) negamax(ply, depth, alpha, beta, side int) int {
+	s.nodes++
+	if s.nodes > s.budget {
+		s.aborted = true
+		return 0
+	}
+
+	moves := s.candidates(ply)
+	if len(moves) == 0 {
+		return 0
+	}
+
+	lines := s.scan(side)
+	switch {
+	case lines.win >= 0:
+		return winScore - ply - 1
+	case lines.threats > 1:
+		// The opponent's immediate wins must be blocked; two of them cannot be
+		return -(winScore - ply - 2)
+	case lines.dead:
+		return 0
+	case lines.threats == 1:
+		moves = append(moves[:0], lines.threat)
+	}
+
+	if depth == 0 {
+		return lines.heuristic
+	}
+
+	alphaOrig := alpha
+	hash := s.board.Hash()
+	if entry, ok := s.table[hash]; ok {
+		score := fromTable(entry.score, ply)
+		if entry.depth >= depth {
+			switch entry.bound {
+			case boundExact:
+				return score
+			case boundLower:
+				alpha = max(alpha, score)
+			case boundUpper:
+				beta = min(beta, score)
+			}
+			if alpha >= beta {
+				return score
+			}
+		}
+		for i, move := range moves {
+			if move == entry.best {
+				moves[0], moves[i] = moves[i], moves[0]
+				break
+			}
+		}
+	}
+
+	bestScore, best := -winScore, -1
+	for _, move := range moves {
+		s.board.MakeMove(move, s.symbols[side])
+		score := -s.negamax(ply+1, depth-1, -beta, -alpha, 1-side)
+		s.board.Undo(move)
+		if s.aborted {
+			return 0
+		}
+
+		if score > bestScore {
+			bestScore, best = score, move
+		}
+		alpha = max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	bound := boundExact
+	if bestScore <= alphaOrig {
+		bound = boundUpper
+	} else if bestScore >= beta {
+		bound = boundLower
+	}
+	s.table[hash] = transposition{depth: depth, score: toTable(bestScore, ply), bound: bound, best: best}
+	return bestScore
+}
+
+// toTable makes forced scores relative to the position so that transpositions reached at other plies can share them
+func toTable(score, ply int) int {
+	switch {
+	case score > forcedScore:
+		return score + ply
+	case score < -forcedScore:
+		return score - ply
+	}
+	return score
+}
+
+// fromTable converts a stored score back to plies from the root
+func fromTable(score, ply int) int {
+	switch {
+	case score > forcedScore:
+		return score - ply
+	case score < -forcedScore:
+		return score + ply
+	}
+	return score
+}
+
+// lineScan - what the open lines of a position mean for the side to move
+type lineScan struct {
+	// win is a move completing a line, -1 if there is none
+	win int
+	// threat is a move completing a line of the opponent, threats counts up to two of them
+	threat, threats int
+	// heuristic scores lines still open to only one side, favoring lines closer to completion
+	heuristic int
+	// dead positions have no line left that either side could complete
+	dead bool
+}
+
+// scan inspects every line of the board once for side
+func (s *searcher) scan(side int) lineScan {
+	mine, theirs := &s.board.bits[s.slots[side]], &s.board.bits[s.slots[1-side]]
+	table := s.board.masks(s.length)
+	result := lineScan{win: -1, threat: -1, dead: true}
+	for i := range table.masks {
+		mask := &table.masks[i]
+		own, other := mask.count(mine, theirs)
+		switch {
+		case own > 0 && other > 0:
+			continue
+		case other == 0:
+			result.heuristic += own * own
+			if own == s.length-1 && result.win < 0 {
+				result.win = s.completion(mask, mine, side)
+			}
+		default:
+			result.heuristic -= other * other
+			if other == s.length-1 && result.threats < 2 {
+				if threat := s.completion(mask, theirs, 1-side); threat >= 0 && threat != result.threat {
+					result.threat = threat
+					result.threats++
+				}
+			}
+		}
+		result.dead = false
+	}
+	return result
+}
+
+// completion returns the missing cell of a line one short of completion if side may complete it there, or -1
+func (s *searcher) completion(mask *winMask, pieces *bitset, side int) int {
+	for w := mask.lo; w <= mask.hi; w++ {
+		if missing := mask.bits[w] &^ pieces[w]; missing != 0 {
+			position := w<<6 + bits.TrailingZeros64(missing)
+			if s.playable(position) && s.wins(position, side) {
+				return position
+			}
+			return -1
+		}
+	}
+	return -1
+}
+
+// principalVariation follows best replies from move until the game ends or the line is unknown
+func (s *searcher) principalVariation(move int) []int {
+	line := []int{move}
+	won := s.wins(move, 0)
+	s.board.MakeMove(move, s.symbols[0])
+
+	for side := 1; !won; side = 1 - side {
+		next := s.bestReply(side)
+		if next < 0 {
+			break
+		}
+		won = s.wins(next, side)
+		s.board.MakeMove(next, s.symbols[side])
+		line = append(line, next)
+	}
+
+	for i := len(line) - 1; i >= 0; i-- {
+		s.board.Undo(line[i])
+	}
+	return line
+}
+
+// bestReply returns the best known move of side: a winning move, a forced block or the stored best move
+func (s *searcher) bestReply(side int) int {
+	if len(s.candidates(0)) == 0 {
+		return -1
+	}
+
+	lines := s.scan(side)
+	if lines.win >= 0 {
+		return lines.win
+	}
+	if lines.threat >= 0 {
+		return lines.threat
+	}
+
+	if entry, ok := s.table[s.board.Hash()]; ok && entry.best >= 0 && s.board.IsValidPosition(entry.best) {
+		return entry.best
+	}
+	return -1
+}
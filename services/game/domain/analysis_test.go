@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		cells     [][]string
+		toMove    string
+		winLength int
+		maxNodes  int
+		outcome   Outcome
+		distance  int
+		best      []int
+	}{
+		{
+			name:    "empty board is a draw",
+			cells:   [][]string{{"", "", ""}, {"", "", ""}, {"", "", ""}},
+			toMove:  "X",
+			outcome: OutcomeDraw,
+			best:    []int{0, 1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		{
+			name:     "win in one",
+			cells:    [][]string{{"X", "X", ""}, {"O", "O", ""}, {"", "", ""}},
+			toMove:   "X",
+			outcome:  OutcomeWin,
+			distance: 1,
+			best:     []int{2},
+		},
+		{
+			name:    "forced block",
+			cells:   [][]string{{"X", "", ""}, {"O", "O", ""}, {"X", "", ""}},
+			toMove:  "X",
+			outcome: OutcomeDraw,
+			best:    []int{5},
+		},
+		{
+			name:      "unknown when the budget runs out",
+			cells:     emptyCells(7),
+			toMove:    "X",
+			winLength: 4,
+			maxNodes:  100,
+			outcome:   OutcomeUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, err := NewAnalysisPosition(tt.cells, tt.toMove, tt.winLength, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			analysis, err := Analyze(pos, tt.maxNodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if analysis.Evaluation.Outcome != tt.outcome || analysis.Evaluation.Distance != tt.distance {
+				t.Fatalf("evaluation %+v, want %s in %d", analysis.Evaluation, tt.outcome, tt.distance)
+			}
+			if tt.best == nil {
+				return
+			}
+			best := analysis.BestMoves()
+			slices.Sort(best)
+			if !slices.Equal(best, tt.best) {
+				t.Fatalf("best moves %v, want %v", best, tt.best)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDecidedPosition(t *testing.T) {
+	pos, err := NewAnalysisPosition([][]string{{"X", "X", "X"}, {"O", "O", ""}, {"", "", ""}}, "O", 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Analyze(pos, 0); !errors.Is(err, ErrPositionDecided) {
+		t.Fatalf("expected ErrPositionDecided, got %v", err)
+	}
+}
+
+func TestAnnotateMove(t *testing.T) {
+	pos, err := NewAnalysisPosition([][]string{{"X", "", ""}, {"O", "O", ""}, {"X", "", ""}}, "X", 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis, err := Analyze(pos, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, annotation := AnnotateMove(analysis, 5); annotation != AnnotationBest {
+		t.Errorf("blocking move annotated %s", annotation)
+	}
+	if played, annotation := AnnotateMove(analysis, 1); annotation != AnnotationBlunder || played.Outcome != OutcomeLoss {
+		t.Errorf("missing the block annotated %s with %+v", annotation, played)
+	}
+	if _, annotation := AnnotateMove(analysis, 0); annotation != AnnotationUnknown {
+		t.Errorf("occupied cell annotated %s", annotation)
+	}
+}
+
+func emptyCells(size int) [][]string {
+	cells := make([][]string, size)
+	for row := range cells {
+		cells[row] = make([]string, size)
+	}
+	return cells
+}
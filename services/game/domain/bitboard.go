@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"math/bits"
+	"sync"
+)
+
+const (
+	// maxCells - cells of the largest board
+	maxCells = MaxBoardSize * MaxBoardSize
+	// bitsetWords - words of a bitset holding one bit per cell
+	bitsetWords = (maxCells + 63) / 64
+	// maxSymbols - symbols a board can hold: the defaults plus one chosen symbol per player
+	maxSymbols = 2 * MaxPlayers
+)
+
+// bitset - one bit per cell of a board
+type bitset [bitsetWords]uint64
+
+// set sets the bit of cell i
+func (s *bitset) set(i int) {
+	s[i>>6] |= 1 << (i & 63)
+}
+
+// clear clears the bit of cell i
+func (s *bitset) clear(i int) {
+	s[i>>6] &^= 1 << (i & 63)
+}
+
+// has checks if the bit of cell i is set
+func (s *bitset) has(i int) bool {
+	return s[i>>6]&(1<<(i&63)) != 0
+}
+
+// covers checks if every cell of mask is set
+func (s *bitset) covers(mask *winMask) bool {
+	for w := mask.lo; w <= mask.hi; w++ {
+		if s[w]&mask.bits[w] != mask.bits[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// winMask - cells of one straight line of a given length
+type winMask struct {
+	bits bitset
+	// lo and hi are the first and last words holding cells of the line
+	lo, hi int
+	// before and after are the cells extending the line on either side, -1 off the board
+	before, after int
+}
+
+// count counts the cells of the line set in a and in b
+func (m *winMask) count(a, b *bitset) (inA, inB int) {
+	for w := m.lo; w <= m.hi; w++ {
+		if cells := m.bits[w]; a[w]&cells|b[w]&cells != 0 {
+			inA += bits.OnesCount64(a[w] & cells)
+			inB += bits.OnesCount64(b[w] & cells)
+		}
+	}
+	return inA, inB
+}
+
+// maskTable - all lines of a length on a board of a size
+type maskTable struct {
+	masks []winMask
+	// through lists indices of masks containing each cell
+	through [][]int32
+}
+
+// maskTables caches mask tables by board size and line length
+var maskTables = struct {
+	sync.RWMutex
+	tables map[[2]int]*maskTable
+}{tables: make(map[[2]int]*maskTable)}
+
+// lineMasks returns the lines of length on a size×size board, computing them once
+func lineMasks(size, length int) *maskTable {
+	key := [2]int{size, length}
+
+	maskTables.RLock()
+	table, ok := maskTables.tables[key]
+	maskTables.RUnlock()
+	if ok {
+		return table
+	}
+
+	maskTables.Lock()
+	defer maskTables.Unlock()
+	if table, ok := maskTables.tables[key]; ok {
+		return table
+	}
+
+	table = newMaskTable(size, length)
+	maskTables.tables[key] = table
+	return table
+}
+
+// newMaskTable computes every line of length in every direction
+func newMaskTable(size, length int) *maskTable {
+	table := &maskTable{through: make([][]int32, size*size)}
+	cell := func(row, col int) int {
+		if row < 0 || row >= size || col < 0 || col >= size {
+			return -1
+		}
+		return row*size + col
+	}
+
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			for _, d := range directions {
+				if cell(row+(length-1)*d[0], col+(length-1)*d[1]) < 0 {
+					continue
+				}
+
+				mask := winMask{
+					lo:     bitsetWords,
+					before: cell(row-d[0], col-d[1]),
+					after:  cell(row+length*d[0], col+length*d[1]),
+				}
+				index := int32(len(table.masks))
+				for i := 0; i < length; i++ {
+					position := cell(row+i*d[0], col+i*d[1])
+					mask.bits.set(position)
+					mask.lo = min(mask.lo, position>>6)
+					mask.hi = max(mask.hi, position>>6)
+					table.through[position] = append(table.through[position], index)
+				}
+				table.masks = append(table.masks, mask)
+			}
+		}
+	}
+	return table
+}
+
+// zobrist holds a random key per cell and symbol slot.
+// The keys come from a fixed seed so that hashes are stable across processes.
+var zobrist = func() (keys [maxCells][maxSymbols]uint64) {
+	state := uint64(0x9e3779b97f4a7c15)
+	for cell := range keys {
+		for slot := range keys[cell] {
+			keys[cell][slot] = splitmix64(&state)
+		}
+	}
+	return keys
+}()
+
+// splitmix64 advances state and returns the next pseudo-random number
+func splitmix64(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
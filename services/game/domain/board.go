@@ -4,90 +4,247 @@ import (
 	"errors"
 )
 
-// Board - Value Object of game board
+// Board errors are created once so that making moves does not allocate
+var (
+	errInvalidPosition = errors.New("invalid position")
+	errInvalidSymbol   = errors.New("invalid symbol")
+	errOccupied        = errors.New("position already occupied")
+	errEmptyPosition   = errors.New("position is empty")
+	errTooManySymbols  = errors.New("too many symbols on the board")
+)
+
+// Board - Value Object of a square game board.
+// Cells are kept as one bitset per symbol, which makes line checks a few
+// word operations and lets search code make and undo moves without allocating.
 type Board struct {
-	cells [3][3]string
+	size    int
+	cells   []uint8 // symbol slot + 1 of every cell, 0 if empty
+	bits    [maxSymbols]bitset
+	symbols [maxSymbols]string
+	counts  [maxSymbols]int // pieces of every slot
+	filled  int
+	hash    uint64
+
+	// lines caches the mask table of the line length checked last
+	lines      *maskTable
+	lineLength int
 }
 
-// NewBoard creates a new game board
+// NewBoard creates a new classic 3×3 game board
 func NewBoard() *Board {
-	return &Board{
-		cells: [3][3]string{
-			{"", "", ""},
-			{"", "", ""},
-			{"", "", ""},
-		},
+	return NewBoardOfSize(3)
+}
+
+// NewBoardOfSize creates an empty board with size rows and columns
+func NewBoardOfSize(size int) *Board {
+	board := &Board{
+		size:  size,
+		cells: make([]uint8, size*size),
 	}
+	copy(board.symbols[:], DefaultSymbols)
+	return board
+}
+
+// Size returns the number of rows and columns
+func (b *Board) Size() int {
+	return b.size
 }
 
 // MakeMove executes a move on the board
 func (b *Board) MakeMove(position int, symbol string) error {
-	if position < 0 || position > 8 {
-		return errors.New("invalid position")
+	if position < 0 || position >= len(b.cells) {
+		return errInvalidPosition
 	}
 	
-	if symbol != "X" && symbol != "O" {
-		return errors.New("invalid symbol")
+	if !ValidSymbol(symbol) {
+		return errInvalidSymbol
 	}
 	
-	row := position / 3
-	col := position % 3
+	if b.cells[position] != 0 {
+		return errOccupied
+	}
 	
-	if b.cells[row][col] != "" {
-		return errors.New("position already occupied")
+	slot := b.slotOf(symbol)
+	if slot < 0 {
+		return errTooManySymbols
 	}
 	
-	b.cells[row][col] = symbol
+	b.cells[position] = uint8(slot + 1)
+	b.bits[slot].set(position)
+	b.counts[slot]++
+	b.hash ^= zobrist[position][slot]
+	b.filled++
 	return nil
 }
 
-// HasWinner checks if there is a winner
-func (b *Board) HasWinner() bool {
-	// Check rows
-	for i := 0; i < 3; i++ {
-		if b.cells[i][0] != "" && b.cells[i][0] == b.cells[i][1] && b.cells[i][1] == b.cells[i][2] {
-			return true
-		}
+// Undo removes the symbol at position
+func (b *Board) Undo(position int) error {
+	if position < 0 || position >= len(b.cells) {
+		return errInvalidPosition
 	}
 	
-	// Check columns
-	for i := 0; i < 3; i++ {
-		if b.cells[0][i] != "" && b.cells[0][i] == b.cells[1][i] && b.cells[1][i] == b.cells[2][i] {
-			return true
-		}
+	if b.cells[position] == 0 {
+		return errEmptyPosition
 	}
 	
-	// Check diagonals
-	if b.cells[0][0] != "" && b.cells[0][0] == b.cells[1][1] && b.cells[1][1] == b.cells[2][2] {
-		return true
+	slot := int(b.cells[position] - 1)
+	b.cells[position] = 0
+	b.bits[slot].clear(position)
+	b.counts[slot]--
+	b.hash ^= zobrist[position][slot]
+	b.filled--
+	return nil
+}
+
+// slotOf returns the slot of symbol, taking a free one for a new symbol, or -1
+func (b *Board) slotOf(symbol string) int {
+	free := -1
+	for slot, s := range b.symbols {
+		if s == symbol {
+			return slot
+		}
+		if s == "" && free < 0 {
+			free = slot
+		}
 	}
 	
-	if b.cells[0][2] != "" && b.cells[0][2] == b.cells[1][1] && b.cells[1][1] == b.cells[2][0] {
-		return true
+	if free >= 0 {
+		b.symbols[free] = symbol
+	}
+	return free
+}
+
+// Hash returns the Zobrist hash of the position.
+// Equal positions on boards whose chosen symbols appeared in the same order hash equally.
+func (b *Board) Hash() uint64 {
+	return b.hash
+}
+
+// HasWinner checks if there is a winner
+func (b *Board) HasWinner() bool {
+	return b.Winner() != ""
+}
+
+// Winner returns the symbol filling a whole row, column or diagonal, or ""
+func (b *Board) Winner() string {
+	table := b.masks(b.size)
+	for slot := range b.bits {
+		if b.counts[slot] < b.size {
+			continue
+		}
+		for i := range table.masks {
+			if b.bits[slot].covers(&table.masks[i]) {
+				return b.symbols[slot]
+			}
+		}
+	}
+	return ""
+}
+
+// CompletesLine checks if the symbol at position is part of a line of length.
+// Only lines through position are checked, as no other line can have changed.
+// Exact checks do not count lines longer than length.
+func (b *Board) CompletesLine(position, length int, exact bool) bool {
+	if position < 0 || position >= len(b.cells) || b.cells[position] == 0 {
+		return false
 	}
 	
+	return b.lineThrough(position, int(b.cells[position]-1), length, exact)
+}
+
+// completesLineWith checks if placing the symbol of slot on the empty position would complete a line
+func (b *Board) completesLineWith(position, slot, length int, exact bool) bool {
+	b.bits[slot].set(position)
+	complete := b.lineThrough(position, slot, length, exact)
+	b.bits[slot].clear(position)
+	return complete
+}
+
+// lineThrough checks if the bits of slot cover a line of length through position
+func (b *Board) lineThrough(position, slot, length int, exact bool) bool {
+	table := b.masks(length)
+	for _, i := range table.through[position] {
+		mask := &table.masks[i]
+		if !b.bits[slot].covers(mask) {
+			continue
+		}
+		if !exact || (!b.hasSlot(mask.before, slot) && !b.hasSlot(mask.after, slot)) {
+			return true
+		}
+	}
 	return false
 }
 
+// masks returns the lines of length on this board
+func (b *Board) masks(length int) *maskTable {
+	if b.lines == nil || b.lineLength != length {
+		b.lines, b.lineLength = lineMasks(b.size, length), length
+	}
+	return b.lines
+}
+
+// hasSlot checks if a possibly off-board cell holds the symbol of slot
+func (b *Board) hasSlot(position, slot int) bool {
+	return position >= 0 && b.bits[slot].has(position)
+}
+
 // IsFull checks if the board is full
 func (b *Board) IsFull() bool {
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
-			if b.cells[i][j] == "" {
-				return false
-			}
+	return b.filled == len(b.cells)
+}
+
+// directions - row and column steps of horizontal, vertical and both diagonal lines
+var directions = [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// LinesThrough returns the lengths of the runs of the symbol at position
+// in every direction. Only cells around position are visited.
+func (b *Board) LinesThrough(position int) [4]int {
+	var lengths [4]int
+	if position < 0 || position >= len(b.cells) || b.cells[position] == 0 {
+		return lengths
+	}
+	
+	slot := b.cells[position]
+	row, col := position/b.size, position%b.size
+	for i, d := range directions {
+		lengths[i] = 1 + b.run(row, col, d[0], d[1], slot) + b.run(row, col, -d[0], -d[1], slot)
+	}
+	return lengths
+}
+
+// run counts cells of a slot next to (row, col) going in one direction
+func (b *Board) run(row, col, dRow, dCol int, slot uint8) int {
+	count := 0
+	for {
+		row, col = row+dRow, col+dCol
+		if row < 0 || row >= b.size || col < 0 || col >= b.size || b.cells[row*b.size+col] != slot {
+			return count
+		}
+		count++
+	}
+}
+
+// DropPosition returns the lowest free position of a column
+func (b *Board) DropPosition(column int) (int, error) {
+	if column < 0 || column >= b.size {
+		return 0, errors.New("invalid column")
+	}
+	
+	for row := b.size - 1; row >= 0; row-- {
+		if position := row*b.size + column; b.cells[position] == 0 {
+			return position, nil
 		}
 	}
-	return true
+	return 0, errors.New("column is full")
 }
 
 // GetState returns board state as 2D array
 func (b *Board) GetState() [][]string {
-	result := make([][]string, 3)
-	for i := 0; i < 3; i++ {
-		result[i] = make([]string, 3)
-		for j := 0; j < 3; j++ {
-			result[i][j] = b.cells[i][j]
+	result := make([][]string, b.size)
+	for i := 0; i < b.size; i++ {
+		result[i] = make([]string, b.size)
+		for j := 0; j < b.size; j++ {
+			result[i][j] = b.GetCell(i, j)
 		}
 	}
 	return result
@@ -95,18 +252,24 @@ func (b *Board) GetState() [][]string {
 
 // GetCell returns cell value
 func (b *Board) GetCell(row, col int) string {
-	if row < 0 || row > 2 || col < 0 || col > 2 {
+	if row < 0 || row >= b.size || col < 0 || col >= b.size {
+		return ""
+	}
+	return b.CellAt(row*b.size + col)
+}
+
+// CellAt returns value of the cell at position
+func (b *Board) CellAt(position int) string {
+	if position < 0 || position >= len(b.cells) || b.cells[position] == 0 {
 		return ""
 	}
-	return b.cells[row][col]
+	return b.symbols[b.cells[position]-1]
 }
 
 // IsValidPosition checks if position is valid
 func (b *Board) IsValidPosition(position int) bool {
-	if position < 0 || position > 8 {
+	if position < 0 || position >= len(b.cells) {
 		return false
 	}
-	row := position / 3
-	col := position % 3
-	return b.cells[row][col] == ""
-} 
\ No newline at end of file
+	return b.cells[position] == 0
+}
\ No newline at end of file
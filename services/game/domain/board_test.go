@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMakeMoveAndUndoDoNotAllocate(t *testing.T) {
+	board := NewBoardOfSize(GomokuBoardSize)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := board.MakeMove(112, "X"); err != nil {
+			t.Fatal(err)
+		}
+		board.CompletesLine(112, GomokuWinLength, true)
+		if err := board.Undo(112); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestUndoRestoresHash(t *testing.T) {
+	board := NewBoardOfSize(GomokuBoardSize)
+	empty := board.Hash()
+
+	board.MakeMove(0, "X")
+	board.MakeMove(1, "O")
+	afterTwo := board.Hash()
+
+	board.MakeMove(2, "X")
+	board.Undo(2)
+	if board.Hash() != afterTwo {
+		t.Fatal("undo did not restore the hash")
+	}
+
+	board.Undo(1)
+	board.Undo(0)
+	if board.Hash() != empty || board.CellAt(0) != "" {
+		t.Fatal("undo did not restore the empty board")
+	}
+
+	swapped := NewBoardOfSize(GomokuBoardSize)
+	swapped.MakeMove(0, "O")
+	swapped.MakeMove(1, "X")
+	if swapped.Hash() == afterTwo {
+		t.Fatal("different positions hash equally")
+	}
+}
+
+func TestCompletesLineExact(t *testing.T) {
+	board := NewBoardOfSize(GomokuBoardSize)
+	for _, position := range []int{0, 1, 2, 3, 5} {
+		board.MakeMove(position, "X")
+	}
+
+	board.MakeMove(4, "X")
+	if board.CompletesLine(4, GomokuWinLength, true) {
+		t.Fatal("six in a row counted as exactly five")
+	}
+	if !board.CompletesLine(4, GomokuWinLength, false) {
+		t.Fatal("six in a row not counted as five or more")
+	}
+
+	board.Undo(0)
+	if !board.CompletesLine(4, GomokuWinLength, true) {
+		t.Fatal("five in a row not counted")
+	}
+}
+
+// stringGrid - a board of strings that rescans every line after each move,
+// as Board did before bitboards. It is the baseline of the benchmarks.
+type stringGrid struct {
+	size  int
+	cells [][]string
+}
+
+func newStringGrid(size int) *stringGrid {
+	cells := make([][]string, size)
+	for i := range cells {
+		cells[i] = make([]string, size)
+	}
+	return &stringGrid{size: size, cells: cells}
+}
+
+func (g *stringGrid) hasLine(length int) bool {
+	for row := 0; row < g.size; row++ {
+		for col := 0; col < g.size; col++ {
+			symbol := g.cells[row][col]
+			if symbol == "" {
+				continue
+			}
+			for _, d := range directions {
+				n := 1
+				for r, c := row+d[0], col+d[1]; r >= 0 && r < g.size && c >= 0 && c < g.size && g.cells[r][c] == symbol; r, c = r+d[0], c+d[1] {
+					n++
+				}
+				if n >= length {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// playout returns a fixed random order of gomoku cells
+func playout(moves int) []int {
+	return rand.New(rand.NewSource(1)).Perm(GomokuBoardSize * GomokuBoardSize)[:moves]
+}
+
+var symbols = [2]string{"X", "O"}
+
+func BenchmarkPlayoutBitboard(b *testing.B) {
+	moves := playout(80)
+	board := NewBoardOfSize(GomokuBoardSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for ply, position := range moves {
+			board.MakeMove(position, symbols[ply%2])
+			board.CompletesLine(position, GomokuWinLength, false)
+		}
+		for _, position := range moves {
+			board.Undo(position)
+		}
+	}
+}
+
+func BenchmarkPlayoutStringGrid(b *testing.B) {
+	moves := playout(80)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		grid := newStringGrid(GomokuBoardSize)
+		for ply, position := range moves {
+			grid.cells[position/GomokuBoardSize][position%GomokuBoardSize] = symbols[ply%2]
+			grid.hasLine(GomokuWinLength)
+		}
+	}
+}
+
+func BenchmarkWinnerClassic(b *testing.B) {
+	board := NewBoard()
+	for ply, position := range []int{0, 4, 8, 2, 6, 3} {
+		board.MakeMove(position, symbols[ply%2])
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		board.HasWinner()
+	}
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event - domain event recorded by the Game aggregate
+type Event interface {
+	EventName() string
+	AggregateID() string
+	OccurredOn() time.Time
+}
+
+// EventBase - fields shared by all game events
+type EventBase struct {
+	GameID     string    `json:"game_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// AggregateID returns ID of the game the event belongs to
+func (e EventBase) AggregateID() string {
+	return e.GameID
+}
+
+// OccurredOn returns the time the event happened
+func (e EventBase) OccurredOn() time.Time {
+	return e.OccurredAt
+}
+
+// GameCreated - a new game was created
+type GameCreated struct {
+	EventBase
+	PlayerID       string       `json:"player_id"`
+	Username       string       `json:"username"`
+	Symbol         string       `json:"symbol"`
+	Settings       GameSettings `json:"settings"`
+	SeriesID       string       `json:"series_id"`
+	PreviousGameID string       `json:"previous_game_id,omitempty"`
+}
+
+// EventName returns the event name
+func (GameCreated) EventName() string { return "game.created" }
+
+// PlayerJoined - a player took a seat in the game
+type PlayerJoined struct {
+	EventBase
+	PlayerID string `json:"player_id"`
+	Username string `json:"username"`
+	Symbol   string `json:"symbol"`
+}
+
+// EventName returns the event name
+func (PlayerJoined) EventName() string { return "game.player_joined" }
+
+// MoveMade - a player placed a symbol on the board
+type MoveMade struct {
+	EventBase
+	PlayerID string `json:"player_id"`
+	Position int    `json:"position"`
+	Symbol   string `json:"symbol"`
+}
+
+// EventName returns the event name
+func (MoveMade) EventName() string { return "game.move_made" }
+
+// DrawOffered - a player offered a draw
+type DrawOffered struct {
+	EventBase
+	PlayerID string `json:"player_id"`
+}
+
+// EventName returns the event name
+func (DrawOffered) EventName() string { return "game.draw_offered" }
+
+// DrawDeclined - a player declined the opponent's draw offer
+type DrawDeclined struct {
+	EventBase
+	PlayerID string `json:"player_id"`
+}
+
+// EventName returns the event name
+func (DrawDeclined) EventName() string { return "game.draw_declined" }
+
+// GameWon - the game finished with a winner
+type GameWon struct {
+	EventBase
+	WinnerID string       `json:"winner_id"`
+	LoserID  string       `json:"loser_id"`            // first of LoserIDs, kept for two-player consumers
+	LoserIDs []string     `json:"loser_ids,omitempty"` // every other player of the game
+	Reason   FinishReason `json:"reason"`
+}
+
+// EventName returns the event name
+func (GameWon) EventName() string { return "game.won" }
+
+// GameDrawn - the game finished without a winner
+type GameDrawn struct {
+	EventBase
+	Reason FinishReason `json:"reason"`
+}
+
+// EventName returns the event name
+func (GameDrawn) EventName() string { return "game.drawn" }
+
+// GameAbandoned - a player left the game and forfeited it
+type GameAbandoned struct {
+	EventBase
+	PlayerID string `json:"player_id"`
+	WinnerID string `json:"winner_id"`
+}
+
+// EventName returns the event name
+func (GameAbandoned) EventName() string { return "game.abandoned" }
+
+// PlayerEliminated - a player left a game of three or more that goes on without them
+type PlayerEliminated struct {
+	EventBase
+	PlayerID string       `json:"player_id"`
+	Reason   FinishReason `json:"reason"`
+}
+
+// EventName returns the event name
+func (PlayerEliminated) EventName() string { return "game.player_eliminated" }
+
+// TakebackRequested - a player asked to take back their last move
+type TakebackRequested struct {
+	EventBase
+	PlayerID string `json:"player_id"`
+}
+
+// EventName returns the event name
+func (TakebackRequested) EventName() string { return "game.takeback_requested" }
+
+// TakebackAccepted - the opponent agreed and the moves were reverted
+type TakebackAccepted struct {
+	EventBase
+	PlayerID    string `json:"player_id"`
+	RequesterID string `json:"requester_id"`
+	Positions   []int  `json:"positions"` // reverted moves, latest first
+}
+
+// EventName returns the event name
+func (TakebackAccepted) EventName() string { return "game.takeback_accepted" }
+
+// TakebackDeclined - the opponent refused a takeback
+type TakebackDeclined struct {
+	EventBase
+	PlayerID string `json:"player_id"`
+}
+
+// EventName returns the event name
+func (TakebackDeclined) EventName() string { return "game.takeback_declined" }
+
+// RematchAccepted - a player agreed to a rematch
+type RematchAccepted struct {
+	EventBase
+	PlayerID string `json:"player_id"`
+}
+
+// EventName returns the event name
+func (RematchAccepted) EventName() string { return "game.rematch_accepted" }
+
+// RematchCreated - the next game of the series was created
+type RematchCreated struct {
+	EventBase
+	NextGameID string `json:"next_game_id"`
+}
+
+// EventName returns the event name
+func (RematchCreated) EventName() string { return "game.rematch_created" }
+
+// eventDecoders maps event names to JSON decoders
+var eventDecoders = map[string]func([]byte) (Event, error){
+	GameCreated{}.EventName():       decodeEvent[GameCreated],
+	PlayerJoined{}.EventName():      decodeEvent[PlayerJoined],
+	MoveMade{}.EventName():          decodeEvent[MoveMade],
+	DrawOffered{}.EventName():       decodeEvent[DrawOffered],
+	DrawDeclined{}.EventName():      decodeEvent[DrawDeclined],
+	GameWon{}.EventName():           decodeEvent[GameWon],
+	GameDrawn{}.EventName():         decodeEvent[GameDrawn],
+	GameAbandoned{}.EventName():     decodeEvent[GameAbandoned],
+	PlayerEliminated{}.EventName():  decodeEvent[PlayerEliminated],
+	TakebackRequested{}.EventName(): decodeEvent[TakebackRequested],
+	TakebackAccepted{}.EventName():  decodeEvent[TakebackAccepted],
+	TakebackDeclined{}.EventName():  decodeEvent[TakebackDeclined],
+	RematchAccepted{}.EventName():   decodeEvent[RematchAccepted],
+	RematchCreated{}.EventName():    decodeEvent[RematchCreated],
+}
+
+// DecodeEvent restores an event from its name and JSON payload
+func DecodeEvent(name string, data []byte) (Event, error) {
+	decode, ok := eventDecoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %s", name)
+	}
+	return decode(data)
+}
+
+// decodeEvent unmarshals a payload into an event of type E
+func decodeEvent[E Event](data []byte) (Event, error) {
+	var event E
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// eventBase returns event fields for this game at the current time
+func (g *Game) eventBase() EventBase {
+	return EventBase{
+		GameID:     g.ID,
+		OccurredAt: time.Now(),
+	}
+}
+
+// PendingEvents returns events recorded since the last PullEvents
+func (g *Game) PendingEvents() []Event {
+	return append([]Event(nil), g.events...)
+}
+
+// PersistedVersion returns the version the game had before its pending events
+func (g *Game) PersistedVersion() int {
+	return g.Version - len(g.events)
+}
+
+// PullEvents returns pending events and clears them
+func (g *Game) PullEvents() []Event {
+	events := g.events
+	g.events = nil
+	return events
+}
@@ -2,14 +2,14 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
 // Game - main game aggregate
 type Game struct {
 	ID          string
-	Player1     *Player
-	Player2     *Player
+	Players     []*Player // in turn order
 	Board       *Board
 	Status      GameStatus
 	CurrentTurn *Player
@@ -17,6 +17,29 @@ type Game struct {
 	CreatedAt   time.Time
 	StartedAt   *time.Time
 	FinishedAt  *time.Time
+
+	// TurnStartedAt is when the current turn began: the start, the last move or an accepted takeback
+	TurnStartedAt *time.Time
+
+	FinishReason  FinishReason
+	DrawOfferedBy *Player
+	LastMove      *Move
+	Moves         []Move   // history of the moves still on the board
+	Eliminated    []string // IDs of players out of a game of three or more
+
+	TakebackRequestedBy *Player
+	TakebacksUsed       map[string]int // accepted takebacks by requester ID
+
+	Settings          GameSettings
+	SeriesID          string
+	PreviousGameID    string
+	NextGameID        string
+	RematchAcceptedBy []string
+
+	// Number of events applied to the aggregate, including pending ones
+	Version int
+
+	events []Event
 }
 
 // GameStatus - game status
@@ -28,38 +51,87 @@ const (
 	GameStatusFinished GameStatus = "finished"
 )
 
+// FinishReason - why a finished game ended
+type FinishReason string
+
+const (
+	FinishReasonWinLine    FinishReason = "win_line"
+	FinishReasonDrawBoard  FinishReason = "draw_board"
+	FinishReasonResign     FinishReason = "resign"
+	FinishReasonTimeout    FinishReason = "timeout" // seated but never connected in time
+	FinishReasonAbandon    FinishReason = "abandon"
+	FinishReasonAgreedDraw FinishReason = "agreed_draw"
+	FinishReasonMisereLine FinishReason = "misere_line" // the loser completed a line in misère
+	FinishReasonEnded      FinishReason = "ended"       // ended by a moderator, counts for nobody
+)
+
 // NewGame creates a new game
 func NewGame(player1 *Player) *Game {
-	return &Game{
-		ID:        generateGameID(),
-		Player1:   player1,
-		Board:     NewBoard(),
-		Status:    GameStatusWaiting,
-		CreatedAt: time.Now(),
-	}
+	return NewGameWithSettings(player1, DefaultGameSettings())
 }
 
-// JoinGame allows the second player to join the game
-func (g *Game) JoinGame(player2 *Player) error {
+// NewGameWithSettings creates a new game with the given settings
+func NewGameWithSettings(player1 *Player, settings GameSettings) *Game {
+	return newGame(defaultIDGenerator.NewID(), player1, settings)
+}
+
+// newGame creates a new game with the given ID
+func newGame(id string, player1 *Player, settings GameSettings) *Game {
+	game := &Game{}
+	game.raise(GameCreated{
+		EventBase: EventBase{GameID: id, OccurredAt: time.Now()},
+		PlayerID:  player1.ID,
+		Username:  player1.Username,
+		Symbol:    player1.Symbol,
+		Settings:  settings,
+		SeriesID:  id,
+	})
+	return game
+}
+
+// JoinGame takes the next free seat. The game starts once all seats are taken.
+func (g *Game) JoinGame(player *Player) error {
 	if g.Status != GameStatusWaiting {
 		return errors.New("game is not in waiting status")
 	}
 	
-	if g.Player1.ID == player2.ID {
+	if g.isPlayerInGame(player) {
 		return errors.New("player cannot join their own game")
 	}
 	
-	g.Player2 = player2
-	g.Status = GameStatusActive
-	g.CurrentTurn = g.Player1
-	now := time.Now()
-	g.StartedAt = &now
+	if !g.Settings.IsInvited(player.ID) {
+		return errors.New("game is reserved for invited players")
+	}
 	
-	return nil
+	if !ValidSymbol(player.Symbol) {
+		return errors.New("invalid symbol")
+	}
+	
+	if g.isSymbolTaken(player.Symbol) {
+		return fmt.Errorf("symbol %s is already taken", player.Symbol)
+	}
+	
+	return g.raise(PlayerJoined{
+		EventBase: g.eventBase(),
+		PlayerID:  player.ID,
+		Username:  player.Username,
+		Symbol:    player.Symbol,
+	})
+}
+
+// IsFull checks if all seats of the game are taken
+func (g *Game) IsFull() bool {
+	return len(g.Players) >= g.Settings.PlayerCount()
 }
 
-// MakeMove executes a move in the game
+// MakeMove places the player's own symbol
 func (g *Game) MakeMove(player *Player, position int) error {
+	return g.MakeMoveWithSymbol(player, position, player.Symbol)
+}
+
+// MakeMoveWithSymbol executes a move in the game.
+// Only variants such as wild let players choose a symbol other than their own.
+func (g *Game) MakeMoveWithSymbol(player *Player, position int, symbol string) error {
 	if g.Status != GameStatusActive {
 		return errors.New("game is not active")
 	}
@@ -71,46 +143,485 @@ func (g *Game) MakeMove(player *Player, position int) error {
 	if !g.isPlayerInGame(player) {
 		return errors.New("player is not in this game")
 	}
+	player = g.PlayerByID(player.ID)
 	
-	if err := g.Board.MakeMove(position, player.Symbol); err != nil {
+	move := Move{
+		Position: position,
+		Symbol:   symbol,
+		PlayerID: player.ID,
+	}
+	if err := g.rules().ValidateMove(g.Board, g.LastMove, player, move); err != nil {
 		return err
 	}
 	
-	// Check for win
-	if g.Board.HasWinner() {
-		g.Status = GameStatusFinished
-		g.Winner = player
-		now := time.Now()
-		g.FinishedAt = &now
-		return nil
+	err := g.raise(MoveMade{
+		EventBase: g.eventBase(),
+		PlayerID:  player.ID,
+		Position:  position,
+		Symbol:    symbol,
+	})
+	if err != nil {
+		return err
+	}
+	
+	switch g.rules().Outcome(g.Board, move) {
+	case MoveResultWin:
+		return g.raiseFinish(player, FinishReasonWinLine)
+	case MoveResultLoss:
+		return g.raiseLoss(player, FinishReasonMisereLine)
+	case MoveResultDraw:
+		return g.raiseFinish(nil, FinishReasonDrawBoard)
+	}
+	
+	return nil
+}
+
+// DropPiece drops a piece into a column of a game with gravity
+func (g *Game) DropPiece(player *Player, column int, symbol string) error {
+	if !g.Settings.Gravity {
+		return errors.New("game is not played with gravity")
 	}
 	
-	// Check for draw
-	if g.Board.IsFull() {
-		g.Status = GameStatusFinished
-		now := time.Now()
-		g.FinishedAt = &now
+	if g.Status != GameStatusActive {
+		return errors.New("game is not active")
+	}
+	
+	position, err := g.Board.DropPosition(column)
+	if err != nil {
+		return err
+	}
+	
+	return g.MakeMoveWithSymbol(player, position, symbol)
+}
+
+// LegalPositions returns the positions the player to move may play
+func (g *Game) LegalPositions() []int {
+	if g.Status != GameStatusActive {
 		return nil
 	}
 	
-	// Switch turn to other player
-	g.switchTurn()
+	var positions []int
+	for position := 0; position < g.Board.Size()*g.Board.Size(); position++ {
+		move := Move{
+			Position: position,
+			Symbol:   g.CurrentTurn.Symbol,
+			PlayerID: g.CurrentTurn.ID,
+		}
+		if g.rules().ValidateMove(g.Board, g.LastMove, g.CurrentTurn, move) == nil {
+			positions = append(positions, position)
+		}
+	}
+	return positions
+}
+
+// rules returns the ruleset of the game's variant
+func (g *Game) rules() Ruleset {
+	return g.Settings.Rules()
+}
+
+// Resign ends the game with a win for the opponent.
+// In games of three or more the player is eliminated and the others play on.
+func (g *Game) Resign(player *Player) error {
+	if err := g.checkActiveParticipant(player); err != nil {
+		return err
+	}
 	
+	return g.raiseLoss(g.PlayerByID(player.ID), FinishReasonResign)
+}
+
+// OfferDraw records a draw offer that the opponent may accept or decline
+func (g *Game) OfferDraw(player *Player) error {
+	if err := g.checkActiveParticipant(player); err != nil {
+		return err
+	}
+	
+	if len(g.Players) > 2 {
+		return errors.New("draws can only be agreed in two-player games")
+	}
+	
+	if g.DrawOfferedBy != nil {
+		return errors.New("draw offer already pending")
+	}
+	
+	return g.raise(DrawOffered{
+		EventBase: g.eventBase(),
+		PlayerID:  player.ID,
+	})
+}
+
+// AcceptDraw accepts the opponent's pending draw offer
+func (g *Game) AcceptDraw(player *Player) error {
+	if err := g.checkDrawOfferFromOpponent(player); err != nil {
+		return err
+	}
+	
+	return g.raiseFinish(nil, FinishReasonAgreedDraw)
+}
+
+// DeclineDraw declines the opponent's pending draw offer
+func (g *Game) DeclineDraw(player *Player) error {
+	if err := g.checkDrawOfferFromOpponent(player); err != nil {
+		return err
+	}
+	
+	return g.raise(DrawDeclined{
+		EventBase: g.eventBase(),
+		PlayerID:  player.ID,
+	})
+}
+
+// Abandon forfeits the game for a player who left it.
+// In games of three or more the player is eliminated and the others play on.
+func (g *Game) Abandon(player *Player) error {
+	if err := g.checkActiveParticipant(player); err != nil {
+		return err
+	}
+	
+	return g.raiseLoss(g.PlayerByID(player.ID), FinishReasonAbandon)
+}
+
+// TimeOut forfeits the game for a player who was seated but did not connect in time.
+// In games of three or more the player is eliminated and the others play on.
+func (g *Game) TimeOut(player *Player) error {
+	if err := g.checkActiveParticipant(player); err != nil {
+		return err
+	}
+	
+	return g.raiseLoss(g.PlayerByID(player.ID), FinishReasonTimeout)
+}
+
+// End finishes an active game without a winner on behalf of a moderator
+func (g *Game) End() error {
+	if g.Status != GameStatusActive {
+		return errors.New("game is not active")
+	}
+	
+	return g.raiseFinish(nil, FinishReasonEnded)
+}
+
+// checkActiveParticipant checks that the game is active and player takes part in it
+func (g *Game) checkActiveParticipant(player *Player) error {
+	if g.Status != GameStatusActive {
+		return errors.New("game is not active")
+	}
+	
+	if !g.isPlayerInGame(player) {
+		return errors.New("player is not in this game")
+	}
+	
+	if g.isEliminated(player.ID) {
+		return errors.New("player has been eliminated")
+	}
+	
+	return nil
+}
+
+// checkDrawOfferFromOpponent checks that player has a draw offer to answer
+func (g *Game) checkDrawOfferFromOpponent(player *Player) error {
+	if err := g.checkActiveParticipant(player); err != nil {
+		return err
+	}
+	
+	if g.DrawOfferedBy == nil {
+		return errors.New("no draw offer pending")
+	}
+	
+	if g.DrawOfferedBy.ID == player.ID {
+		return errors.New("cannot answer your own draw offer")
+	}
+	
+	return nil
+}
+
+// raiseLoss takes player out of the game. When a single player remains they win,
+// otherwise the player is eliminated and the others play on.
+func (g *Game) raiseLoss(player *Player, reason FinishReason) error {
+	remaining := g.remainingPlayersExcept(player)
+	if len(remaining) > 1 {
+		return g.raise(PlayerEliminated{
+			EventBase: g.eventBase(),
+			PlayerID:  player.ID,
+			Reason:    reason,
+		})
+	}
+	
+	if reason == FinishReasonAbandon {
+		return g.raise(GameAbandoned{
+			EventBase: g.eventBase(),
+			PlayerID:  player.ID,
+			WinnerID:  remaining[0].ID,
+		})
+	}
+	
+	return g.raiseFinish(remaining[0], reason)
+}
+
+// raiseFinish raises the event matching the way the game finished
+func (g *Game) raiseFinish(winner *Player, reason FinishReason) error {
+	if winner == nil {
+		return g.raise(GameDrawn{
+			EventBase: g.eventBase(),
+			Reason:    reason,
+		})
+	}
+	
+	var losers []string
+	for _, player := range g.Players {
+		if player.ID != winner.ID {
+			losers = append(losers, player.ID)
+		}
+	}
+	
+	return g.raise(GameWon{
+		EventBase: g.eventBase(),
+		WinnerID:  winner.ID,
+		LoserID:   losers[0],
+		LoserIDs:  losers,
+		Reason:    reason,
+	})
+}
+
+// raise applies a new event and records it as pending
+func (g *Game) raise(event Event) error {
+	if err := g.apply(event); err != nil {
+		return err
+	}
+	
+	g.events = append(g.events, event)
+	return nil
+}
+
+// apply changes the game state according to event.
+// It is the only place where state changes, so that games can be rebuilt from their events.
+func (g *Game) apply(event Event) error {
+	switch e := event.(type) {
+	case GameCreated:
+		g.ID = e.GameID
+		g.Players = []*Player{restorePlayer(e.PlayerID, e.Username, e.Symbol, e.OccurredAt)}
+		g.Settings = e.Settings
+		g.Board = g.rules().NewBoard()
+		g.Status = GameStatusWaiting
+		g.CreatedAt = e.OccurredAt
+		g.SeriesID = e.SeriesID
+		g.PreviousGameID = e.PreviousGameID
+	case PlayerJoined:
+		g.Players = append(g.Players, restorePlayer(e.PlayerID, e.Username, e.Symbol, e.OccurredAt))
+		if g.IsFull() {
+			g.Status = GameStatusActive
+			g.CurrentTurn = g.Players[0]
+			startedAt := e.OccurredAt
+			g.StartedAt = &startedAt
+			g.TurnStartedAt = &startedAt
+		}
+	case MoveMade:
+		if err := g.Board.MakeMove(e.Position, e.Symbol); err != nil {
+			return err
+		}
+		move := Move{
+			Position:  e.Position,
+			Symbol:    e.Symbol,
+			PlayerID:  e.PlayerID,
+			Timestamp: e.OccurredAt,
+		}
+		if turnStartedAt := g.turnStartedAt(); turnStartedAt != nil {
+			move.Spent = e.OccurredAt.Sub(*turnStartedAt).Milliseconds()
+		}
+		g.Moves = append(g.Moves, move)
+		g.LastMove = &move
+		// A move implicitly declines any pending draw offer or takeback request
+		g.DrawOfferedBy = nil
+		g.TakebackRequestedBy = nil
+		if g.rules().Outcome(g.Board, *g.LastMove) == MoveResultNone {
+			g.advanceTurn()
+		}
+		g.startTurn(e.OccurredAt)
+	case DrawOffered:
+		g.DrawOfferedBy = g.PlayerByID(e.PlayerID)
+	case DrawDeclined:
+		g.DrawOfferedBy = nil
+	case TakebackRequested:
+		g.TakebackRequestedBy = g.PlayerByID(e.PlayerID)
+	case TakebackAccepted:
+		if err := g.takeBack(e); err != nil {
+			return err
+		}
+		g.startTurn(e.OccurredAt)
+	case TakebackDeclined:
+		g.TakebackRequestedBy = nil
+	case PlayerEliminated:
+		g.Eliminated = append(g.Eliminated, e.PlayerID)
+		if g.CurrentTurn != nil && g.CurrentTurn.ID == e.PlayerID {
+			g.advanceTurn()
+			g.startTurn(e.OccurredAt)
+		}
+	case GameWon:
+		g.finish(g.PlayerByID(e.WinnerID), e.Reason, e.OccurredAt)
+	case GameDrawn:
+		g.finish(nil, e.Reason, e.OccurredAt)
+	case GameAbandoned:
+		g.finish(g.PlayerByID(e.WinnerID), FinishReasonAbandon, e.OccurredAt)
+	case RematchAccepted:
+		if !g.hasAcceptedRematch(e.PlayerID) {
+			g.RematchAcceptedBy = append(g.RematchAcceptedBy, e.PlayerID)
+		}
+	case RematchCreated:
+		g.NextGameID = e.NextGameID
+	default:
+		return fmt.Errorf("unknown event %s", event.EventName())
+	}
+	
+	g.Version++
+	return nil
+}
+
+// finish moves the game to finished status
+func (g *Game) finish(winner *Player, reason FinishReason, at time.Time) {
+	g.Status = GameStatusFinished
+	g.Winner = winner
+	g.FinishReason = reason
+	g.DrawOfferedBy = nil
+	g.TakebackRequestedBy = nil
+	g.FinishedAt = &at
+	g.TurnStartedAt = nil
+}
+
+// startTurn starts the clock of the player to move
+func (g *Game) startTurn(at time.Time) {
+	g.TurnStartedAt = &at
+}
+
+// turnStartedAt returns when the current turn began.
+// Games stored before turns were timed fall back to the last move or the start.
+func (g *Game) turnStartedAt() *time.Time {
+	switch {
+	case g.TurnStartedAt != nil:
+		return g.TurnStartedAt
+	case g.LastMove != nil && !g.LastMove.Timestamp.IsZero():
+		lastMoveAt := g.LastMove.Timestamp
+		return &lastMoveAt
+	default:
+		return g.StartedAt
+	}
+}
+
+// Clocks returns the milliseconds every player spent on the moves still on the board
+func (g *Game) Clocks() map[string]int64 {
+	clocks := make(map[string]int64, len(g.Players))
+	for _, player := range g.Players {
+		clocks[player.ID] = 0
+	}
+	
+	var lastMoveAt time.Time
+	if g.StartedAt != nil {
+		lastMoveAt = *g.StartedAt
+	}
+	for _, move := range g.Moves {
+		clocks[move.PlayerID] += move.spentSince(lastMoveAt)
+		lastMoveAt = move.Timestamp
+	}
+	return clocks
+}
+
+// RebuildGame folds events into a new game
+func RebuildGame(events []Event) (*Game, error) {
+	game := &Game{}
+	if err := game.ApplyEvents(events); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// ApplyEvents folds already persisted events into the game
+func (g *Game) ApplyEvents(events []Event) error {
+	for _, event := range events {
+		if err := g.apply(event); err != nil {
+			return fmt.Errorf("failed to apply %s at version %d: %w", event.EventName(), g.Version+1, err)
+		}
+	}
+	return nil
+}
+
+// restorePlayer creates a participant from event data
+func restorePlayer(id, username, symbol string, joinedAt time.Time) *Player {
+	player := NewPlayer(id, username, "")
+	player.AssignSymbol(symbol)
+	player.CreatedAt = joinedAt
+	return player
+}
+
+// PlayerByID returns the participant with the given ID or nil
+func (g *Game) PlayerByID(playerID string) *Player {
+	for _, player := range g.Players {
+		if player.ID == playerID {
+			return player
+		}
+	}
 	return nil
 }
 
 // isPlayerInGame checks if player is a participant in the game
 func (g *Game) isPlayerInGame(player *Player) bool {
-	return (g.Player1 != nil && g.Player1.ID == player.ID) ||
-		   (g.Player2 != nil && g.Player2.ID == player.ID)
+	return g.PlayerByID(player.ID) != nil
 }
 
-// switchTurn switches turn between players
-func (g *Game) switchTurn() {
-	if g.CurrentTurn.ID == g.Player1.ID {
-		g.CurrentTurn = g.Player2
-	} else {
-		g.CurrentTurn = g.Player1
+// isSymbolTaken checks if a participant already plays symbol
+func (g *Game) isSymbolTaken(symbol string) bool {
+	for _, player := range g.Players {
+		if player.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// isEliminated checks if the player is out of the game
+func (g *Game) isEliminated(playerID string) bool {
+	for _, id := range g.Eliminated {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// SeatedPlayerIDs returns the IDs of the people still in the game in seat order.
+// The AI never connects, so it is left out.
+func (g *Game) SeatedPlayerIDs() []string {
+	ids := make([]string, 0, len(g.Players))
+	for _, player := range g.Players {
+		if !g.isEliminated(player.ID) && (g.Settings.AI == "" || !player.IsAI()) {
+			ids = append(ids, player.ID)
+		}
+	}
+	return ids
+}
+
+// remainingPlayersExcept returns players still in the game other than player
+func (g *Game) remainingPlayersExcept(player *Player) []*Player {
+	var remaining []*Player
+	for _, p := range g.Players {
+		if p.ID != player.ID && !g.isEliminated(p.ID) {
+			remaining = append(remaining, p)
+		}
+	}
+	return remaining
+}
+
+// advanceTurn passes the turn to the next player in seat order who is still in the game
+func (g *Game) advanceTurn() {
+	current := 0
+	for i, player := range g.Players {
+		if player.ID == g.CurrentTurn.ID {
+			current = i
+		}
+	}
+	
+	for i := 1; i <= len(g.Players); i++ {
+		next := g.Players[(current+i)%len(g.Players)]
+		if !g.isEliminated(next.ID) {
+			g.CurrentTurn = next
+			return
+		}
 	}
 }
 
@@ -122,8 +633,26 @@ func (g *Game) GetGameState() GameState {
 		Board:       g.Board.GetState(),
 		CurrentTurn: g.CurrentTurn,
 		Winner:      g.Winner,
-		Player1:     g.Player1,
-		Player2:     g.Player2,
+		Players:     g.Players,
+
+		TurnStartedAt: g.TurnStartedAt,
+		Clocks:        g.Clocks(),
+
+		FinishReason:  g.FinishReason,
+		DrawOfferedBy: g.DrawOfferedBy,
+		LastMove:      g.LastMove,
+		Moves:         g.Moves,
+		Eliminated:    g.Eliminated,
+
+		TakebackRequestedBy: g.TakebackRequestedBy,
+		TakebacksUsed:       g.TakebacksUsed,
+
+		Settings:          g.Settings,
+		SeriesID:          g.SeriesID,
+		PreviousGameID:    g.PreviousGameID,
+		NextGameID:        g.NextGameID,
+		RematchAcceptedBy: g.RematchAcceptedBy,
+		Version:           g.Version,
 	}
 }
 
@@ -134,11 +663,26 @@ type GameState struct {
 	Board       [][]string `json:"board"`
 	CurrentTurn *Player    `json:"current_turn"`
 	Winner      *Player    `json:"winner,omitempty"`
-	Player1     *Player    `json:"player1"`
-	Player2     *Player    `json:"player2,omitempty"`
-}
+	Players     []*Player  `json:"players"`
+
+	// Clocks hold the milliseconds every player spent, the current turn runs since TurnStartedAt
+	TurnStartedAt *time.Time       `json:"turn_started_at,omitempty"`
+	Clocks        map[string]int64 `json:"clocks"`
+
+	FinishReason  FinishReason `json:"finish_reason,omitempty"`
+	DrawOfferedBy *Player      `json:"draw_offered_by,omitempty"`
+	LastMove      *Move        `json:"last_move,omitempty"`
+	Moves         []Move       `json:"moves,omitempty"`
+	Eliminated    []string     `json:"eliminated,omitempty"`
 
-// generateGameID generates unique game ID
-func generateGameID() string {
-	return "game_" + time.Now().Format("20060102150405")
-} 
\ No newline at end of file
+	TakebackRequestedBy *Player        `json:"takeback_requested_by,omitempty"`
+	TakebacksUsed       map[string]int `json:"takebacks_used,omitempty"`
+
+	Settings          GameSettings `json:"settings"`
+	SeriesID          string       `json:"series_id"`
+	PreviousGameID    string       `json:"previous_game_id,omitempty"`
+	NextGameID        string       `json:"next_game_id,omitempty"`
+	RematchAcceptedBy []string     `json:"rematch_accepted_by,omitempty"`
+	Version           int          `json:"version"`
+}
+ 
\ No newline at end of file
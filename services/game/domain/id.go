@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IDGenerator generates unique identifiers for aggregates
+type IDGenerator interface {
+	NewID() string
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator generates ULIDs: 48 bits of millisecond time followed by
+// 80 random bits, encoded as 26 sortable characters
+type ULIDGenerator struct {
+	now func() time.Time
+}
+
+// NewULIDGenerator creates a generator based on the system clock
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{now: time.Now}
+}
+
+// NewID returns a new ULID
+func (g *ULIDGenerator) NewID() string {
+	var id [16]byte
+	ms := uint64(g.now().UnixMilli())
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	return encodeULID(id)
+}
+
+// encodeULID encodes 128 bits as 26 base32 characters, 5 bits at a time
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// SequenceIDGenerator generates predictable IDs for tests
+type SequenceIDGenerator struct {
+	prefix string
+	next   int
+	mu     sync.Mutex
+}
+
+// NewSequenceIDGenerator creates a generator returning prefix1, prefix2, ...
+func NewSequenceIDGenerator(prefix string) *SequenceIDGenerator {
+	return &SequenceIDGenerator{prefix: prefix}
+}
+
+// NewID returns the next ID of the sequence
+func (g *SequenceIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.next++
+	return fmt.Sprintf("%s%d", g.prefix, g.next)
+}
+
+// defaultIDGenerator is used by constructors that are not given a generator
+var defaultIDGenerator IDGenerator = NewULIDGenerator()
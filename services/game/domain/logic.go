@@ -2,49 +2,377 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 )
 
 // GameService - domain service for game logic
-type GameService struct{}
+type GameService struct {
+	ids IDGenerator
+}
 
 // NewGameService creates a new game service
 func NewGameService() *GameService {
-	return &GameService{}
+	return NewGameServiceWithIDGenerator(defaultIDGenerator)
+}
+
+// NewGameServiceWithIDGenerator creates a game service with a custom ID generator
+func NewGameServiceWithIDGenerator(ids IDGenerator) *GameService {
+	return &GameService{ids: ids}
 }
 
 // CreateGame creates a new game
 func (gs *GameService) CreateGame(player1 *Player) *Game {
 	// Assign symbols to players
-	player1.AssignSymbol("X")
+	player1.AssignSymbol(DefaultSymbols[0])
 	
-	game := NewGame(player1)
+	game := newGame(gs.ids.NewID(), player1, DefaultGameSettings())
 	return game
 }
 
-// JoinGame allows player to join the game
-func (gs *GameService) JoinGame(game *Game, player2 *Player) error {
-	if game.Player2 != nil {
+// CreateGameWithSettings creates a new game with custom settings
+func (gs *GameService) CreateGameWithSettings(player1 *Player, settings GameSettings) (*Game, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	
+	if player1.Symbol == "" {
+		player1.AssignSymbol(DefaultSymbols[0])
+	}
+	if !player1.IsValidSymbol() {
+		return nil, errors.New("invalid symbol")
+	}
+	
+	if settings.AI == "" {
+		return newGame(gs.ids.NewID(), player1, settings), nil
+	}
+	
+	// Games against the AI are casual, so takebacks are unlimited
+	settings.TakebackLimit = UnlimitedTakebacks
+	game := newGame(gs.ids.NewID(), player1, settings)
+	if err := gs.JoinGame(game, NewPlayer(AIPlayerID, AIUsername, "")); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// JoinGame allows player to join the game.
+// Players who did not choose a symbol get the first default one still free.
+func (gs *GameService) JoinGame(game *Game, player *Player) error {
+	if game.IsFull() {
 		return errors.New("game is already full")
 	}
 	
-	// Assign symbol to second player
-	player2.AssignSymbol("O")
+	if player.Symbol == "" {
+		for _, symbol := range DefaultSymbols {
+			if !game.isSymbolTaken(symbol) {
+				player.AssignSymbol(symbol)
+				break
+			}
+		}
+	}
 	
-	return game.JoinGame(player2)
+	return game.JoinGame(player)
 }
 
 // MakeMove executes a move in the game
 func (gs *GameService) MakeMove(game *Game, player *Player, position int) error {
+	return gs.MakeMoveWithSymbol(game, player, position, player.Symbol)
+}
+
+// MakeMoveWithSymbol executes a move with a chosen symbol.
+// An empty symbol stands for the player's own one.
+func (gs *GameService) MakeMoveWithSymbol(game *Game, player *Player, position int, symbol string) error {
+	if symbol == "" {
+		symbol = player.Symbol
+	}
+	
 	// Create move
-	move, err := NewMove(position, player.Symbol, player.ID)
+	move, err := NewMove(position, symbol, player.ID)
 	if err != nil {
 		return err
 	}
 	
 	// Execute move
-	return game.MakeMove(player, move.Position)
+	if err := game.MakeMoveWithSymbol(player, move.Position, move.Symbol); err != nil {
+		return err
+	}
+	return gs.playAI(game)
+}
+
+// DropPiece drops a piece into a column of a game with gravity.
+// An empty symbol stands for the player's own one.
+func (gs *GameService) DropPiece(game *Game, player *Player, column int, symbol string) error {
+	if symbol == "" {
+		symbol = player.Symbol
+	}
+	
+	if err := game.DropPiece(player, column, symbol); err != nil {
+		return err
+	}
+	return gs.playAI(game)
+}
+
+// Resign resigns the game on behalf of player
+func (gs *GameService) Resign(game *Game, player *Player) error {
+	return game.Resign(player)
+}
+
+// OfferDraw offers a draw to the opponent
+func (gs *GameService) OfferDraw(game *Game, player *Player) error {
+	if err := game.OfferDraw(player); err != nil {
+		return err
+	}
+	if ai := game.AIPlayer(); ai != nil {
+		return game.DeclineDraw(ai)
+	}
+	return nil
+}
+
+// AcceptDraw accepts the opponent's draw offer
+func (gs *GameService) AcceptDraw(game *Game, player *Player) error {
+	return game.AcceptDraw(player)
+}
+
+// DeclineDraw declines the opponent's draw offer
+func (gs *GameService) DeclineDraw(game *Game, player *Player) error {
+	return game.DeclineDraw(player)
+}
+
+// RequestTakeback asks the opponent to take back the player's last move
+func (gs *GameService) RequestTakeback(game *Game, player *Player) error {
+	if err := game.RequestTakeback(player); err != nil {
+		return err
+	}
+	if ai := game.AIPlayer(); ai != nil {
+		return game.AcceptTakeback(ai)
+	}
+	return nil
+}
+
+// AcceptTakeback accepts the opponent's takeback request
+func (gs *GameService) AcceptTakeback(game *Game, player *Player) error {
+	return game.AcceptTakeback(player)
+}
+
+// DeclineTakeback declines the opponent's takeback request
+func (gs *GameService) DeclineTakeback(game *Game, player *Player) error {
+	return game.DeclineTakeback(player)
+}
+
+// AnalyzePosition solves a position within a search budget of maxNodes
+func (gs *GameService) AnalyzePosition(pos *AnalysisPosition, maxNodes int) (*Analysis, error) {
+	return Analyze(pos, maxNodes)
+}
+
+// AnnotateGame compares every move of the game with perfect play
+func (gs *GameService) AnnotateGame(game *Game, maxNodes int) ([]MoveAnnotation, error) {
+	annotations := make([]MoveAnnotation, 0, len(game.Moves))
+	for ply, move := range game.Moves {
+		pos, err := game.PositionBefore(ply)
+		if err != nil {
+			return nil, err
+		}
+		
+		analysis, err := Analyze(pos, maxNodes)
+		if err != nil {
+			return nil, err
+		}
+		
+		played, annotation := AnnotateMove(analysis, move.Position)
+		annotations = append(annotations, MoveAnnotation{
+			Ply:        ply,
+			Move:       move,
+			Annotation: annotation,
+			Before:     analysis.Evaluation,
+			Played:     played,
+			BestMoves:  analysis.BestMoves(),
+		})
+	}
+	
+	return annotations, nil
+}
+
+// ImportGame rebuilds a game from a TTN record, validating every move
+func (gs *GameService) ImportGame(text string) (*Game, *GameRecord, error) {
+	record, err := ParseTTN(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	settings, err := record.Settings()
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	players := record.Players(settings.PlayerCount())
+	game, err := gs.CreateGameWithSettings(players[0], settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, player := range players[1:] {
+		if err := game.JoinGame(player); err != nil {
+			return nil, nil, err
+		}
+	}
+	
+	if err := record.Replay(game); err != nil {
+		return nil, nil, err
+	}
+	
+	return game, record, nil
+}
+
+// GetReplayFrame returns the state of the game after ply moves
+func (gs *GameService) GetReplayFrame(game *Game, ply int) (*ReplayFrame, error) {
+	return game.ReplayAt(ply)
+}
+
+// GetReplayFrames returns the state of a finished game after every ply
+func (gs *GameService) GetReplayFrames(game *Game) ([]ReplayFrame, error) {
+	if game.Status != GameStatusFinished {
+		return nil, errors.New("only finished games can be replayed")
+	}
+	
+	var frames []ReplayFrame
+	err := game.Replay(func(frame ReplayFrame) bool {
+		frames = append(frames, frame)
+		return true
+	})
+	return frames, err
+}
+
+// Abandon forfeits the game for a player who has been disconnected too long
+func (gs *GameService) Abandon(game *Game, player *Player) error {
+	return game.Abandon(player)
+}
+
+// TimeOut forfeits the game for a player who never connected to it in time
+func (gs *GameService) TimeOut(game *Game, player *Player) error {
+	return game.TimeOut(player)
+}
+
+// End finishes the game without a winner for a moderator
+func (gs *GameService) End(game *Game) error {
+	return game.End()
+}
+
+// AcceptRematch records a rematch request and reports whether both players agreed
+func (gs *GameService) AcceptRematch(game *Game, player *Player) (bool, error) {
+	ready, err := game.AcceptRematch(player)
+	if ai := game.AIPlayer(); err == nil && !ready && ai != nil {
+		return game.AcceptRematch(ai)
+	}
+	return ready, err
+}
+
+// CreateRematch creates the next game of the series with swapped symbols.
+// A decided series starts a new one with the same settings.
+func (gs *GameService) CreateRematch(previous *Game, seriesGames []*Game) (*Game, error) {
+	if previous.NextGameID != "" {
+		return nil, errors.New("rematch already created")
+	}
+	
+	if !previous.allAcceptedRematch() {
+		return nil, errors.New("all players must accept the rematch")
+	}
+	
+	seriesID := previous.SeriesID
+	if gs.GetSeriesScore(seriesGames, seriesID).IsDecided() {
+		seriesID = ""
+	}
+	
+	rematch, err := previous.newRematch(gs.ids.NewID(), seriesID)
+	if err != nil {
+		return nil, err
+	}
+	// The AI opens rematches in which it plays first
+	if err := gs.playAI(rematch); err != nil {
+		return nil, err
+	}
+	return rematch, nil
+}
+
+// playAI lets the computer opponent of a solo game reply when it is its turn
+func (gs *GameService) playAI(game *Game) error {
+	ai := game.AIPlayer()
+	if ai == nil || game.Status != GameStatusActive || game.CurrentTurn.ID != ai.ID {
+		return nil
+	}
+	
+	position, err := game.chooseAIMove()
+	if err != nil {
+		return fmt.Errorf("AI move: %w", err)
+	}
+	return game.MakeMove(ai, position)
+}
+
+// GetSeriesGames returns games of a series
+func (gs *GameService) GetSeriesGames(games []*Game, seriesID string) []*Game {
+	var seriesGames []*Game
+	for _, game := range games {
+		if game.SeriesID == seriesID {
+			seriesGames = append(seriesGames, game)
+		}
+	}
+	return seriesGames
+}
+
+// GetSeriesScore counts results of finished games of a series
+func (gs *GameService) GetSeriesScore(games []*Game, seriesID string) SeriesScore {
+	score := SeriesScore{
+		SeriesID: seriesID,
+		BestOf:   1,
+		Wins:     make(map[string]int),
+	}
+	
+	for _, game := range gs.GetSeriesGames(games, seriesID) {
+		score.BestOf = game.Settings.BestOf
+		if game.Status != GameStatusFinished {
+			continue
+		}
+		
+		score.GamesPlayed++
+		if game.Winner == nil {
+			score.Draws++
+			continue
+		}
+		
+		score.Wins[game.Winner.ID]++
+		if score.Wins[game.Winner.ID] > score.BestOf/2 {
+			score.WinnerID = game.Winner.ID
+		}
+	}
+	
+	return score
+}
+
+// CreateTournament creates a tournament organized by the player, open for registration
+func (gs *GameService) CreateTournament(organizer *Player, name string, format TournamentFormat, settings GameSettings, rounds, maxPlayers int) (*Tournament, error) {
+	return newTournament(gs.ids.NewID(), organizer.ID, name, format, settings, rounds, maxPlayers)
+}
+
+// CreateTournamentGame creates the game of a pairing with both players seated,
+// so it starts at once, and assigns it to the pairing
+func (gs *GameService) CreateTournamentGame(tournament *Tournament, pairing Pairing) (*Game, error) {
+	first, second := tournament.Entrant(pairing.FirstID), tournament.Entrant(pairing.SecondID)
+	if first == nil || second == nil {
+		return nil, errors.New("pairing has no opponent")
+	}
+	
+	game, err := gs.CreateGameWithSettings(NewPlayer(first.ID, first.Username, ""), tournament.Settings)
+	if err != nil {
+		return nil, err
+	}
+	if err := gs.JoinGame(game, NewPlayer(second.ID, second.Username, "")); err != nil {
+		return nil, err
+	}
+	
+	if err := tournament.AssignGame(pairing.Table, game.ID); err != nil {
+		return nil, err
+	}
+	return game, nil
 }
 
 // GetAvailableGames returns list of available games
@@ -62,8 +390,7 @@ func (gs *GameService) GetAvailableGames(games []*Game) []*Game {
 func (gs *GameService) GetPlayerGames(games []*Game, playerID string) []*Game {
 	var playerGames []*Game
 	for _, game := range games {
-		if (game.Player1 != nil && game.Player1.ID == playerID) ||
-		   (game.Player2 != nil && game.Player2.ID == playerID) {
+		if game.PlayerByID(playerID) != nil {
 			playerGames = append(playerGames, game)
 		}
 	}
@@ -105,7 +432,7 @@ func (gs *GameService) GetCurrentTurn(game *Game) *Player {
 
 // ValidateGameState проверяет валидность состояния игры
 func (gs *GameService) ValidateGameState(game *Game) error {
-	if game.Player1 == nil {
+	if len(game.Players) == 0 {
 		return errors.New("game must have at least one player")
 	}
 	
@@ -113,8 +440,8 @@ func (gs *GameService) ValidateGameState(game *Game) error {
 		return errors.New("game must have a board")
 	}
 	
-	if game.Status == GameStatusActive && game.Player2 == nil {
-		return errors.New("active game must have two players")
+	if game.Status == GameStatusActive && !game.IsFull() {
+		return errors.New("active game must have all players")
 	}
 	
 	if game.Status == GameStatusActive && game.CurrentTurn == nil {
@@ -127,17 +454,18 @@ func (gs *GameService) ValidateGameState(game *Game) error {
 // GetGameStatistics возвращает статистику игры
 func (gs *GameService) GetGameStatistics(game *Game) GameStatistics {
 	stats := GameStatistics{
-		GameID:     game.ID,
-		Status:     game.Status,
-		TotalMoves: 0,
-		Duration:   time.Duration(0),
+		GameID:       game.ID,
+		Status:       game.Status,
+		FinishReason: game.FinishReason,
+		TotalMoves:   0,
+		Duration:     time.Duration(0),
 	}
 	
 	// Подсчитываем количество ходов
 	if game.Board != nil {
-		for i := 0; i < 3; i++ {
-			for j := 0; j < 3; j++ {
-				if game.Board.GetCell(i, j) != "" {
+		for _, row := range game.Board.GetState() {
+			for _, cell := range row {
+				if cell != "" {
 					stats.TotalMoves++
 				}
 			}
@@ -158,10 +486,11 @@ func (gs *GameService) GetGameStatistics(game *Game) GameStatistics {
 
 // GameStatistics - статистика игры
 type GameStatistics struct {
-	GameID     string        `json:"game_id"`
-	Status     GameStatus    `json:"status"`
-	TotalMoves int           `json:"total_moves"`
-	Duration   time.Duration `json:"duration"`
+	GameID       string        `json:"game_id"`
+	Status       GameStatus    `json:"status"`
+	FinishReason FinishReason  `json:"finish_reason,omitempty"`
+	TotalMoves   int           `json:"total_moves"`
+	Duration     time.Duration `json:"duration"`
 }
 
 // GetRandomAvailablePosition возвращает случайную доступную позицию
@@ -170,12 +499,7 @@ func (gs *GameService) GetRandomAvailablePosition(game *Game) int {
 		return -1
 	}
 	
-	var available []int
-	for i := 0; i < 9; i++ {
-		if game.Board.IsValidPosition(i) {
-			available = append(available, i)
-		}
-	}
+	available := game.LegalPositions()
 	
 	if len(available) == 0 {
 		return -1
@@ -7,10 +7,12 @@ import (
 
 // Move - Value Object of a move
 type Move struct {
-	Position   int
-	Symbol     string
-	PlayerID   string
-	Timestamp  time.Time
+	Position   int       `json:"position"`
+	Symbol     string    `json:"symbol"`
+	PlayerID   string    `json:"player_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	// Spent is the milliseconds the player thought about the move, zero for moves recorded before
+	Spent      int64     `json:"spent_ms,omitempty"`
 }
 
 // NewMove creates a new move
@@ -29,12 +31,13 @@ func NewMove(position int, symbol, playerID string) (*Move, error) {
 
 // validateMove validates move
 func validateMove(position int, symbol string) error {
-	if position < 0 || position > 8 {
-		return errors.New("position must be between 0 and 8")
+	// The upper bound depends on the board and is checked by the ruleset
+	if position < 0 {
+		return errors.New("position must not be negative")
 	}
 	
-	if symbol != "X" && symbol != "O" {
-		return errors.New("symbol must be X or O")
+	if !ValidSymbol(symbol) {
+		return errors.New("symbol must be a single character")
 	}
 	
 	return nil
@@ -42,7 +45,16 @@ func validateMove(position int, symbol string) error {
 
 // IsValid checks if move is valid
 func (m *Move) IsValid() bool {
-	return m.Position >= 0 && m.Position <= 8 && 
-		   (m.Symbol == "X" || m.Symbol == "O") &&
+	return m.Position >= 0 && 
+		   ValidSymbol(m.Symbol) &&
 		   m.PlayerID != ""
-} 
\ No newline at end of file
+}
+
+// spentSince returns the milliseconds spent on the move.
+// Moves recorded before turns were timed count from the previous move.
+func (m *Move) spentSince(previousAt time.Time) int64 {
+	if m.Spent > 0 || previousAt.IsZero() || m.Timestamp.IsZero() {
+		return m.Spent
+	}
+	return m.Timestamp.Sub(previousAt).Milliseconds()
+}
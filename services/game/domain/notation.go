@@ -0,0 +1,473 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TTN (tic-tac-toe notation) is a PGN-like text record of a game:
+//
+//	[Game "01J9Z3Q4R8K2M5N7P0S6T1V3W4"]
+//	[Variant "classic"]
+//	[Size "3"]
+//	[K "3"]
+//	[Player1 "12"]
+//	[Symbol1 "X"]
+//	[Player2 "34"]
+//	[Symbol2 "O"]
+//	[TimeControl "-"]
+//	[Result "1-0"]
+//	[Termination "win_line"]
+//	[Date "2026.10.18"]
+//
+//	b2 a1 c3 a3 a2 c1 b1 1-0
+//
+// Cells are a column letter from "a" followed by a row number counted from the bottom row.
+// A move placing another symbol than the mover's own, as in wild games, ends with "=" and the symbol.
+// Results list the score of every seat: "1-0", "0-1", "1/2-1/2", "0-0-1", or "*" for unfinished games.
+// Move numbers such as "1." and comments in braces are ignored when parsing.
+
+// TTN header names
+const (
+	HeaderGame        = "Game"
+	HeaderVariant     = "Variant"
+	HeaderSize        = "Size"
+	HeaderK           = "K"
+	HeaderGravity     = "Gravity"
+	HeaderTimeControl = "TimeControl"
+	HeaderResult      = "Result"
+	HeaderTermination = "Termination"
+	HeaderDate        = "Date"
+	HeaderStartTime   = "StartTime"
+	HeaderEndTime     = "EndTime"
+)
+
+// ResultUnfinished - result of a game still in progress
+const ResultUnfinished = "*"
+
+// ttnDateFormat - layout of the Date header
+const ttnDateFormat = "2006.01.02"
+
+// ttnLineWidth - movetext is wrapped before this width
+const ttnLineWidth = 80
+
+var (
+	headerPattern = regexp.MustCompile(`^\[([A-Za-z0-9_]+)\s+"((?:[^"\\]|\\.)*)"\]$`)
+	movePattern   = regexp.MustCompile(`^([a-s])([1-9][0-9]?)(?:=(.+))?$`)
+	resultPattern = regexp.MustCompile(`^(?:\*|(?:1|0|1/2)(?:-(?:1|0|1/2))+)$`)
+	numberPattern = regexp.MustCompile(`^[0-9]+\.+$`)
+)
+
+// GameRecord - a game read from TTN
+type GameRecord struct {
+	Headers map[string]string `json:"headers"`
+	Moves   []RecordedMove    `json:"moves"`
+	// Result is the result token closing the movetext, if any
+	Result string `json:"result,omitempty"`
+}
+
+// RecordedMove - a move of a record in board coordinates
+type RecordedMove struct {
+	Column int `json:"column"`
+	// Row is counted from the bottom row
+	Row int `json:"row"`
+	// Symbol is only set when it differs from the mover's own
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// Position returns the board position of the move, or -1 if it is off a board of size
+func (m RecordedMove) Position(size int) int {
+	if m.Column >= size || m.Row >= size {
+		return -1
+	}
+	return (size-1-m.Row)*size + m.Column
+}
+
+// String returns the move in TTN
+func (m RecordedMove) String() string {
+	text := fmt.Sprintf("%c%d", 'a'+m.Column, m.Row+1)
+	if m.Symbol != "" {
+		text += "=" + m.Symbol
+	}
+	return text
+}
+
+// Coordinate returns the TTN cell of position on a board of size
+func Coordinate(position, size int) string {
+	return RecordedMove{Column: position % size, Row: size - 1 - position/size}.String()
+}
+
+// FormatTTN writes the game as a TTN record
+func FormatTTN(g *Game) (string, error) {
+	if len(g.Moves) == 0 && g.Board.filled > 0 {
+		return "", errors.New("game has no move history")
+	}
+
+	var b strings.Builder
+	header := func(name, value string) {
+		fmt.Fprintf(&b, "[%s %s]\n", name, strconv.Quote(value))
+	}
+
+	size := g.Board.Size()
+	header(HeaderGame, g.ID)
+	header(HeaderVariant, string(g.variant()))
+	header(HeaderSize, strconv.Itoa(size))
+	header(HeaderK, strconv.Itoa(g.Settings.lineLength()))
+	if g.Settings.Gravity {
+		header(HeaderGravity, "true")
+	}
+	for i, player := range g.Players {
+		header(fmt.Sprintf("Player%d", i+1), player.ID)
+		header(fmt.Sprintf("Symbol%d", i+1), player.Symbol)
+	}
+	header(HeaderTimeControl, "-")
+	header(HeaderResult, g.Result())
+	if g.FinishReason != "" {
+		header(HeaderTermination, string(g.FinishReason))
+	}
+	header(HeaderDate, g.CreatedAt.UTC().Format(ttnDateFormat))
+	if g.StartedAt != nil {
+		header(HeaderStartTime, g.StartedAt.UTC().Format(time.RFC3339))
+	}
+	if g.FinishedAt != nil {
+		header(HeaderEndTime, g.FinishedAt.UTC().Format(time.RFC3339))
+	}
+	b.WriteString("\n")
+
+	tokens := make([]string, 0, len(g.Moves)+1)
+	for _, move := range g.Moves {
+		token := Coordinate(move.Position, size)
+		if player := g.PlayerByID(move.PlayerID); player != nil && player.Symbol != move.Symbol {
+			token += "=" + move.Symbol
+		}
+		tokens = append(tokens, token)
+	}
+	tokens = append(tokens, g.Result())
+
+	width := 0
+	for i, token := range tokens {
+		if i > 0 && width+1+len(token) > ttnLineWidth {
+			b.WriteString("\n")
+			width = 0
+		} else if i > 0 {
+			b.WriteString(" ")
+			width++
+		}
+		b.WriteString(token)
+		width += len(token)
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// ParseTTN reads a TTN record. Moves are only checked against the rules when replayed.
+func ParseTTN(text string) (*GameRecord, error) {
+	record := &GameRecord{Headers: make(map[string]string)}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "[") {
+			break
+		}
+
+		match := headerPattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("line %d: invalid header", i+1)
+		}
+		value, err := strconv.Unquote(`"` + match[2] + `"`)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid header value: %w", i+1, err)
+		}
+		record.Headers[match[1]] = value
+	}
+
+	movetext, err := stripComments(strings.Join(lines[i:], "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range strings.Fields(movetext) {
+		if record.Result != "" {
+			return nil, fmt.Errorf("unexpected %q after the result", token)
+		}
+
+		switch {
+		case numberPattern.MatchString(token):
+			continue
+		case resultPattern.MatchString(token):
+			record.Result = token
+		default:
+			move, err := parseMove(token)
+			if err != nil {
+				return nil, err
+			}
+			record.Moves = append(record.Moves, move)
+		}
+	}
+
+	if header := record.Headers[HeaderResult]; header != "" && record.Result != "" && header != record.Result {
+		return nil, fmt.Errorf("result %s does not match the Result header %s", record.Result, header)
+	}
+	return record, nil
+}
+
+// stripComments removes comments in braces
+func stripComments(text string) (string, error) {
+	var b strings.Builder
+	depth := 0
+	for _, r := range text {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}':
+			if depth == 0 {
+				return "", errors.New("unbalanced comment")
+			}
+			depth--
+			b.WriteRune(' ')
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+
+	if depth > 0 {
+		return "", errors.New("unterminated comment")
+	}
+	return b.String(), nil
+}
+
+// parseMove reads a move token such as "b2" or "b2=O"
+func parseMove(token string) (RecordedMove, error) {
+	match := movePattern.FindStringSubmatch(token)
+	if match == nil {
+		return RecordedMove{}, fmt.Errorf("invalid move %q", token)
+	}
+
+	row, _ := strconv.Atoi(match[2])
+	move := RecordedMove{
+		Column: int(match[1][0] - 'a'),
+		Row:    row - 1,
+		Symbol: match[3],
+	}
+	if move.Symbol != "" && !ValidSymbol(move.Symbol) {
+		return RecordedMove{}, fmt.Errorf("invalid symbol in move %q", token)
+	}
+	return move, nil
+}
+
+// Settings returns the game settings described by the headers
+func (r *GameRecord) Settings() (GameSettings, error) {
+	settings := DefaultGameSettings()
+	if variant := r.Headers[HeaderVariant]; variant != "" {
+		settings.Variant = Variant(variant)
+	}
+
+	if gravity := r.Headers[HeaderGravity]; gravity != "" {
+		value, err := strconv.ParseBool(gravity)
+		if err != nil {
+			return GameSettings{}, fmt.Errorf("invalid Gravity header %q", gravity)
+		}
+		settings.Gravity = value
+	}
+
+	if players := len(r.players()); players > 2 {
+		settings.Players = players
+	}
+
+	size, err := r.intHeader(HeaderSize)
+	if err != nil {
+		return GameSettings{}, err
+	}
+	length, err := r.intHeader(HeaderK)
+	if err != nil {
+		return GameSettings{}, err
+	}
+	if settings.Variant == VariantConnect {
+		settings.BoardSize, settings.WinLength = size, length
+	}
+
+	if err := settings.Validate(); err != nil {
+		return GameSettings{}, err
+	}
+
+	// Other variants have fixed dimensions that the headers may only repeat
+	if size != 0 && size != settings.Rules().NewBoard().Size() {
+		return GameSettings{}, fmt.Errorf("size %d does not match variant %s", size, settings.Variant)
+	}
+	if length != 0 && length != settings.lineLength() {
+		return GameSettings{}, fmt.Errorf("k %d does not match variant %s", length, settings.Variant)
+	}
+	return settings, nil
+}
+
+// intHeader returns the numeric value of a header, 0 if it is missing
+func (r *GameRecord) intHeader(name string) (int, error) {
+	value, ok := r.Headers[name]
+	if !ok || value == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid %s header %q", name, value)
+	}
+	return number, nil
+}
+
+// players returns the IDs of the Player headers in seat order
+func (r *GameRecord) players() []string {
+	var ids []string
+	for seat := 1; seat <= MaxPlayers; seat++ {
+		id, ok := r.Headers[fmt.Sprintf("Player%d", seat)]
+		if !ok {
+			break
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Players creates the players of the record in seat order.
+// Seats without a Player header are named by their number.
+func (r *GameRecord) Players(count int) []*Player {
+	ids := r.players()
+	players := make([]*Player, count)
+	for i := range players {
+		id := strconv.Itoa(i + 1)
+		if i < len(ids) && ids[i] != "" {
+			id = ids[i]
+		}
+
+		players[i] = NewPlayer(id, "", "")
+		symbol := r.Headers[fmt.Sprintf("Symbol%d", i+1)]
+		if symbol == "" {
+			symbol = DefaultSymbols[i]
+		}
+		players[i].AssignSymbol(symbol)
+	}
+	return players
+}
+
+// Replay plays the recorded moves on a started game, then ends it as the result says
+// if the moves alone did not finish it
+func (r *GameRecord) Replay(game *Game) error {
+	size := game.Board.Size()
+	for i, move := range r.Moves {
+		if game.Status != GameStatusActive {
+			return fmt.Errorf("move %d (%s): game is already over", i+1, move)
+		}
+
+		position := move.Position(size)
+		if position < 0 {
+			return fmt.Errorf("move %d (%s): outside the board", i+1, move)
+		}
+
+		var err error
+		if move.Symbol == "" {
+			err = game.MakeMove(game.CurrentTurn, position)
+		} else {
+			err = game.MakeMoveWithSymbol(game.CurrentTurn, position, move.Symbol)
+		}
+		if err != nil {
+			return fmt.Errorf("move %d (%s): %w", i+1, move, err)
+		}
+	}
+
+	result := r.Result
+	if result == "" {
+		result = r.Headers[HeaderResult]
+	}
+	if result == "" || result == ResultUnfinished {
+		return nil
+	}
+
+	if game.Status == GameStatusFinished {
+		if result != game.Result() {
+			return fmt.Errorf("result %s does not match the moves, which end %s", result, game.Result())
+		}
+		return nil
+	}
+	return r.finish(game, result)
+}
+
+// finish ends an unfinished game with the result of the record
+func (r *GameRecord) finish(game *Game, result string) error {
+	scores := strings.Split(result, "-")
+	if len(scores) != len(game.Players) {
+		return fmt.Errorf("result %s does not list %d players", result, len(game.Players))
+	}
+
+	reason := FinishReason(r.Headers[HeaderTermination])
+	winner := -1
+	for seat, score := range scores {
+		switch {
+		case score == "1" && winner < 0:
+			winner = seat
+		case score == "1/2" && len(scores) == 2 && scores[1-seat] == "1/2":
+			if reason == "" {
+				reason = FinishReasonAgreedDraw
+			}
+			return game.raiseFinish(nil, reason)
+		case score != "0":
+			return fmt.Errorf("invalid result %s", result)
+		}
+	}
+	if winner < 0 {
+		return fmt.Errorf("invalid result %s", result)
+	}
+
+	switch reason {
+	case "":
+		reason = FinishReasonResign
+	case FinishReasonResign, FinishReasonTimeout, FinishReasonAbandon:
+	default:
+		return fmt.Errorf("termination %s does not match the moves", reason)
+	}
+
+	// Everybody but the winner loses, which eliminates players of larger games one by one
+	for seat, player := range game.Players {
+		if seat != winner && !game.isEliminated(player.ID) && game.Status == GameStatusActive {
+			if err := game.raiseLoss(player, reason); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Result returns the TTN result of the game
+func (g *Game) Result() string {
+	if g.Status != GameStatusFinished {
+		return ResultUnfinished
+	}
+
+	scores := make([]string, len(g.Players))
+	for seat, player := range g.Players {
+		switch {
+		case g.Winner == nil:
+			scores[seat] = "1/2"
+		case g.Winner.ID == player.ID:
+			scores[seat] = "1"
+		default:
+			scores[seat] = "0"
+		}
+	}
+	return strings.Join(scores, "-")
+}
+
+// variant returns the variant of the game, classic for games stored before variants
+func (g *Game) variant() Variant {
+	if g.Settings.Variant == "" {
+		return VariantClassic
+	}
+	return g.Settings.Variant
+}
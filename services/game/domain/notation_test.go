@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTTNRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers string
+		moves   string
+		result  string
+	}{
+		{"classic", `[Variant "classic"]`, "a1 a2 b1 b2 c1", "1-0"},
+		{"classic draw", `[Variant "classic"]`, "b2 a1 c3 a3 a2 c2 b1 b3 c1", "1/2-1/2"},
+		{"resigned", `[Variant "classic"] [Termination "resign"]`, "b2 a1", "1-0"},
+		{"timed out", `[Variant "classic"] [Termination "timeout"]`, "b2", "0-1"},
+		{"misere", `[Variant "misere"]`, "a1 a2 b1 b2 c1", "0-1"},
+		{"wild", `[Variant "wild"]`, "a1=O b1 c1=O", "1-0"},
+		{"ultimate", `[Variant "ultimate"]`, "e5 e4", "*"},
+		{"connect", `[Variant "connect"] [Size "6"] [K "4"] [Gravity "true"]`, "a1 b1 a2 b2 a3 b3 a4", "1-0"},
+		{"gomoku", `[Variant "gomoku"]`, "a1 a2 b1 b2 c1 c2 d1 d2 e1", "1-0"},
+		{"gomoku freestyle", `[Variant "gomoku_freestyle"]`, "a1 a2 b1 b2 c1 c2 d1 d2 f1 f2 e1", "1-0"},
+		{"three players", `[Variant "connect"] [Player1 "1"] [Player2 "2"] [Player3 "3"]`, "a1 a2 a3 b1 b2 b3 c1 c2 c3 d1", "1-0-0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := strings.ReplaceAll(tt.headers, "] [", "]\n[") + "\n\n" + tt.moves + " " + tt.result + "\n"
+			game, record, err := NewGameService().ImportGame(text)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			if game.Result() != tt.result {
+				t.Fatalf("result %s, want %s", game.Result(), tt.result)
+			}
+
+			formatted, err := FormatTTN(game)
+			if err != nil {
+				t.Fatalf("format: %v", err)
+			}
+			reread, err := ParseTTN(formatted)
+			if err != nil {
+				t.Fatalf("parse %q: %v", formatted, err)
+			}
+
+			if !slices.Equal(reread.Moves, record.Moves) {
+				t.Errorf("moves %v, want %v", reread.Moves, record.Moves)
+			}
+			if reread.Result != tt.result || reread.Headers[HeaderResult] != tt.result {
+				t.Errorf("result %s with header %s, want %s", reread.Result, reread.Headers[HeaderResult], tt.result)
+			}
+			if reread.Headers[HeaderGame] != game.ID {
+				t.Errorf("game header %q, want %q", reread.Headers[HeaderGame], game.ID)
+			}
+
+			settings, err := reread.Settings()
+			if err != nil {
+				t.Fatalf("settings: %v", err)
+			}
+			if settings.Rules().NewBoard().Size() != game.Board.Size() || settings.Gravity != game.Settings.Gravity {
+				t.Errorf("settings %+v do not match the game %+v", settings, game.Settings)
+			}
+		})
+	}
+}
+
+func TestParseTTNIgnoresNumbersAndComments(t *testing.T) {
+	record, err := ParseTTN("[Variant \"classic\"]\n\n1. b2 {center} a1 2. c3 {corner {nested}} *\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []RecordedMove{{Column: 1, Row: 1}, {Column: 0, Row: 0}, {Column: 2, Row: 2}}
+	if !slices.Equal(record.Moves, want) || record.Result != ResultUnfinished {
+		t.Fatalf("moves %v with result %q", record.Moves, record.Result)
+	}
+}
+
+func TestParseTTNRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"invalid header", "[Variant classic]\n\nb2 *"},
+		{"unterminated header value", `[Variant "classic\"]`},
+		{"invalid move", "b2 z9 *"},
+		{"row zero", "b0 *"},
+		{"invalid symbol", "b2=XO *"},
+		{"move after the result", "b2 1-0 a1"},
+		{"result mismatch", "[Result \"0-1\"]\n\nb2 1-0"},
+		{"unbalanced comment", "b2 } *"},
+		{"unterminated comment", "b2 { a1 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if record, err := ParseTTN(tt.text); err == nil {
+				t.Fatalf("parsed %+v", record)
+			}
+		})
+	}
+}
+
+func TestImportGameRejectsInvalidRecords(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"unknown variant", "[Variant \"chess\"]\n\ne4 *"},
+		{"size of a fixed variant", "[Variant \"classic\"]\n[Size \"4\"]\n\nb2 *"},
+		{"k of a fixed variant", "[Variant \"gomoku\"]\n[K \"4\"]\n\nh8 *"},
+		{"invalid size", "[Variant \"connect\"]\n[Size \"x\"]\n\na1 *"},
+		{"invalid gravity", "[Variant \"connect\"]\n[Gravity \"maybe\"]\n\na1 *"},
+		{"occupied cell", "b2 b2 *"},
+		{"outside the board", "b2 d4 *"},
+		{"floating piece", "[Variant \"connect\"]\n[Gravity \"true\"]\n\na2 *"},
+		{"move after the end", "a1 a2 b1 b2 c1 c2 1-0"},
+		{"result contradicting the moves", "a1 a2 b1 b2 c1 0-1"},
+		{"result of other seats", "b2 1-0-0"},
+		{"termination contradicting the moves", "[Termination \"win_line\"]\n\nb2 1-0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := NewGameService().ImportGame(tt.text); err == nil {
+				t.Fatal("imported an invalid record")
+			}
+		})
+	}
+}
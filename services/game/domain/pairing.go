@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"sort"
+)
+
+// swissSearchBudget bounds the pairings tried when avoiding rematches in a Swiss round
+const swissSearchBudget = 100000
+
+// roundRobinPairs returns the pairs of a round of the circle method: the top seed stays
+// in place while everyone else rotates by one seat per round. With an odd field the
+// player facing the empty seat has a bye. Pairs are [first, second], byes come last.
+func roundRobinPairs(entrants []Entrant, number int) [][2]string {
+	ids := make([]string, 0, len(entrants)+1)
+	for _, entrant := range entrants {
+		ids = append(ids, entrant.ID)
+	}
+	if len(ids)%2 == 1 {
+		ids = append(ids, "")
+	}
+
+	n := len(ids)
+	shift := number - 1
+	seats := make([]string, n)
+	seats[0] = ids[0]
+	for i := 1; i < n; i++ {
+		seats[i] = ids[1+(i-1+shift)%(n-1)]
+	}
+
+	var pairs, byes [][2]string
+	for i := 0; i < n/2; i++ {
+		first, second := seats[i], seats[n-1-i]
+		// alternate who moves first from round to round
+		if (i+shift)%2 == 1 {
+			first, second = second, first
+		}
+
+		switch {
+		case first == "":
+			byes = append(byes, [2]string{second, ""})
+		case second == "":
+			byes = append(byes, [2]string{first, ""})
+		default:
+			pairs = append(pairs, [2]string{first, second})
+		}
+	}
+	return append(pairs, byes...)
+}
+
+// swissPairs pairs players in order of their score, each with the best placed player
+// they have not met yet. With an odd field the lowest placed player without a bye sits out.
+// Rematches are only allowed when no pairing avoids them. The player who moved first
+// less often moves first, the better placed one on a tie.
+func (t *Tournament) swissPairs() [][2]string {
+	points := make(map[string]float64, len(t.Entrants))
+	for _, standing := range t.Standings() {
+		points[standing.ID] = standing.Points
+	}
+
+	order := make([]Entrant, len(t.Entrants))
+	copy(order, t.Entrants)
+	sort.SliceStable(order, func(i, j int) bool {
+		if points[order[i].ID] != points[order[j].ID] {
+			return points[order[i].ID] > points[order[j].ID]
+		}
+		return order[i].Seed < order[j].Seed
+	})
+
+	met := make(map[[2]string]bool)
+	firsts := make(map[string]int)
+	hadBye := make(map[string]bool)
+	for _, round := range t.Rounds {
+		for _, pairing := range round.Pairings {
+			if pairing.IsBye() {
+				hadBye[pairing.FirstID] = true
+				continue
+			}
+			met[[2]string{pairing.FirstID, pairing.SecondID}] = true
+			met[[2]string{pairing.SecondID, pairing.FirstID}] = true
+			firsts[pairing.FirstID]++
+		}
+	}
+
+	ids := make([]string, 0, len(order))
+	for _, entrant := range order {
+		ids = append(ids, entrant.ID)
+	}
+
+	bye := ""
+	if len(ids)%2 == 1 {
+		at := len(ids) - 1
+		for i := len(ids) - 1; i >= 0; i-- {
+			if !hadBye[ids[i]] {
+				at = i
+				break
+			}
+		}
+		bye = ids[at]
+		ids = append(ids[:at:at], ids[at+1:]...)
+	}
+
+	budget := swissSearchBudget
+	pairs, ok := matchUnmet(ids, met, &budget)
+	if !ok {
+		pairs = nil
+		for i := 0; i+1 < len(ids); i += 2 {
+			pairs = append(pairs, [2]string{ids[i], ids[i+1]})
+		}
+	}
+
+	for i, pair := range pairs {
+		if firsts[pair[1]] < firsts[pair[0]] {
+			pairs[i] = [2]string{pair[1], pair[0]}
+		}
+	}
+	if bye != "" {
+		pairs = append(pairs, [2]string{bye, ""})
+	}
+	return pairs
+}
+
+// matchUnmet pairs players in order, each with the first later player they have not met.
+// It backtracks when the remaining players cannot be paired and gives up once
+// budget attempts are spent.
+func matchUnmet(ids []string, met map[[2]string]bool, budget *int) ([][2]string, bool) {
+	if len(ids) == 0 {
+		return nil, true
+	}
+
+	top := ids[0]
+	for i := 1; i < len(ids); i++ {
+		if *budget <= 0 {
+			return nil, false
+		}
+		*budget--
+
+		if met[[2]string{top, ids[i]}] {
+			continue
+		}
+
+		rest := make([]string, 0, len(ids)-2)
+		rest = append(rest, ids[1:i]...)
+		rest = append(rest, ids[i+1:]...)
+		if pairs, ok := matchUnmet(rest, met, budget); ok {
+			return append([][2]string{{top, ids[i]}}, pairs...), true
+		}
+	}
+	return nil, false
+}
+
+// knockoutPairs returns the matches of a knockout round. The first round places seeds so
+// that the best meet as late as possible, with byes for the top seeds when the field is not
+// a power of two. Later rounds pair the winners of neighbouring matches. The better seed
+// moves first.
+func (t *Tournament) knockoutPairs(number int) [][2]string {
+	var pairs [][2]string
+	if number == 1 {
+		bySeed := make(map[int]string, len(t.Entrants))
+		for _, entrant := range t.Entrants {
+			bySeed[entrant.Seed] = entrant.ID
+		}
+
+		order := seedOrder(bracketSize(len(t.Entrants)))
+		for i := 0; i < len(order); i += 2 {
+			// the second seed of a match is always the worse one, so only it can be missing
+			pairs = append(pairs, [2]string{bySeed[order[i]], bySeed[order[i+1]]})
+		}
+		return pairs
+	}
+
+	previous := t.Rounds[number-2].Pairings
+	for i := 0; i+1 < len(previous); i += 2 {
+		first, second := previous[i].WinnerID, previous[i+1].WinnerID
+		if t.Entrant(second).Seed < t.Entrant(first).Seed {
+			first, second = second, first
+		}
+		pairs = append(pairs, [2]string{first, second})
+	}
+	return pairs
+}
+
+// seedOrder returns seeds 1 to size in bracket order: neighbours meet in the first round
+// and seeds 1 and 2 can only meet in the final
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		sum := 2*len(order) + 1
+		next := make([]int, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, sum-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// bracketSize returns the smallest power of two holding the players
+func bracketSize(players int) int {
+	size := 1
+	for size < players {
+		size *= 2
+	}
+	return size
+}
+
+// log2 returns the base 2 logarithm of a power of two
+func log2(n int) int {
+	k := 0
+	for n > 1 {
+		n /= 2
+		k++
+	}
+	return k
+}
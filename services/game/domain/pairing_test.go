@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func newEntrants(count int) []Entrant {
+	entrants := make([]Entrant, count)
+	for i := range entrants {
+		entrants[i] = Entrant{ID: fmt.Sprint(i + 1), Seed: i + 1}
+	}
+	return entrants
+}
+
+func pairKey(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+func TestRoundRobinPairsMeetEveryoneOnce(t *testing.T) {
+	for _, players := range []int{2, 4, 5, 6, 7} {
+		t.Run(fmt.Sprint(players, " players"), func(t *testing.T) {
+			entrants := newEntrants(players)
+			rounds := players - 1
+			if players%2 == 1 {
+				rounds = players
+			}
+
+			met := make(map[[2]string]int)
+			byes := make(map[string]int)
+			for number := 1; number <= rounds; number++ {
+				seen := make(map[string]bool)
+				for _, pair := range roundRobinPairs(entrants, number) {
+					for _, id := range pair {
+						if id == "" {
+							continue
+						}
+						if seen[id] {
+							t.Fatalf("round %d: %s paired twice", number, id)
+						}
+						seen[id] = true
+					}
+
+					if pair[1] == "" {
+						byes[pair[0]]++
+						continue
+					}
+					met[pairKey(pair[0], pair[1])]++
+				}
+				if len(seen) != players {
+					t.Fatalf("round %d pairs %d of %d players", number, len(seen), players)
+				}
+			}
+
+			if len(met) != players*(players-1)/2 {
+				t.Fatalf("%d distinct pairs, want %d", len(met), players*(players-1)/2)
+			}
+			for pair, count := range met {
+				if count != 1 {
+					t.Errorf("%v met %d times", pair, count)
+				}
+			}
+			for _, entrant := range entrants {
+				if want := players % 2; byes[entrant.ID] != want {
+					t.Errorf("%s had %d byes, want %d", entrant.ID, byes[entrant.ID], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRoundRobinByesComeLast(t *testing.T) {
+	for number := 1; number <= 5; number++ {
+		pairs := roundRobinPairs(newEntrants(5), number)
+		if last := pairs[len(pairs)-1]; last[1] == "" {
+			continue
+		}
+		t.Fatalf("round %d: bye is not last in %v", number, pairs)
+	}
+}
+
+func TestSwissPairsAvoidRematches(t *testing.T) {
+	// Everybody drew, so pairing by placement alone would repeat both games
+	tournament := &Tournament{
+		Format:   FormatSwiss,
+		Status:   TournamentStatusRunning,
+		Entrants: newEntrants(4),
+		Rounds: []Round{{Number: 1, Pairings: []Pairing{
+			{Table: 1, FirstID: "1", SecondID: "2", Result: PairingDraw},
+			{Table: 2, FirstID: "3", SecondID: "4", Result: PairingDraw},
+		}}},
+	}
+
+	pairs := tournament.swissPairs()
+	want := [][2]string{{"1", "3"}, {"2", "4"}}
+	if !slices.Equal(pairs, want) {
+		t.Fatalf("pairs %v, want %v", pairs, want)
+	}
+}
+
+func TestSwissPairsAllowRematchesOnlyWhenUnavoidable(t *testing.T) {
+	// Two players can only meet again, with the one who has not moved first yet moving first
+	tournament := &Tournament{
+		Format:   FormatSwiss,
+		Status:   TournamentStatusRunning,
+		Entrants: newEntrants(2),
+		Rounds: []Round{{Number: 1, Pairings: []Pairing{
+			{Table: 1, FirstID: "1", SecondID: "2", Result: PairingFirstWon, WinnerID: "1"},
+		}}},
+	}
+
+	pairs := tournament.swissPairs()
+	if want := [][2]string{{"2", "1"}}; !slices.Equal(pairs, want) {
+		t.Fatalf("pairs %v, want %v", pairs, want)
+	}
+}
+
+func TestSwissPairsByeGoesToLowestPlacedWithoutBye(t *testing.T) {
+	tournament := &Tournament{
+		Format:   FormatSwiss,
+		Status:   TournamentStatusRunning,
+		Entrants: newEntrants(5),
+		Rounds: []Round{{Number: 1, Pairings: []Pairing{
+			{Table: 1, FirstID: "1", SecondID: "2", Result: PairingFirstWon, WinnerID: "1"},
+			{Table: 2, FirstID: "3", SecondID: "4", Result: PairingFirstWon, WinnerID: "3"},
+			{Table: 3, FirstID: "5", Result: PairingBye, WinnerID: "5"},
+		}}},
+	}
+
+	// 1, 3 and 5 lead with a point each; 5 already had a bye, so the last placed 4 sits out
+	pairs := tournament.swissPairs()
+	want := [][2]string{{"1", "3"}, {"5", "2"}, {"4", ""}}
+	if !slices.Equal(pairs, want) {
+		t.Fatalf("pairs %v, want %v", pairs, want)
+	}
+}
+
+func TestSeedOrder(t *testing.T) {
+	tests := []struct {
+		size int
+		want []int
+	}{
+		{2, []int{1, 2}},
+		{4, []int{1, 4, 2, 3}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+	}
+
+	for _, tt := range tests {
+		if order := seedOrder(tt.size); !slices.Equal(order, tt.want) {
+			t.Errorf("seedOrder(%d) = %v, want %v", tt.size, order, tt.want)
+		}
+	}
+}
+
+func TestKnockoutPairsSeedFirstRoundWithByesForTopSeeds(t *testing.T) {
+	tournament := &Tournament{Format: FormatKnockout, Status: TournamentStatusRunning, Entrants: newEntrants(6)}
+
+	pairs := tournament.knockoutPairs(1)
+	want := [][2]string{{"1", ""}, {"4", "5"}, {"2", ""}, {"3", "6"}}
+	if !slices.Equal(pairs, want) {
+		t.Fatalf("pairs %v, want %v", pairs, want)
+	}
+}
+
+func TestKnockoutPairsAdvanceWinnersWithBetterSeedFirst(t *testing.T) {
+	tournament := &Tournament{
+		Format:   FormatKnockout,
+		Status:   TournamentStatusRunning,
+		Entrants: newEntrants(4),
+		Rounds: []Round{{Number: 1, Pairings: []Pairing{
+			{Table: 1, FirstID: "1", SecondID: "4", Result: PairingSecondWon, WinnerID: "4"},
+			{Table: 2, FirstID: "2", SecondID: "3", Result: PairingFirstWon, WinnerID: "2"},
+		}}},
+	}
+
+	pairs := tournament.knockoutPairs(2)
+	if want := [][2]string{{"2", "4"}}; !slices.Equal(pairs, want) {
+		t.Fatalf("pairs %v, want %v", pairs, want)
+	}
+}
+
+func TestBracketSizeAndLog2(t *testing.T) {
+	for players, want := range map[int]int{1: 1, 2: 2, 3: 4, 5: 8, 8: 8, 9: 16} {
+		if size := bracketSize(players); size != want {
+			t.Errorf("bracketSize(%d) = %d, want %d", players, size, want)
+		}
+	}
+	if rounds := log2(16); rounds != 4 {
+		t.Errorf("log2(16) = %d, want 4", rounds)
+	}
+}
@@ -1,15 +1,21 @@
 package domain
 
 import (
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
+// DefaultSymbols - symbols of players who did not choose one, by seat
+var DefaultSymbols = []string{"X", "O", "Y", "Z"}
+
 // Player - player entity
 type Player struct {
 	ID        string
 	Username  string
 	Email     string
-	Symbol    string // "X" or "O"
+	Symbol    string // a single character, "X" and "O" in two-player games
 	CreatedAt time.Time
 }
 
@@ -30,5 +36,16 @@ func (p *Player) AssignSymbol(symbol string) {
 
 // IsValidSymbol checks if symbol is valid
 func (p *Player) IsValidSymbol() bool {
-	return p.Symbol == "X" || p.Symbol == "O"
+	return ValidSymbol(p.Symbol)
+}
+
+// ValidSymbol checks that symbol is a single visible character.
+// "-" and "," are reserved for storing boards and player lists.
+func ValidSymbol(symbol string) bool {
+	if utf8.RuneCountInString(symbol) != 1 || strings.ContainsAny(symbol, "-,") {
+		return false
+	}
+	
+	r, _ := utf8.DecodeRuneInString(symbol)
+	return unicode.IsGraphic(r) && !unicode.IsSpace(r)
 } 
\ No newline at end of file
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReplayFrame - state of a game after a number of plies, derived from its move list
+type ReplayFrame struct {
+	Ply   int        `json:"ply"`
+	Plies int        `json:"plies"`
+	Board [][]string `json:"board"`
+	// Turn is the player to move, nil once a finished game reached its last ply
+	Turn *Player `json:"turn,omitempty"`
+	// Move is the move leading to this frame, nil at ply 0
+	Move *Move `json:"move,omitempty"`
+	// Clocks hold the milliseconds every player spent on their moves so far
+	Clocks map[string]int64 `json:"clocks"`
+	// Result is set on the last frame of a finished game
+	Result       string       `json:"result,omitempty"`
+	FinishReason FinishReason `json:"finish_reason,omitempty"`
+}
+
+// ErrNoMoveHistory is returned for games stored before moves were recorded
+var ErrNoMoveHistory = errors.New("game has no move history")
+
+// ReplayAt returns the frame of the game after ply moves
+func (g *Game) ReplayAt(ply int) (*ReplayFrame, error) {
+	if ply < 0 || ply > len(g.Moves) {
+		return nil, fmt.Errorf("ply must be between 0 and %d", len(g.Moves))
+	}
+
+	var frame *ReplayFrame
+	err := g.Replay(func(f ReplayFrame) bool {
+		if f.Ply < ply {
+			return true
+		}
+		frame = &f
+		return false
+	})
+	return frame, err
+}
+
+// Replay replays the move list, calling visit with the frame of every ply from 0
+// until visit returns false
+func (g *Game) Replay(visit func(ReplayFrame) bool) error {
+	if len(g.Moves) == 0 && g.Board.filled > 0 {
+		return ErrNoMoveHistory
+	}
+
+	board := g.rules().NewBoard()
+	clocks := make(map[string]int64, len(g.Players))
+	for _, player := range g.Players {
+		clocks[player.ID] = 0
+	}
+
+	var lastMoveAt time.Time
+	if g.StartedAt != nil {
+		lastMoveAt = *g.StartedAt
+	}
+
+	for ply := 0; ply <= len(g.Moves); ply++ {
+		frame := ReplayFrame{
+			Ply:    ply,
+			Plies:  len(g.Moves),
+			Clocks: make(map[string]int64, len(clocks)),
+		}
+
+		if ply > 0 {
+			move := g.Moves[ply-1]
+			if err := board.MakeMove(move.Position, move.Symbol); err != nil {
+				return fmt.Errorf("move %d: %w", ply, err)
+			}
+			clocks[move.PlayerID] += move.spentSince(lastMoveAt)
+			lastMoveAt = move.Timestamp
+			frame.Move = &move
+		}
+
+		switch {
+		case ply < len(g.Moves):
+			frame.Turn = g.PlayerByID(g.Moves[ply].PlayerID)
+		case g.Status == GameStatusFinished:
+			frame.Result, frame.FinishReason = g.Result(), g.FinishReason
+		default:
+			frame.Turn = g.CurrentTurn
+		}
+
+		for id, spent := range clocks {
+			frame.Clocks[id] = spent
+		}
+		frame.Board = board.GetState()
+
+		if !visit(frame) {
+			return nil
+		}
+	}
+	return nil
+}
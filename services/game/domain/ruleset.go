@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Variant - rules a game is played by
+type Variant string
+
+const (
+	VariantClassic  Variant = "classic"
+	VariantMisere   Variant = "misere"
+	VariantWild     Variant = "wild"
+	VariantUltimate Variant = "ultimate"
+	VariantConnect  Variant = "connect"
+	VariantGomoku   Variant = "gomoku"
+
+	// VariantGomokuFreestyle - gomoku where lines longer than five also win
+	VariantGomokuFreestyle Variant = "gomoku_freestyle"
+)
+
+const (
+	// GomokuBoardSize - rows and columns of a gomoku board
+	GomokuBoardSize = 15
+	// GomokuWinLength - stones in a row that win gomoku
+	GomokuWinLength = 5
+
+	// DefaultConnectBoardSize - rows and columns of a connect board unless chosen
+	DefaultConnectBoardSize = 7
+	// DefaultConnectWinLength - pieces in a row that win connect unless chosen
+	DefaultConnectWinLength = 4
+
+	// MaxBoardSize - largest board a game may be played on
+	MaxBoardSize = 19
+)
+
+// MoveResult - outcome of a move as decided by a ruleset
+type MoveResult int
+
+const (
+	// MoveResultNone - the game goes on
+	MoveResultNone MoveResult = iota
+	// MoveResultWin - the player who moved wins
+	MoveResultWin
+	// MoveResultLoss - the player who moved loses
+	MoveResultLoss
+	// MoveResultDraw - the game ends without a winner
+	MoveResultDraw
+)
+
+// Ruleset - rules of a variant the Game aggregate delegates to
+type Ruleset interface {
+	// NewBoard creates the empty board the variant is played on
+	NewBoard() *Board
+	// ValidateMove checks that player may make move after the last move
+	ValidateMove(board *Board, last *Move, player *Player, move Move) error
+	// Outcome decides the result of move after it was placed on the board
+	Outcome(board *Board, move Move) MoveResult
+}
+
+// classicRules - three in a row of your own symbol wins
+type classicRules struct{}
+
+// NewBoard creates a 3×3 board
+func (classicRules) NewBoard() *Board {
+	return NewBoard()
+}
+
+// ValidateMove checks that the player places their own symbol on a free cell
+func (classicRules) ValidateMove(board *Board, last *Move, player *Player, move Move) error {
+	if move.Symbol != player.Symbol {
+		return fmt.Errorf("you play %s", player.Symbol)
+	}
+	return checkPosition(board, move.Position)
+}
+
+// Outcome wins on a completed line and draws on a full board
+func (classicRules) Outcome(board *Board, move Move) MoveResult {
+	if board.CompletesLine(move.Position, board.Size(), false) {
+		return MoveResultWin
+	}
+	if board.IsFull() {
+		return MoveResultDraw
+	}
+	return MoveResultNone
+}
+
+// misereRules - completing a line loses
+type misereRules struct {
+	classicRules
+}
+
+// Outcome loses on a completed line and draws on a full board
+func (misereRules) Outcome(board *Board, move Move) MoveResult {
+	if board.CompletesLine(move.Position, board.Size(), false) {
+		return MoveResultLoss
+	}
+	if board.IsFull() {
+		return MoveResultDraw
+	}
+	return MoveResultNone
+}
+
+// wildRules - players choose X or O on every move, any completed line wins
+type wildRules struct {
+	classicRules
+}
+
+// ValidateMove checks that either symbol is placed on a free cell
+func (wildRules) ValidateMove(board *Board, last *Move, player *Player, move Move) error {
+	if move.Symbol != "X" && move.Symbol != "O" {
+		return errors.New("symbol must be X or O")
+	}
+	return checkPosition(board, move.Position)
+}
+
+// lineRules - length in a row wins on a size×size board.
+// Exact rules do not count lines longer than length.
+type lineRules struct {
+	classicRules
+	size   int
+	length int
+	exact  bool
+}
+
+// NewBoard creates a size×size board
+func (r lineRules) NewBoard() *Board {
+	return NewBoardOfSize(r.size)
+}
+
+// Outcome wins on a line of the required length through move and draws on a full board
+func (r lineRules) Outcome(board *Board, move Move) MoveResult {
+	if board.CompletesLine(move.Position, r.length, r.exact) {
+		return MoveResultWin
+	}
+	if board.IsFull() {
+		return MoveResultDraw
+	}
+	return MoveResultNone
+}
+
+// gravityRules - pieces drop to the lowest free cell of their column
+type gravityRules struct {
+	Ruleset
+}
+
+// ValidateMove additionally checks that the position is the lowest free cell of its column
+func (r gravityRules) ValidateMove(board *Board, last *Move, player *Player, move Move) error {
+	if err := r.Ruleset.ValidateMove(board, last, player, move); err != nil {
+		return err
+	}
+
+	position, err := board.DropPosition(move.Position % board.Size())
+	if err != nil {
+		return err
+	}
+	if position != move.Position {
+		return errors.New("piece must drop to the lowest free cell of the column")
+	}
+	return nil
+}
+
+// checkPosition checks that position is a free cell of board
+func checkPosition(board *Board, position int) error {
+	if position < 0 || position >= board.Size()*board.Size() {
+		return errors.New("invalid position")
+	}
+	if !board.IsValidPosition(position) {
+		return errors.New("position already occupied")
+	}
+	return nil
+}
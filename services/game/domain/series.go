@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// SeriesScore - score of a series of rematches
+type SeriesScore struct {
+	SeriesID    string         `json:"series_id"`
+	BestOf      int            `json:"best_of"`
+	GamesPlayed int            `json:"games_played"`
+	Wins        map[string]int `json:"wins"`
+	Draws       int            `json:"draws"`
+	WinnerID    string         `json:"winner_id,omitempty"`
+}
+
+// IsDecided checks if a player has won the majority of the series
+func (s SeriesScore) IsDecided() bool {
+	return s.WinnerID != ""
+}
+
+// AcceptRematch records that player wants a rematch and reports whether both players agreed
+func (g *Game) AcceptRematch(player *Player) (bool, error) {
+	if g.Status != GameStatusFinished {
+		return false, errors.New("game is not finished")
+	}
+	
+	if !g.isPlayerInGame(player) {
+		return false, errors.New("player is not in this game")
+	}
+	
+	if len(g.Players) < 2 {
+		return false, errors.New("game has no opponent")
+	}
+	
+	if g.NextGameID != "" {
+		return false, errors.New("rematch already created")
+	}
+	
+	if !g.hasAcceptedRematch(player.ID) {
+		err := g.raise(RematchAccepted{
+			EventBase: g.eventBase(),
+			PlayerID:  player.ID,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	
+	return g.allAcceptedRematch(), nil
+}
+
+// allAcceptedRematch checks if every player has accepted a rematch
+func (g *Game) allAcceptedRematch() bool {
+	for _, player := range g.Players {
+		if !g.hasAcceptedRematch(player.ID) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAcceptedRematch checks if player has accepted a rematch
+func (g *Game) hasAcceptedRematch(playerID string) bool {
+	for _, id := range g.RematchAcceptedBy {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// newRematch creates an active follow-up game with seats rotated by one.
+// Chosen symbols stay with their players while default ones are handed out
+// again by seat, so two players with X and O swap symbols.
+// An empty seriesID starts a new series.
+func (g *Game) newRematch(id, seriesID string) (*Game, error) {
+	if seriesID == "" {
+		seriesID = id
+	}
+	
+	players := append(append([]*Player(nil), g.Players[1:]...), g.Players[0])
+	symbols := rematchSymbols(players)
+	
+	rematch := &Game{}
+	for seat, player := range players {
+		var event Event = PlayerJoined{
+			EventBase: EventBase{GameID: id, OccurredAt: time.Now()},
+			PlayerID:  player.ID,
+			Username:  player.Username,
+			Symbol:    symbols[seat],
+		}
+		if seat == 0 {
+			event = GameCreated{
+				EventBase:      EventBase{GameID: id, OccurredAt: time.Now()},
+				PlayerID:       player.ID,
+				Username:       player.Username,
+				Symbol:         symbols[seat],
+				Settings:       g.Settings,
+				SeriesID:       seriesID,
+				PreviousGameID: g.ID,
+			}
+		}
+		
+		if err := rematch.raise(event); err != nil {
+			return nil, err
+		}
+	}
+	
+	err := g.raise(RematchCreated{
+		EventBase:  g.eventBase(),
+		NextGameID: rematch.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return rematch, nil
+}
+
+
+// rematchSymbols returns the symbols of players in seat order.
+// Players keep chosen symbols and get the first free default one otherwise.
+func rematchSymbols(players []*Player) []string {
+	taken := make(map[string]bool)
+	for _, player := range players {
+		if !isDefaultSymbol(player.Symbol) {
+			taken[player.Symbol] = true
+		}
+	}
+	
+	symbols := make([]string, len(players))
+	for seat, player := range players {
+		if !isDefaultSymbol(player.Symbol) {
+			symbols[seat] = player.Symbol
+			continue
+		}
+		for _, symbol := range DefaultSymbols {
+			if !taken[symbol] {
+				symbols[seat] = symbol
+				taken[symbol] = true
+				break
+			}
+		}
+	}
+	return symbols
+}
+
+// isDefaultSymbol checks if symbol is one of DefaultSymbols
+func isDefaultSymbol(symbol string) bool {
+	for _, s := range DefaultSymbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
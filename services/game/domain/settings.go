@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GameSettings - Value Object of options chosen at game creation
+type GameSettings struct {
+	BestOf  int     `json:"best_of"`
+	Variant Variant `json:"variant,omitempty"`
+
+	// Board size and win length of the connect variant
+	BoardSize int `json:"board_size,omitempty"`
+	WinLength int `json:"win_length,omitempty"`
+
+	// Gravity drops pieces to the lowest free cell of the chosen column
+	Gravity bool `json:"gravity,omitempty"`
+
+	// Players is the number of seats, two unless chosen
+	Players int `json:"players,omitempty"`
+
+	// TakebackLimit is the number of takebacks each player may have accepted
+	TakebackLimit int `json:"takeback_limit,omitempty"`
+
+	// Invited are the IDs of the only players who may join, anyone may join when empty
+	Invited []string `json:"invited,omitempty"`
+
+	// AI seats a computer opponent of this difficulty in the second seat.
+	// Games against the AI are casual: unrated and without a takeback limit.
+	AI AIDifficulty `json:"ai,omitempty"`
+}
+
+const (
+	// MaxPlayers - largest number of players in a game
+	MaxPlayers = 4
+
+	// DefaultTakebackLimit - takebacks per player unless chosen
+	DefaultTakebackLimit = 3
+	// UnlimitedTakebacks - takeback limit of casual games
+	UnlimitedTakebacks = -1
+)
+
+// DefaultGameSettings returns settings of a single classic game
+func DefaultGameSettings() GameSettings {
+	return GameSettings{
+		BestOf:        1,
+		Variant:       VariantClassic,
+		TakebackLimit: DefaultTakebackLimit,
+	}
+}
+
+// Validate checks that settings are consistent
+func (s GameSettings) Validate() error {
+	if s.BestOf < 1 || s.BestOf%2 == 0 {
+		return errors.New("best_of must be a positive odd number")
+	}
+	
+	switch s.Variant {
+	case VariantClassic, VariantMisere, VariantWild, VariantUltimate, VariantGomoku, VariantGomokuFreestyle:
+		if s.BoardSize != 0 || s.WinLength != 0 {
+			return errors.New("board_size and win_length apply to the connect variant only")
+		}
+	case VariantConnect:
+		size, length := s.connectDimensions()
+		if size < 3 || size > MaxBoardSize {
+			return fmt.Errorf("board_size must be between 3 and %d", MaxBoardSize)
+		}
+		if length < 3 || length > size {
+			return errors.New("win_length must be between 3 and board_size")
+		}
+	default:
+		return fmt.Errorf("unknown variant %q", s.Variant)
+	}
+	
+	if s.Gravity && s.Variant == VariantUltimate {
+		return errors.New("ultimate cannot be played with gravity")
+	}
+	
+	if s.TakebackLimit < UnlimitedTakebacks {
+		return errors.New("takeback_limit must not be negative, or -1 for unlimited takebacks")
+	}
+	
+	if s.Players != 0 && (s.Players < 2 || s.Players > MaxPlayers) {
+		return fmt.Errorf("players must be between 2 and %d", MaxPlayers)
+	}
+	
+	if len(s.Invited) > s.PlayerCount()-1 {
+		return errors.New("more players invited than there are free seats")
+	}
+	
+	if s.AI != "" {
+		if !s.AI.Valid() {
+			return fmt.Errorf("unknown ai difficulty %q", s.AI)
+		}
+		if _, _, ok := s.lineRules(); !ok {
+			return errors.New("the AI plays two-player classic, connect and gomoku games only")
+		}
+		if len(s.Invited) > 0 {
+			return errors.New("games against the AI have no seat to invite to")
+		}
+	}
+	
+	// Three and more players need the room of a larger board
+	if s.PlayerCount() > 2 {
+		switch s.Variant {
+		case VariantConnect, VariantGomoku, VariantGomokuFreestyle:
+		default:
+			return errors.New("games of three or more players must use the connect or gomoku variant")
+		}
+	}
+	
+	return nil
+}
+
+// Rules returns the ruleset of the chosen variant.
+// Games created before variants existed play classic rules.
+func (s GameSettings) Rules() Ruleset {
+	var rules Ruleset
+	switch s.Variant {
+	case VariantMisere:
+		rules = misereRules{}
+	case VariantWild:
+		rules = wildRules{}
+	case VariantUltimate:
+		rules = ultimateRules{}
+	case VariantConnect:
+		size, length := s.connectDimensions()
+		rules = lineRules{size: size, length: length}
+	case VariantGomoku:
+		rules = lineRules{size: GomokuBoardSize, length: GomokuWinLength, exact: true}
+	case VariantGomokuFreestyle:
+		rules = lineRules{size: GomokuBoardSize, length: GomokuWinLength}
+	default:
+		rules = classicRules{}
+	}
+	
+	if s.Gravity {
+		return gravityRules{rules}
+	}
+	return rules
+}
+
+// PlayerCount returns the number of seats of the game
+func (s GameSettings) PlayerCount() int {
+	if s.Players == 0 {
+		return 2
+	}
+	return s.Players
+}
+
+// connectDimensions returns board size and win length of the connect variant
+func (s GameSettings) connectDimensions() (size, length int) {
+	size, length = s.BoardSize, s.WinLength
+	if size == 0 {
+		size = DefaultConnectBoardSize
+	}
+	if length == 0 {
+		length = DefaultConnectWinLength
+	}
+	return size, length
+}
+
+// lineLength returns K, the length of a winning line
+func (s GameSettings) lineLength() int {
+	switch s.Variant {
+	case VariantConnect:
+		_, length := s.connectDimensions()
+		return length
+	case VariantGomoku, VariantGomokuFreestyle:
+		return GomokuWinLength
+	}
+	return 3
+}
+
+// IsInvited reports whether the player may take a seat
+func (s GameSettings) IsInvited(playerID string) bool {
+	if len(s.Invited) == 0 {
+		return true
+	}
+	for _, id := range s.Invited {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
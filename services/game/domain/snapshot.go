@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// GameSnapshot - full state of a game at a stream version
+type GameSnapshot struct {
+	ID                string         `json:"id"`
+	Version           int            `json:"version"`
+	Players           []*Player      `json:"players"`
+	Eliminated        []string       `json:"eliminated,omitempty"`
+	LegacyPlayer1     *Player        `json:"player1,omitempty"` // seats of snapshots taken before the player list
+	LegacyPlayer2     *Player        `json:"player2,omitempty"`
+	Board             [][]string     `json:"board"`
+	Status            GameStatus     `json:"status"`
+	CurrentTurnID     string         `json:"current_turn_id,omitempty"`
+	WinnerID          string         `json:"winner_id,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+	StartedAt         *time.Time     `json:"started_at,omitempty"`
+	FinishedAt        *time.Time     `json:"finished_at,omitempty"`
+	TurnStartedAt     *time.Time     `json:"turn_started_at,omitempty"`
+	FinishReason      FinishReason   `json:"finish_reason,omitempty"`
+	DrawOfferedByID   string         `json:"draw_offered_by_id,omitempty"`
+	LastMove          *Move          `json:"last_move,omitempty"`
+	Moves             []Move         `json:"moves,omitempty"`
+	TakebackByID      string         `json:"takeback_requested_by_id,omitempty"`
+	TakebacksUsed     map[string]int `json:"takebacks_used,omitempty"`
+	Settings          GameSettings   `json:"settings"`
+	SeriesID          string         `json:"series_id"`
+	PreviousGameID    string         `json:"previous_game_id,omitempty"`
+	NextGameID        string         `json:"next_game_id,omitempty"`
+	RematchAcceptedBy []string       `json:"rematch_accepted_by,omitempty"`
+}
+
+// Snapshot captures the current game state.
+// The snapshot shares no mutable data with the game.
+func (g *Game) Snapshot() GameSnapshot {
+	return GameSnapshot{
+		ID:                g.ID,
+		Version:           g.Version,
+		Players:           copyPlayers(g.Players),
+		Eliminated:        append([]string(nil), g.Eliminated...),
+		Board:             g.Board.GetState(),
+		Status:            g.Status,
+		CurrentTurnID:     playerID(g.CurrentTurn),
+		WinnerID:          playerID(g.Winner),
+		CreatedAt:         g.CreatedAt,
+		StartedAt:         g.StartedAt,
+		FinishedAt:        g.FinishedAt,
+		TurnStartedAt:     g.TurnStartedAt,
+		FinishReason:      g.FinishReason,
+		DrawOfferedByID:   playerID(g.DrawOfferedBy),
+		LastMove:          copyMove(g.LastMove),
+		Moves:             append([]Move(nil), g.Moves...),
+		TakebackByID:      playerID(g.TakebackRequestedBy),
+		TakebacksUsed:     copyCounts(g.TakebacksUsed),
+		Settings:          g.Settings,
+		SeriesID:          g.SeriesID,
+		PreviousGameID:    g.PreviousGameID,
+		NextGameID:        g.NextGameID,
+		RematchAcceptedBy: append([]string(nil), g.RematchAcceptedBy...),
+	}
+}
+
+// RestoreGame recreates a game from a snapshot
+func RestoreGame(snapshot GameSnapshot) (*Game, error) {
+	board := snapshot.Settings.Rules().NewBoard()
+	for row, cells := range snapshot.Board {
+		for col, cell := range cells {
+			if cell == "" {
+				continue
+			}
+			if err := board.MakeMove(row*board.Size()+col, cell); err != nil {
+				return nil, fmt.Errorf("invalid snapshot board: %w", err)
+			}
+		}
+	}
+	
+	game := &Game{
+		ID:                snapshot.ID,
+		Version:           snapshot.Version,
+		Players:           copyPlayers(snapshot.Players),
+		Eliminated:        append([]string(nil), snapshot.Eliminated...),
+		Board:             board,
+		Status:            snapshot.Status,
+		CreatedAt:         snapshot.CreatedAt,
+		StartedAt:         snapshot.StartedAt,
+		FinishedAt:        snapshot.FinishedAt,
+		TurnStartedAt:     snapshot.TurnStartedAt,
+		FinishReason:      snapshot.FinishReason,
+		LastMove:          copyMove(snapshot.LastMove),
+		Moves:             append([]Move(nil), snapshot.Moves...),
+		TakebacksUsed:     copyCounts(snapshot.TakebacksUsed),
+		Settings:          snapshot.Settings,
+		SeriesID:          snapshot.SeriesID,
+		PreviousGameID:    snapshot.PreviousGameID,
+		NextGameID:        snapshot.NextGameID,
+		RematchAcceptedBy: append([]string(nil), snapshot.RematchAcceptedBy...),
+	}
+	for _, player := range []*Player{snapshot.LegacyPlayer1, snapshot.LegacyPlayer2} {
+		if len(snapshot.Players) == 0 && player != nil {
+			game.Players = append(game.Players, copyPlayer(player))
+		}
+	}
+	game.CurrentTurn = game.PlayerByID(snapshot.CurrentTurnID)
+	game.Winner = game.PlayerByID(snapshot.WinnerID)
+	game.DrawOfferedBy = game.PlayerByID(snapshot.DrawOfferedByID)
+	game.TakebackRequestedBy = game.PlayerByID(snapshot.TakebackByID)
+	
+	return game, nil
+}
+
+// copyPlayers returns copies of players
+func copyPlayers(players []*Player) []*Player {
+	copied := make([]*Player, 0, len(players))
+	for _, player := range players {
+		copied = append(copied, copyPlayer(player))
+	}
+	return copied
+}
+
+// copyPlayer returns a copy of a possibly absent player
+func copyPlayer(player *Player) *Player {
+	if player == nil {
+		return nil
+	}
+	copied := *player
+	return &copied
+}
+
+// copyMove returns a copy of a possibly absent move
+func copyMove(move *Move) *Move {
+	if move == nil {
+		return nil
+	}
+	copied := *move
+	return &copied
+}
+
+// copyCounts returns a copy of a possibly nil map of counts
+func copyCounts(counts map[string]int) map[string]int {
+	if counts == nil {
+		return nil
+	}
+	copied := make(map[string]int, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
+
+// playerID returns ID of a possibly absent player
+func playerID(player *Player) string {
+	if player == nil {
+		return ""
+	}
+	return player.ID
+}
@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"sort"
+)
+
+// Points scored per pairing result
+const (
+	WinPoints  = 1.0
+	DrawPoints = 0.5
+	ByePoints  = 1.0
+)
+
+// Standing - placement of an entrant
+type Standing struct {
+	Rank int `json:"rank"`
+	Entrant
+
+	Played int     `json:"played"` // games against an opponent, byes excluded
+	Wins   int     `json:"wins"`
+	Draws  int     `json:"draws"`
+	Losses int     `json:"losses"`
+	Byes   int     `json:"byes"`
+	Points float64 `json:"points"`
+
+	// Buchholz is the sum of the points of all opponents met
+	Buchholz float64 `json:"buchholz"`
+	// SonnebornBerger is the sum of the points of beaten opponents plus half of those of drawn ones
+	SonnebornBerger float64 `json:"sonneborn_berger"`
+
+	// EliminatedInRound is the knockout round the entrant lost, 0 while still in
+	EliminatedInRound int `json:"eliminated_in_round,omitempty"`
+}
+
+// Standings ranks the entrants by points, then Buchholz, then Sonneborn-Berger, then seed.
+// Knockout entrants are ranked by how far they came first.
+func (t *Tournament) Standings() []Standing {
+	standings := make([]Standing, len(t.Entrants))
+	index := make(map[string]int, len(t.Entrants))
+	for i, entrant := range t.Entrants {
+		standings[i] = Standing{Entrant: entrant}
+		index[entrant.ID] = i
+	}
+
+	// results of every entrant against their opponents, in the order they were played
+	type meeting struct {
+		opponent string
+		score    float64
+	}
+	meetings := make(map[string][]meeting, len(t.Entrants))
+
+	for _, round := range t.Rounds {
+		for _, pairing := range round.Pairings {
+			if !pairing.IsDecided() {
+				continue
+			}
+
+			first := &standings[index[pairing.FirstID]]
+			if pairing.IsBye() {
+				first.Byes++
+				first.Points += ByePoints
+				continue
+			}
+			second := &standings[index[pairing.SecondID]]
+			first.Played++
+			second.Played++
+
+			var firstScore float64
+			switch pairing.Result {
+			case PairingFirstWon:
+				first.Wins++
+				second.Losses++
+				firstScore = WinPoints
+				second.eliminate(t.Format, round.Number)
+			case PairingSecondWon:
+				second.Wins++
+				first.Losses++
+				firstScore = 0
+				first.eliminate(t.Format, round.Number)
+			case PairingDraw:
+				first.Draws++
+				second.Draws++
+				firstScore = DrawPoints
+			}
+			first.Points += firstScore
+			second.Points += WinPoints - firstScore
+
+			meetings[first.ID] = append(meetings[first.ID], meeting{second.ID, firstScore})
+			meetings[second.ID] = append(meetings[second.ID], meeting{first.ID, WinPoints - firstScore})
+		}
+	}
+
+	for i := range standings {
+		for _, m := range meetings[standings[i].ID] {
+			opponentPoints := standings[index[m.opponent]].Points
+			standings[i].Buchholz += opponentPoints
+			standings[i].SonnebornBerger += m.score * opponentPoints
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if t.Format == FormatKnockout && a.EliminatedInRound != b.EliminatedInRound {
+			return a.EliminatedInRound == 0 || (b.EliminatedInRound != 0 && a.EliminatedInRound > b.EliminatedInRound)
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Buchholz != b.Buchholz {
+			return a.Buchholz > b.Buchholz
+		}
+		if a.SonnebornBerger != b.SonnebornBerger {
+			return a.SonnebornBerger > b.SonnebornBerger
+		}
+		return a.Seed < b.Seed
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// eliminate records a knockout loss in round
+func (s *Standing) eliminate(format TournamentFormat, round int) {
+	if format == FormatKnockout {
+		s.EliminatedInRound = round
+	}
+}
+
+// BracketMatch - a pairing with its entrants, which are nil while not known yet
+type BracketMatch struct {
+	Table         int           `json:"table"`
+	First         *Entrant      `json:"first"`
+	Second        *Entrant      `json:"second"`
+	GameID        string        `json:"game_id,omitempty"`
+	Replays       []string      `json:"replays,omitempty"`
+	Result        PairingResult `json:"result"`
+	WinnerID      string        `json:"winner_id,omitempty"`
+	DecidedBySeed bool          `json:"decided_by_seed,omitempty"`
+}
+
+// BracketRound - the matches of a round
+type BracketRound struct {
+	Number   int            `json:"number"`
+	Finished bool           `json:"finished"`
+	Matches  []BracketMatch `json:"matches"`
+}
+
+// Bracket - every round of a tournament, including those not paired yet where they are
+// known in advance: the whole schedule of a round robin and the empty slots of a knockout.
+// Swiss rounds depend on earlier results and are listed once paired.
+type Bracket struct {
+	TournamentID string           `json:"tournament_id"`
+	Format       TournamentFormat `json:"format"`
+	Status       TournamentStatus `json:"status"`
+	TotalRounds  int              `json:"total_rounds"`
+	Rounds       []BracketRound   `json:"rounds"`
+}
+
+// Bracket returns the bracket of a started tournament, without rounds before the start
+func (t *Tournament) Bracket() Bracket {
+	bracket := Bracket{
+		TournamentID: t.ID,
+		Format:       t.Format,
+		Status:       t.Status,
+		TotalRounds:  t.TotalRounds,
+		Rounds:       []BracketRound{},
+	}
+
+	for _, round := range t.Rounds {
+		matches := make([]BracketMatch, 0, len(round.Pairings))
+		for _, pairing := range round.Pairings {
+			matches = append(matches, BracketMatch{
+				Table:         pairing.Table,
+				First:         t.Entrant(pairing.FirstID),
+				Second:        t.Entrant(pairing.SecondID),
+				GameID:        pairing.GameID,
+				Replays:       pairing.Replays,
+				Result:        pairing.Result,
+				WinnerID:      pairing.WinnerID,
+				DecidedBySeed: pairing.DecidedBySeed,
+			})
+		}
+		bracket.Rounds = append(bracket.Rounds, BracketRound{
+			Number:   round.Number,
+			Finished: round.FinishedAt != nil,
+			Matches:  matches,
+		})
+	}
+
+	if t.Status != TournamentStatusRunning {
+		return bracket
+	}
+
+	for number := len(t.Rounds) + 1; number <= t.TotalRounds; number++ {
+		var matches []BracketMatch
+		switch t.Format {
+		case FormatRoundRobin:
+			for i, pair := range roundRobinPairs(t.Entrants, number) {
+				matches = append(matches, BracketMatch{
+					Table:  i + 1,
+					First:  t.Entrant(pair[0]),
+					Second: t.Entrant(pair[1]),
+					Result: PairingPending,
+				})
+			}
+		case FormatKnockout:
+			matches = knockoutSlots(t, bracket.Rounds[len(bracket.Rounds)-1].Matches)
+		default:
+			return bracket
+		}
+		bracket.Rounds = append(bracket.Rounds, BracketRound{Number: number, Matches: matches})
+	}
+	return bracket
+}
+
+// knockoutSlots returns the matches fed by the winners of the previous round, with the
+// entrants of undecided matches left empty
+func knockoutSlots(t *Tournament, previous []BracketMatch) []BracketMatch {
+	matches := make([]BracketMatch, 0, len(previous)/2)
+	for i := 0; i+1 < len(previous); i += 2 {
+		match := BracketMatch{
+			Table:  i/2 + 1,
+			First:  t.Entrant(previous[i].WinnerID),
+			Second: t.Entrant(previous[i+1].WinnerID),
+			Result: PairingPending,
+		}
+		if match.First != nil && match.Second != nil && match.Second.Seed < match.First.Seed {
+			match.First, match.Second = match.Second, match.First
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
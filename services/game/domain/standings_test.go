@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStandingsTiebreaks(t *testing.T) {
+	// 1 beats 2 and draws 3; 3 draws 4; 2 beats 4
+	tournament := &Tournament{
+		Format:   FormatRoundRobin,
+		Status:   TournamentStatusRunning,
+		Entrants: newEntrants(4),
+		Rounds: []Round{
+			{Number: 1, Pairings: []Pairing{
+				{Table: 1, FirstID: "1", SecondID: "2", Result: PairingFirstWon, WinnerID: "1"},
+				{Table: 2, FirstID: "3", SecondID: "4", Result: PairingDraw},
+			}},
+			{Number: 2, Pairings: []Pairing{
+				{Table: 1, FirstID: "1", SecondID: "3", Result: PairingDraw},
+				{Table: 2, FirstID: "4", SecondID: "2", Result: PairingSecondWon, WinnerID: "2"},
+			}},
+		},
+	}
+
+	want := []struct {
+		id                                string
+		points, buchholz, sonnebornBerger float64
+	}{
+		{"1", 1.5, 2, 1.5},
+		// 3 and 2 tie on points and Buchholz; 3 drew the leader, 2 only beat the last
+		{"3", 1, 2, 1},
+		{"2", 1, 2, 0.5},
+		{"4", 0.5, 2, 0.5},
+	}
+
+	standings := tournament.Standings()
+	for i, w := range want {
+		s := standings[i]
+		if s.ID != w.id || s.Rank != i+1 {
+			t.Fatalf("rank %d is %s", i+1, s.ID)
+		}
+		if s.Points != w.points || s.Buchholz != w.buchholz || s.SonnebornBerger != w.sonnebornBerger {
+			t.Errorf("%s: points %v, Buchholz %v, Sonneborn-Berger %v; want %v, %v, %v",
+				s.ID, s.Points, s.Buchholz, s.SonnebornBerger, w.points, w.buchholz, w.sonnebornBerger)
+		}
+	}
+}
+
+func TestStandingsBuchholzBreaksTies(t *testing.T) {
+	// Winners of the first round tie on points; 2 recovered, so beating 2 weighs most
+	tournament := &Tournament{
+		Format:   FormatSwiss,
+		Status:   TournamentStatusRunning,
+		Entrants: newEntrants(6),
+		Rounds: []Round{
+			{Number: 1, Pairings: []Pairing{
+				{Table: 1, FirstID: "1", SecondID: "2", Result: PairingFirstWon, WinnerID: "1"},
+				{Table: 2, FirstID: "3", SecondID: "4", Result: PairingFirstWon, WinnerID: "3"},
+				{Table: 3, FirstID: "5", SecondID: "6", Result: PairingFirstWon, WinnerID: "5"},
+			}},
+			{Number: 2, Pairings: []Pairing{
+				{Table: 1, FirstID: "1", SecondID: "3", Result: PairingPending},
+				{Table: 2, FirstID: "2", SecondID: "6", Result: PairingFirstWon, WinnerID: "2"},
+				{Table: 3, FirstID: "5", SecondID: "4", Result: PairingPending},
+			}},
+		},
+	}
+
+	var order []string
+	buchholz := make(map[string]float64)
+	for _, s := range tournament.Standings() {
+		order = append(order, s.ID)
+		buchholz[s.ID] = s.Buchholz
+	}
+
+	// 1 and 2 tie on Buchholz too, and 1 won their game; 3 and 5 tie on everything but seed
+	want := []string{"1", "2", "3", "5", "6", "4"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("order %v, want %v", order, want)
+	}
+	for id, w := range map[string]float64{"1": 1, "2": 1, "3": 0, "5": 0, "6": 2, "4": 1} {
+		if buchholz[id] != w {
+			t.Errorf("%s: Buchholz %v, want %v", id, buchholz[id], w)
+		}
+	}
+}
+
+func TestStandingsByes(t *testing.T) {
+	tournament := &Tournament{
+		Format:   FormatRoundRobin,
+		Status:   TournamentStatusRunning,
+		Entrants: newEntrants(3),
+		Rounds: []Round{{Number: 1, Pairings: []Pairing{
+			{Table: 1, FirstID: "1", SecondID: "2", Result: PairingFirstWon, WinnerID: "1"},
+			{Table: 2, FirstID: "3", Result: PairingBye, WinnerID: "3"},
+		}}},
+	}
+
+	standings := tournament.Standings()
+	// A bye scores like a win but is no game, so it adds to nobody's Buchholz
+	if s := standings[1]; s.ID != "3" || s.Byes != 1 || s.Played != 0 || s.Points != ByePoints || s.Buchholz != 0 {
+		t.Fatalf("bye standing %+v", s)
+	}
+	if s := standings[0]; s.ID != "1" || s.Buchholz != 0 || s.SonnebornBerger != 0 {
+		t.Fatalf("winner standing %+v", s)
+	}
+}
+
+func TestKnockoutStandingsRankByRoundReached(t *testing.T) {
+	tournament := &Tournament{
+		Format:   FormatKnockout,
+		Status:   TournamentStatusFinished,
+		Entrants: newEntrants(4),
+		Rounds: []Round{
+			{Number: 1, Pairings: []Pairing{
+				{Table: 1, FirstID: "1", SecondID: "4", Result: PairingSecondWon, WinnerID: "4"},
+				{Table: 2, FirstID: "2", SecondID: "3", Result: PairingFirstWon, WinnerID: "2"},
+			}},
+			{Number: 2, Pairings: []Pairing{
+				{Table: 1, FirstID: "2", SecondID: "4", Result: PairingSecondWon, WinnerID: "4"},
+			}},
+		},
+	}
+
+	standings := tournament.Standings()
+	var order []string
+	for _, s := range standings {
+		order = append(order, s.ID)
+	}
+	// The first round losers tie and fall back to seeds
+	if want := []string{"4", "2", "1", "3"}; !slices.Equal(order, want) {
+		t.Fatalf("order %v, want %v", order, want)
+	}
+	if standings[1].EliminatedInRound != 2 || standings[0].EliminatedInRound != 0 {
+		t.Errorf("eliminated in rounds %d and %d", standings[0].EliminatedInRound, standings[1].EliminatedInRound)
+	}
+}
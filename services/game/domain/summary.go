@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+// GameFinishedEvent is the name of the integration message carrying a GameSummary.
+// It is published next to the domain events of a finishing game and is not part of its stream.
+const GameFinishedEvent = "game.finished"
+
+// Results of a player in a finished game
+const (
+	ResultWin  = "win"
+	ResultLoss = "loss"
+	ResultDraw = "draw"
+)
+
+// GameSummary - outcome of a finished game as needed by statistics
+type GameSummary struct {
+	GameID        string          `json:"game_id"`
+	Variant       Variant         `json:"variant"`
+	BoardSize     int             `json:"board_size"`
+	WinLength     int             `json:"win_length"`
+	AI            AIDifficulty    `json:"ai,omitempty"` // difficulty of the computer opponent, empty in games between people
+	Players       []PlayerSummary `json:"players"`
+	WinnerID      string          `json:"winner_id,omitempty"`
+	Reason        FinishReason    `json:"reason"`
+	FirstPlayerID string          `json:"first_player_id"`
+	Moves         int             `json:"moves"`
+	StartedAt     *time.Time      `json:"started_at,omitempty"`
+	FinishedAt    *time.Time      `json:"finished_at,omitempty"`
+}
+
+// PlayerSummary - a player of a finished game and how it ended for them
+type PlayerSummary struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Symbol   string `json:"symbol"`
+	Result   string `json:"result"`
+}
+
+// Summary returns the outcome of the game.
+// Players eliminated from a drawn game of three or more count as losers.
+func (g *Game) Summary() GameSummary {
+	summary := GameSummary{
+		GameID:     g.ID,
+		Variant:    g.variant(),
+		BoardSize:  g.Board.Size(),
+		WinLength:  g.Settings.lineLength(),
+		AI:         g.Settings.AI,
+		Players:    make([]PlayerSummary, 0, len(g.Players)),
+		Reason:     g.FinishReason,
+		Moves:      len(g.Moves),
+		StartedAt:  g.StartedAt,
+		FinishedAt: g.FinishedAt,
+	}
+	if g.Winner != nil {
+		summary.WinnerID = g.Winner.ID
+	}
+	if len(g.Moves) > 0 {
+		summary.FirstPlayerID = g.Moves[0].PlayerID
+	} else if len(g.Players) > 0 {
+		summary.FirstPlayerID = g.Players[0].ID
+	}
+
+	for _, player := range g.Players {
+		result := ResultLoss
+		switch {
+		case g.Winner != nil && g.Winner.ID == player.ID:
+			result = ResultWin
+		case g.Winner == nil && !g.isEliminated(player.ID):
+			result = ResultDraw
+		}
+		summary.Players = append(summary.Players, PlayerSummary{
+			ID:       player.ID,
+			Username: player.Username,
+			Symbol:   player.Symbol,
+			Result:   result,
+		})
+	}
+	return summary
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"errors"
+)
+
+// RequestTakeback asks the opponent to take back the player's last move
+func (g *Game) RequestTakeback(player *Player) error {
+	if err := g.checkActiveParticipant(player); err != nil {
+		return err
+	}
+	
+	if len(g.Players) > 2 {
+		return errors.New("takebacks are only available in two-player games")
+	}
+	
+	if g.TakebackRequestedBy != nil {
+		return errors.New("takeback request already pending")
+	}
+	
+	if len(g.takebackPositions(player.ID)) == 0 {
+		return errors.New("no move to take back")
+	}
+	
+	if limit := g.Settings.TakebackLimit; limit != UnlimitedTakebacks && g.TakebacksUsed[player.ID] >= limit {
+		return errors.New("takeback limit reached")
+	}
+	
+	return g.raise(TakebackRequested{
+		EventBase: g.eventBase(),
+		PlayerID:  player.ID,
+	})
+}
+
+// AcceptTakeback reverts the moves made since and including the requester's last one
+func (g *Game) AcceptTakeback(player *Player) error {
+	if err := g.checkTakebackFromOpponent(player); err != nil {
+		return err
+	}
+	
+	requester := g.TakebackRequestedBy
+	return g.raise(TakebackAccepted{
+		EventBase:   g.eventBase(),
+		PlayerID:    player.ID,
+		RequesterID: requester.ID,
+		Positions:   g.takebackPositions(requester.ID),
+	})
+}
+
+// DeclineTakeback declines the opponent's takeback request
+func (g *Game) DeclineTakeback(player *Player) error {
+	if err := g.checkTakebackFromOpponent(player); err != nil {
+		return err
+	}
+	
+	return g.raise(TakebackDeclined{
+		EventBase: g.eventBase(),
+		PlayerID:  player.ID,
+	})
+}
+
+// checkTakebackFromOpponent checks that player has a takeback request to answer
+func (g *Game) checkTakebackFromOpponent(player *Player) error {
+	if err := g.checkActiveParticipant(player); err != nil {
+		return err
+	}
+	
+	if g.TakebackRequestedBy == nil {
+		return errors.New("no takeback request pending")
+	}
+	
+	if g.TakebackRequestedBy.ID == player.ID {
+		return errors.New("cannot answer your own takeback request")
+	}
+	
+	return nil
+}
+
+// takebackPositions returns positions of the moves to revert, latest first:
+// the player's last move and every move made after it
+func (g *Game) takebackPositions(playerID string) []int {
+	var positions []int
+	for i := len(g.Moves) - 1; i >= 0; i-- {
+		positions = append(positions, g.Moves[i].Position)
+		if g.Moves[i].PlayerID == playerID {
+			return positions
+		}
+	}
+	return nil
+}
+
+// takeBack removes reverted moves from the board and the history
+func (g *Game) takeBack(e TakebackAccepted) error {
+	for _, position := range e.Positions {
+		last := len(g.Moves) - 1
+		if last < 0 || g.Moves[last].Position != position {
+			return errors.New("takeback does not match move history")
+		}
+		if err := g.Board.Undo(position); err != nil {
+			return err
+		}
+		g.Moves = g.Moves[:last]
+	}
+	
+	g.LastMove = nil
+	if len(g.Moves) > 0 {
+		move := g.Moves[len(g.Moves)-1]
+		g.LastMove = &move
+	}
+	
+	if g.TakebacksUsed == nil {
+		g.TakebacksUsed = make(map[string]int)
+	}
+	g.TakebacksUsed[e.RequesterID]++
+	g.TakebackRequestedBy = nil
+	g.DrawOfferedBy = nil
+	g.CurrentTurn = g.PlayerByID(e.RequesterID)
+	return nil
+}
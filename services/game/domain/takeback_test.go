@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTakebackRestoresClockAndTurn(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) EventBase {
+		return EventBase{GameID: "game", OccurredAt: start.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	game, err := RebuildGame([]Event{
+		GameCreated{EventBase: at(0), PlayerID: "1", Symbol: "X", Settings: DefaultGameSettings()},
+		PlayerJoined{EventBase: at(0), PlayerID: "2", Symbol: "O"},
+		MoveMade{EventBase: at(5), PlayerID: "1", Position: 4, Symbol: "X"},
+		MoveMade{EventBase: at(15), PlayerID: "2", Position: 0, Symbol: "O"},
+		MoveMade{EventBase: at(45), PlayerID: "1", Position: 8, Symbol: "X"},
+		TakebackRequested{EventBase: at(50), PlayerID: "1"},
+		TakebackAccepted{EventBase: at(60), PlayerID: "2", RequesterID: "1", Positions: []int{8}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if game.CurrentTurn == nil || game.CurrentTurn.ID != "1" {
+		t.Fatalf("turn of %v after the takeback, want the requester", game.CurrentTurn)
+	}
+	if game.TurnStartedAt == nil || !game.TurnStartedAt.Equal(start.Add(60*time.Second)) {
+		t.Fatalf("turn started at %v, want the takeback", game.TurnStartedAt)
+	}
+	// The 30 seconds spent on the reverted move are given back
+	clocks := game.Clocks()
+	if clocks["1"] != 5000 || clocks["2"] != 10000 {
+		t.Fatalf("clocks %v after the takeback", clocks)
+	}
+}
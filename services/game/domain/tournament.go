@@ -0,0 +1,443 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TournamentFormat - how the players of a tournament are paired
+type TournamentFormat string
+
+const (
+	FormatRoundRobin TournamentFormat = "round_robin" // everyone plays everyone once
+	FormatSwiss      TournamentFormat = "swiss"       // players with equal scores meet, nobody twice
+	FormatKnockout   TournamentFormat = "knockout"    // single elimination, losers are out
+)
+
+// TournamentStatus - tournament status
+type TournamentStatus string
+
+const (
+	TournamentStatusRegistering TournamentStatus = "registering"
+	TournamentStatusRunning     TournamentStatus = "running"
+	TournamentStatusFinished    TournamentStatus = "finished"
+)
+
+// PairingResult - outcome of a pairing
+type PairingResult string
+
+const (
+	PairingPending   PairingResult = "pending"
+	PairingFirstWon  PairingResult = "first_won"
+	PairingSecondWon PairingResult = "second_won"
+	PairingDraw      PairingResult = "draw"
+	PairingBye       PairingResult = "bye" // the player had no opponent and scores a win
+)
+
+const (
+	// MaxTournamentPlayers - largest field of a tournament
+	MaxTournamentPlayers = 256
+
+	// MaxKnockoutReplays - drawn knockout games played again with the first move
+	// passed on before the better seed advances
+	MaxKnockoutReplays = 2
+
+	// maxTournamentNameLength - longest tournament name in characters
+	maxTournamentNameLength = 100
+)
+
+// Entrant - a player registered for a tournament
+type Entrant struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username,omitempty"`
+	Seed         int       `json:"seed"` // 1 is the top seed, seeds follow registration order
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// Pairing - two entrants meeting in a round, or one entrant with a bye
+type Pairing struct {
+	Table    int    `json:"table"`
+	FirstID  string `json:"first_id"`            // moves first
+	SecondID string `json:"second_id,omitempty"` // empty for a bye
+	GameID   string `json:"game_id,omitempty"`   // empty until the game is created
+
+	// Replays are drawn knockout games that had to be played again
+	Replays []string `json:"replays,omitempty"`
+
+	Result   PairingResult `json:"result"`
+	WinnerID string        `json:"winner_id,omitempty"`
+
+	// DecidedBySeed is set when the better seed advanced after MaxKnockoutReplays drawn replays
+	DecidedBySeed bool `json:"decided_by_seed,omitempty"`
+}
+
+// IsBye reports whether the pairing has no opponent
+func (p Pairing) IsBye() bool {
+	return p.SecondID == ""
+}
+
+// IsDecided reports whether the pairing has a result
+func (p Pairing) IsDecided() bool {
+	return p.Result != PairingPending
+}
+
+// Round - pairings played at the same time
+type Round struct {
+	Number     int        `json:"number"`
+	Pairings   []Pairing  `json:"pairings"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Tournament - aggregate of a tournament and the rounds played so far
+type Tournament struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Format      TournamentFormat `json:"format"`
+	Status      TournamentStatus `json:"status"`
+	OrganizerID string           `json:"organizer_id"`
+
+	// Settings of every game of the tournament
+	Settings   GameSettings `json:"settings"`
+	MaxPlayers int          `json:"max_players"`
+
+	// TotalRounds is chosen for Swiss tournaments and follows from the field otherwise.
+	// It is fixed when the tournament starts.
+	TotalRounds int `json:"total_rounds"`
+
+	Entrants []Entrant `json:"entrants"`
+	Rounds   []Round   `json:"rounds"`
+	WinnerID string    `json:"winner_id,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Number of times the tournament was saved, for optimistic concurrency
+	Version int `json:"version"`
+}
+
+// newTournament creates a tournament open for registration.
+// Rounds may only be chosen for Swiss tournaments, 0 picks enough rounds for a single leader.
+func newTournament(id, organizerID, name string, format TournamentFormat, settings GameSettings, rounds, maxPlayers int) (*Tournament, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if len([]rune(name)) > maxTournamentNameLength {
+		return nil, fmt.Errorf("name must be at most %d characters", maxTournamentNameLength)
+	}
+
+	switch format {
+	case FormatRoundRobin, FormatSwiss, FormatKnockout:
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	if settings.PlayerCount() != 2 {
+		return nil, errors.New("tournament games must have two players")
+	}
+	if settings.BestOf != 1 {
+		return nil, errors.New("tournament games cannot be series")
+	}
+	if len(settings.Invited) > 0 {
+		return nil, errors.New("tournament games cannot have invitations")
+	}
+
+	if rounds < 0 || (rounds > 0 && format != FormatSwiss) {
+		return nil, errors.New("rounds can only be chosen for swiss tournaments")
+	}
+
+	if maxPlayers == 0 {
+		maxPlayers = MaxTournamentPlayers
+	}
+	if maxPlayers < 2 || maxPlayers > MaxTournamentPlayers {
+		return nil, fmt.Errorf("max_players must be between 2 and %d", MaxTournamentPlayers)
+	}
+
+	return &Tournament{
+		ID:          id,
+		Name:        name,
+		Format:      format,
+		Status:      TournamentStatusRegistering,
+		OrganizerID: organizerID,
+		Settings:    settings,
+		MaxPlayers:  maxPlayers,
+		TotalRounds: rounds,
+		Entrants:    []Entrant{},
+		Rounds:      []Round{},
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Register adds the player to a tournament that has not started yet
+func (t *Tournament) Register(player *Player) error {
+	if t.Status != TournamentStatusRegistering {
+		return errors.New("registration is closed")
+	}
+	if t.Entrant(player.ID) != nil {
+		return errors.New("player is already registered")
+	}
+	if len(t.Entrants) >= t.MaxPlayers {
+		return errors.New("tournament is full")
+	}
+
+	t.Entrants = append(t.Entrants, Entrant{
+		ID:           player.ID,
+		Username:     player.Username,
+		Seed:         len(t.Entrants) + 1,
+		RegisteredAt: time.Now(),
+	})
+	return nil
+}
+
+// Withdraw removes the player from a tournament that has not started yet
+func (t *Tournament) Withdraw(playerID string) error {
+	if t.Status != TournamentStatusRegistering {
+		return errors.New("tournament has already started")
+	}
+
+	for i, entrant := range t.Entrants {
+		if entrant.ID != playerID {
+			continue
+		}
+		t.Entrants = append(t.Entrants[:i], t.Entrants[i+1:]...)
+		for seat := range t.Entrants {
+			t.Entrants[seat].Seed = seat + 1
+		}
+		return nil
+	}
+	return errors.New("player is not registered")
+}
+
+// Start closes registration and pairs the first round
+func (t *Tournament) Start() error {
+	if t.Status != TournamentStatusRegistering {
+		return errors.New("tournament has already started")
+	}
+
+	players := len(t.Entrants)
+	if players < 2 {
+		return errors.New("tournament needs at least 2 players")
+	}
+
+	switch t.Format {
+	case FormatRoundRobin:
+		t.TotalRounds = players - 1
+		if players%2 == 1 {
+			t.TotalRounds = players
+		}
+	case FormatKnockout:
+		t.TotalRounds = log2(bracketSize(players))
+	case FormatSwiss:
+		if t.TotalRounds == 0 {
+			t.TotalRounds = log2(bracketSize(players))
+		}
+		if t.TotalRounds > players-1 {
+			return fmt.Errorf("a swiss tournament of %d players has at most %d rounds", players, players-1)
+		}
+	}
+
+	now := time.Now()
+	t.Status = TournamentStatusRunning
+	t.StartedAt = &now
+	t.startRound()
+	return nil
+}
+
+// Entrant returns the registered player with the given ID
+func (t *Tournament) Entrant(playerID string) *Entrant {
+	for i := range t.Entrants {
+		if t.Entrants[i].ID == playerID {
+			return &t.Entrants[i]
+		}
+	}
+	return nil
+}
+
+// CurrentRound returns the round being played, nil before the tournament starts
+func (t *Tournament) CurrentRound() *Round {
+	if len(t.Rounds) == 0 {
+		return nil
+	}
+	return &t.Rounds[len(t.Rounds)-1]
+}
+
+// PairingsWithoutGame returns the undecided pairings of the current round that need a game
+func (t *Tournament) PairingsWithoutGame() []Pairing {
+	round := t.CurrentRound()
+	if round == nil || t.Status != TournamentStatusRunning {
+		return nil
+	}
+
+	var pairings []Pairing
+	for _, pairing := range round.Pairings {
+		if !pairing.IsDecided() && pairing.GameID == "" {
+			pairings = append(pairings, pairing)
+		}
+	}
+	return pairings
+}
+
+// AssignGame records the game created for a pairing of the current round
+func (t *Tournament) AssignGame(table int, gameID string) error {
+	pairing := t.currentPairing(func(p *Pairing) bool { return p.Table == table })
+	if pairing == nil {
+		return fmt.Errorf("round has no table %d", table)
+	}
+	if pairing.IsDecided() || pairing.GameID != "" {
+		return fmt.Errorf("table %d already has a game", table)
+	}
+
+	pairing.GameID = gameID
+	return nil
+}
+
+// HasGame reports whether the game is played for a pairing of the current round
+func (t *Tournament) HasGame(gameID string) bool {
+	return t.currentPairing(func(p *Pairing) bool { return p.GameID == gameID }) != nil
+}
+
+// RecordGame takes the result of a finished game of the current round and
+// starts the next round, or finishes the tournament, once every pairing is decided.
+// The pairing of a game ended by a moderator is left without a game to be paired again.
+// It reports whether the tournament changed, so repeated calls for a game are harmless.
+func (t *Tournament) RecordGame(game *Game) (bool, error) {
+	if t.Status != TournamentStatusRunning || game.Status != GameStatusFinished {
+		return false, nil
+	}
+
+	pairing := t.currentPairing(func(p *Pairing) bool { return p.GameID == game.ID })
+	if pairing == nil || pairing.IsDecided() {
+		return false, nil
+	}
+
+	switch {
+	case game.FinishReason == FinishReasonEnded:
+		// A game ended by a moderator counts for nobody, the pairing is played again
+		pairing.GameID = ""
+		return true, nil
+	case game.Winner == nil && t.Format == FormatKnockout:
+		t.replayOrSeed(pairing)
+	case game.Winner == nil:
+		pairing.Result = PairingDraw
+	case game.Winner.ID == pairing.FirstID:
+		pairing.Result, pairing.WinnerID = PairingFirstWon, pairing.FirstID
+	case game.Winner.ID == pairing.SecondID:
+		pairing.Result, pairing.WinnerID = PairingSecondWon, pairing.SecondID
+	default:
+		return false, fmt.Errorf("winner of game %s is not in its pairing", game.ID)
+	}
+
+	t.advance()
+	return true, nil
+}
+
+// replayOrSeed handles a drawn knockout game: it is played again with the first move
+// passed on, and after MaxKnockoutReplays replays the better seed advances
+func (t *Tournament) replayOrSeed(pairing *Pairing) {
+	if len(pairing.Replays) < MaxKnockoutReplays {
+		pairing.Replays = append(pairing.Replays, pairing.GameID)
+		pairing.GameID = ""
+		pairing.FirstID, pairing.SecondID = pairing.SecondID, pairing.FirstID
+		return
+	}
+
+	pairing.DecidedBySeed = true
+	if t.Entrant(pairing.FirstID).Seed < t.Entrant(pairing.SecondID).Seed {
+		pairing.Result, pairing.WinnerID = PairingFirstWon, pairing.FirstID
+	} else {
+		pairing.Result, pairing.WinnerID = PairingSecondWon, pairing.SecondID
+	}
+}
+
+// advance finishes the current round once all its pairings are decided
+func (t *Tournament) advance() {
+	round := t.CurrentRound()
+	for _, pairing := range round.Pairings {
+		if !pairing.IsDecided() {
+			return
+		}
+	}
+
+	now := time.Now()
+	round.FinishedAt = &now
+	if len(t.Rounds) < t.TotalRounds {
+		t.startRound()
+		return
+	}
+
+	t.Status = TournamentStatusFinished
+	t.FinishedAt = &now
+	t.WinnerID = t.Standings()[0].ID
+}
+
+// startRound pairs the next round. Byes are decided at once.
+func (t *Tournament) startRound() {
+	number := len(t.Rounds) + 1
+
+	var pairs [][2]string
+	switch t.Format {
+	case FormatRoundRobin:
+		pairs = roundRobinPairs(t.Entrants, number)
+	case FormatSwiss:
+		pairs = t.swissPairs()
+	case FormatKnockout:
+		pairs = t.knockoutPairs(number)
+	}
+
+	round := Round{
+		Number:    number,
+		Pairings:  make([]Pairing, 0, len(pairs)),
+		StartedAt: time.Now(),
+	}
+	for i, pair := range pairs {
+		pairing := Pairing{
+			Table:    i + 1,
+			FirstID:  pair[0],
+			SecondID: pair[1],
+			Result:   PairingPending,
+		}
+		if pairing.IsBye() {
+			pairing.Result, pairing.WinnerID = PairingBye, pairing.FirstID
+		}
+		round.Pairings = append(round.Pairings, pairing)
+	}
+	t.Rounds = append(t.Rounds, round)
+
+	// Nothing is left to play in a round of byes only
+	t.advance()
+}
+
+// currentPairing returns the pairing of the current round matching match
+func (t *Tournament) currentPairing(match func(*Pairing) bool) *Pairing {
+	round := t.CurrentRound()
+	if round == nil {
+		return nil
+	}
+	for i := range round.Pairings {
+		if match(&round.Pairings[i]) {
+			return &round.Pairings[i]
+		}
+	}
+	return nil
+}
+
+// GameIDs returns the IDs of all games played or being played in the tournament
+func (t *Tournament) GameIDs() []string {
+	var ids []string
+	for _, round := range t.Rounds {
+		for _, pairing := range round.Pairings {
+			ids = append(ids, pairing.Replays...)
+			if pairing.GameID != "" {
+				ids = append(ids, pairing.GameID)
+			}
+		}
+	}
+	return ids
+}
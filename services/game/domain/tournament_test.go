@@ -0,0 +1,37 @@
+package domain
+
+import "testing"
+
+func TestRecordGameEndedByModeratorIsPlayedAgain(t *testing.T) {
+	for _, format := range []TournamentFormat{FormatRoundRobin, FormatKnockout} {
+		t.Run(string(format), func(t *testing.T) {
+			tournament := &Tournament{
+				Format:   format,
+				Status:   TournamentStatusRunning,
+				Entrants: newEntrants(2),
+				Rounds: []Round{
+					{Number: 1, Pairings: []Pairing{{Table: 1, FirstID: "1", SecondID: "2", GameID: "g1", Result: PairingPending}}},
+				},
+			}
+
+			changed, err := tournament.RecordGame(&Game{ID: "g1", Status: GameStatusFinished, FinishReason: FinishReasonEnded})
+			if err != nil || !changed {
+				t.Fatalf("changed %v with %v", changed, err)
+			}
+
+			pairing := tournament.Rounds[0].Pairings[0]
+			if pairing.IsDecided() || pairing.GameID != "" || len(pairing.Replays) != 0 {
+				t.Fatalf("pairing %+v, want it undecided without a game or replay", pairing)
+			}
+			if pairing.FirstID != "1" {
+				t.Errorf("first move passed to %s", pairing.FirstID)
+			}
+			if pairings := tournament.PairingsWithoutGame(); len(pairings) != 1 {
+				t.Errorf("%d pairings to play, want 1", len(pairings))
+			}
+			if tournament.Status != TournamentStatusRunning {
+				t.Errorf("tournament %s, want running", tournament.Status)
+			}
+		})
+	}
+}
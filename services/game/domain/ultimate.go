@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"fmt"
+)
+
+// ultimateRules - a 3×3 grid of classic boards. A move sends the opponent to the
+// small board matching the cell it was played in, unless that board is decided.
+// Winning three small boards in a row wins the game.
+//
+// Positions are numbered row by row on the whole 9×9 board.
+type ultimateRules struct{}
+
+// NewBoard creates a 9×9 board
+func (ultimateRules) NewBoard() *Board {
+	return NewBoardOfSize(9)
+}
+
+// ValidateMove checks that the player places their own symbol in an allowed small board
+func (ultimateRules) ValidateMove(board *Board, last *Move, player *Player, move Move) error {
+	if move.Symbol != player.Symbol {
+		return fmt.Errorf("you play %s", player.Symbol)
+	}
+	if err := checkPosition(board, move.Position); err != nil {
+		return err
+	}
+
+	target, _ := smallBoardOf(move.Position)
+	if isDecided(smallBoard(board, target)) {
+		return fmt.Errorf("board %d is already decided", target)
+	}
+
+	if next, ok := nextSmallBoard(board, last); ok && next != target {
+		return fmt.Errorf("move must be played in board %d", next)
+	}
+	return nil
+}
+
+// Outcome wins on three small boards in a row and draws when every small board is decided
+func (ultimateRules) Outcome(board *Board, move Move) MoveResult {
+	overall := NewBoard()
+	decided := 0
+	for i := 0; i < 9; i++ {
+		small := smallBoard(board, i)
+		if winner := small.Winner(); winner != "" {
+			overall.MakeMove(i, winner)
+		}
+		if isDecided(small) {
+			decided++
+		}
+	}
+
+	if overall.HasWinner() {
+		return MoveResultWin
+	}
+	if decided == 9 {
+		return MoveResultDraw
+	}
+	return MoveResultNone
+}
+
+// nextSmallBoard returns the small board the last move sends to,
+// or false if the next move may be played in any open board
+func nextSmallBoard(board *Board, last *Move) (int, bool) {
+	if last == nil {
+		return 0, false
+	}
+
+	_, next := smallBoardOf(last.Position)
+	if isDecided(smallBoard(board, next)) {
+		return 0, false
+	}
+	return next, true
+}
+
+// smallBoardOf returns the small board of a position and the cell it takes in it
+func smallBoardOf(position int) (small, cell int) {
+	row, col := position/9, position%9
+	return (row/3)*3 + col/3, (row%3)*3 + col%3
+}
+
+// smallBoard copies a small board into a classic board
+func smallBoard(board *Board, small int) *Board {
+	result := NewBoard()
+	for cell := 0; cell < 9; cell++ {
+		row := (small/3)*3 + cell/3
+		col := (small%3)*3 + cell%3
+		if symbol := board.GetCell(row, col); symbol != "" {
+			result.MakeMove(cell, symbol)
+		}
+	}
+	return result
+}
+
+// isDecided checks if a small board is won or full
+func isDecided(board *Board) bool {
+	return board.HasWinner() || board.IsFull()
+}
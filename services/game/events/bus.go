@@ -0,0 +1,76 @@
+package events
+
+import (
+	"log"
+	"sync"
+
+	"game-service/domain"
+)
+
+// Handler processes a published domain event
+type Handler func(event domain.Event)
+
+// Bus dispatches domain events to in-process subscribers
+type Bus struct {
+	handlers    map[string][]Handler
+	allHandlers []Handler
+	mu          sync.RWMutex
+}
+
+// NewBus creates an event bus without subscribers
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers a handler for one event type
+func Subscribe[E domain.Event](bus *Bus, handler func(E)) {
+	var zero E
+	bus.subscribe(zero.EventName(), func(event domain.Event) {
+		if typed, ok := event.(E); ok {
+			handler(typed)
+		}
+	})
+}
+
+// SubscribeAll registers a handler for every event
+func (b *Bus) SubscribeAll(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.allHandlers = append(b.allHandlers, handler)
+}
+
+// subscribe registers a handler for the named event
+func (b *Bus) subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish delivers events to subscribers in order
+func (b *Bus) Publish(events ...domain.Event) {
+	for _, event := range events {
+		b.mu.RLock()
+		handlers := append([]Handler{}, b.handlers[event.EventName()]...)
+		handlers = append(handlers, b.allHandlers...)
+		b.mu.RUnlock()
+
+		for _, handler := range handlers {
+			dispatch(handler, event)
+		}
+	}
+}
+
+// dispatch runs a handler so that a failing subscriber cannot break the others
+func dispatch(handler Handler, event domain.Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event handler for %s panicked: %v", event.EventName(), r)
+		}
+	}()
+
+	handler(event)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+)
+
+// AnalysisRequest - body of a position analysis request
+type AnalysisRequest struct {
+	Board     [][]string `json:"board"`
+	ToMove    string     `json:"to_move"`
+	WinLength int        `json:"k"`
+	Gravity   bool       `json:"gravity"`
+	MaxNodes  int        `json:"max_nodes"` // search budget, defaults to domain.DefaultAnalysisNodes
+}
+
+// Analyze evaluates a position under perfect play and ranks its moves
+func (h *GameHandler) Analyze(c *fiber.Ctx) error {
+	var req AnalysisRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.MaxNodes < 0 || req.MaxNodes > domain.MaxAnalysisNodes {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("max_nodes must be between 0 and %d", domain.MaxAnalysisNodes))
+	}
+
+	pos, err := domain.NewAnalysisPosition(req.Board, req.ToMove, req.WinLength, req.Gravity)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	analysis, err := h.service.AnalyzePosition(pos, req.MaxNodes)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SuccessResponse(c, analysis, "")
+}
+
+// AnnotateGame compares every move of a stored game with perfect play
+func (h *GameHandler) AnnotateGame(c *fiber.Ctx) error {
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	annotations, err := h.service.AnnotateGame(game, domain.DefaultAnnotationNodes)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SuccessResponse(c, annotations, "")
+}
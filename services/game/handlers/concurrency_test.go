@@ -0,0 +1,232 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+	"game-service/events"
+	"game-service/handlers"
+	"game-service/repository"
+	"game-service/routes"
+)
+
+const (
+	testSecret        = "test-secret"
+	testServiceSecret = "test-service-secret"
+)
+
+type stateResponse struct {
+	Success bool             `json:"success"`
+	Error   string           `json:"error"`
+	Data    domain.GameState `json:"data"`
+}
+
+// slowRepository widens the window between load and save so that requests overlap
+type slowRepository struct {
+	repository.GameRepository
+}
+
+func (r slowRepository) FindByID(id string) (*domain.Game, error) {
+	game, err := r.GameRepository.FindByID(id)
+	time.Sleep(5 * time.Millisecond)
+	return game, err
+}
+
+func newTestApp() *fiber.App {
+	app, _ := newTestAppWithRepository()
+	return app
+}
+
+func newTestAppWithRepository() (*fiber.App, repository.GameRepository) {
+	app := fiber.New()
+	repo := slowRepository{repository.NewMemoryGameRepository()}
+	tournaments := repository.NewMemoryTournamentRepository()
+	handler := handlers.NewGameHandler(domain.NewGameService(), repo, events.NewBus())
+	handler.SetTournamentRepository(tournaments)
+	tournamentHandler := handlers.NewTournamentHandler(domain.NewGameService(), repo, tournaments, events.NewBus())
+	routes.Setup(app, handler, tournamentHandler, testSecret, testServiceSecret)
+	return app, repo
+}
+
+// testUsername returns the username in the tokens of the user
+func testUsername(userID string) string {
+	return "user" + userID
+}
+
+func do(t *testing.T, app *fiber.App, userID, method, path, body string) (int, stateResponse) {
+	t.Helper()
+
+	token, err := utils.GenerateTokenWithRoles(userID, userID+"@example.com", testUsername(userID), nil, nil, testSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded stateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		t.Fatalf("decode %s %s: %v", method, path, err)
+	}
+	return resp.StatusCode, decoded
+}
+
+func TestConcurrentMovesOnOneGame(t *testing.T) {
+	app := newTestApp()
+
+	status, created := do(t, app, "1", http.MethodPost, "/games/", "")
+	if status != fiber.StatusCreated {
+		t.Fatalf("create: status %d: %s", status, created.Error)
+	}
+	gameID := created.Data.ID
+
+	if status, joined := do(t, app, "2", http.MethodPost, "/games/"+gameID+"/join", ""); status != fiber.StatusOK {
+		t.Fatalf("join: status %d: %s", status, joined.Error)
+	}
+
+	const workers = 48
+	var mu sync.Mutex
+	statuses := make(map[int]int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			userID := fmt.Sprint(w%2 + 1)
+			body := fmt.Sprintf(`{"position": %d}`, w%9)
+			status, resp := do(t, app, userID, http.MethodPost, "/games/"+gameID+"/move", body)
+
+			if status == fiber.StatusConflict && resp.Data.ID != gameID {
+				t.Errorf("conflict response without latest state: %+v", resp)
+			}
+
+			mu.Lock()
+			statuses[status]++
+			mu.Unlock()
+		}(w)
+	}
+	wg.Wait()
+
+	for status := range statuses {
+		switch status {
+		case fiber.StatusOK, fiber.StatusBadRequest, fiber.StatusConflict:
+		default:
+			t.Fatalf("unexpected status %d", status)
+		}
+	}
+
+	_, final := do(t, app, "1", http.MethodGet, "/games/"+gameID, "")
+
+	var x, o int
+	for _, row := range final.Data.Board {
+		for _, cell := range row {
+			switch cell {
+			case "X":
+				x++
+			case "O":
+				o++
+			}
+		}
+	}
+
+	if statuses[fiber.StatusConflict] == 0 {
+		t.Fatalf("expected overlapping moves to conflict, got %v", statuses)
+	}
+	if x+o != statuses[fiber.StatusOK] {
+		t.Fatalf("board has %d symbols, but %d moves succeeded", x+o, statuses[fiber.StatusOK])
+	}
+	if x-o != 0 && x-o != 1 {
+		t.Fatalf("turn order broken: %d X and %d O", x, o)
+	}
+	if final.Data.Version != 2+x+o+finishEvents(final.Data) {
+		t.Fatalf("version %d does not match %d moves", final.Data.Version, x+o)
+	}
+	t.Logf("statuses: %v", statuses)
+}
+
+func TestConcurrentRematchCreatesOneGame(t *testing.T) {
+	app, repo := newTestAppWithRepository()
+
+	status, created := do(t, app, "1", http.MethodPost, "/games/", "")
+	if status != fiber.StatusCreated {
+		t.Fatalf("create: status %d: %s", status, created.Error)
+	}
+	gameID := created.Data.ID
+
+	if status, joined := do(t, app, "2", http.MethodPost, "/games/"+gameID+"/join", ""); status != fiber.StatusOK {
+		t.Fatalf("join: status %d: %s", status, joined.Error)
+	}
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		body := fmt.Sprintf(`{"position": %d}`, position)
+		if status, moved := do(t, app, fmt.Sprint(i%2+1), http.MethodPost, "/games/"+gameID+"/move", body); status != fiber.StatusOK {
+			t.Fatalf("move %d: status %d: %s", position, status, moved.Error)
+		}
+	}
+	if status, requested := do(t, app, "1", http.MethodPost, "/games/"+gameID+"/rematch", ""); status != fiber.StatusOK {
+		t.Fatalf("rematch request: status %d: %s", status, requested.Error)
+	}
+
+	const workers = 8
+	var mu sync.Mutex
+	statuses := make(map[int]int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			status, _ := do(t, app, "2", http.MethodPost, "/games/"+gameID+"/rematch", "")
+
+			mu.Lock()
+			statuses[status]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if statuses[fiber.StatusCreated] != 1 {
+		t.Fatalf("expected exactly one rematch to be created, got %v", statuses)
+	}
+
+	games, err := repo.FindAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rematches int
+	for _, game := range games {
+		if game.PreviousGameID == gameID {
+			rematches++
+		}
+	}
+	if rematches != 1 {
+		t.Fatalf("%d rematches stored, want 1", rematches)
+	}
+	t.Logf("statuses: %v", statuses)
+}
+
+func finishEvents(state domain.GameState) int {
+	if state.Status == domain.GameStatusFinished {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,497 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+	"game-service/events"
+	"game-service/repository"
+)
+
+// GameHandler serves game HTTP endpoints
+type GameHandler struct {
+	service     *domain.GameService
+	repo        repository.GameRepository
+	bus         *events.Bus
+	blocks      BlockChecker
+	tournaments repository.TournamentRepository
+}
+
+// NewGameHandler creates a new game handler
+func NewGameHandler(service *domain.GameService, repo repository.GameRepository, bus *events.Bus) *GameHandler {
+	return &GameHandler{
+		service: service,
+		repo:    repo,
+		bus:     bus,
+	}
+}
+
+// SetTournamentRepository sets the tournaments whose games may not be deleted.
+// Without it, any game may be deleted.
+func (h *GameHandler) SetTournamentRepository(tournaments repository.TournamentRepository) {
+	h.tournaments = tournaments
+}
+
+// CreateGameRequest - body of a create game request
+type CreateGameRequest struct {
+	BestOf    int    `json:"best_of"`
+	Variant   string `json:"variant"`
+	BoardSize int    `json:"board_size"`
+	WinLength int    `json:"win_length"`
+	Gravity   bool   `json:"gravity"`
+	Players   int    `json:"players"`
+	Symbol    string `json:"symbol"`
+
+	// TakebackLimit is the number of takebacks per player, -1 for unlimited
+	TakebackLimit *int `json:"takeback_limit"`
+
+	// Invited reserves the free seats for these user IDs
+	Invited []string `json:"invited"`
+
+	// AI seats a computer opponent of this difficulty: easy, medium or hard
+	AI string `json:"ai"`
+}
+
+// JoinGameRequest - optional body of a join request
+type JoinGameRequest struct {
+	Symbol string `json:"symbol"`
+}
+
+// MoveRequest - body of a move request
+type MoveRequest struct {
+	Position int    `json:"position"`
+	Column   *int   `json:"column,omitempty"` // drops a piece instead in games with gravity
+	Symbol   string `json:"symbol,omitempty"` // only for variants that let players choose
+}
+
+// AbandonRequest - body of an abandonment notification
+type AbandonRequest struct {
+	PlayerID string `json:"player_id"`
+	// Reason is abandon, the default, for players who disconnected and timeout for those who never connected
+	Reason string `json:"reason"`
+}
+
+// TakebackRequest - body of a takeback forwarded by the chat service
+type TakebackRequest struct {
+	PlayerID string `json:"player_id"`
+}
+
+// CreateGame creates a game with the current user as the first player
+func (h *GameHandler) CreateGame(c *fiber.Ctx) error {
+	var req CreateGameRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	settings := domain.DefaultGameSettings()
+	if req.BestOf != 0 {
+		settings.BestOf = req.BestOf
+	}
+	if req.Variant != "" {
+		settings.Variant = domain.Variant(req.Variant)
+	}
+	settings.BoardSize = req.BoardSize
+	settings.WinLength = req.WinLength
+	settings.Gravity = req.Gravity
+	settings.Players = req.Players
+	settings.Invited = req.Invited
+	settings.AI = domain.AIDifficulty(req.AI)
+	if req.TakebackLimit != nil {
+		settings.TakebackLimit = *req.TakebackLimit
+	}
+
+	player := currentPlayer(c)
+	player.AssignSymbol(req.Symbol)
+
+	game, err := h.service.CreateGameWithSettings(player, settings)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.persist(game); err != nil {
+		return h.saveError(c, game.ID, err)
+	}
+
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, game.GetGameState(), "Game created")
+}
+
+// GetGame returns the current game state
+func (h *GameHandler) GetGame(c *fiber.Ctx) error {
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	return utils.SuccessResponse(c, game.GetGameState(), "")
+}
+
+// EndGame finishes an active game without a winner for moderators
+func (h *GameHandler) EndGame(c *fiber.Ctx) error {
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	if err := h.service.End(game); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusConflict, err.Error())
+	}
+
+	return h.save(c, game, "Game ended")
+}
+
+// DeleteGame removes a game for moderators, along with its history
+func (h *GameHandler) DeleteGame(c *fiber.Ctx) error {
+	// The pairing of a deleted tournament game could never be decided
+	if h.tournaments != nil {
+		_, err := h.tournaments.FindByGameID(c.Params("id"))
+		if err == nil {
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Tournament games cannot be deleted")
+		}
+		if !errors.Is(err, repository.ErrTournamentNotFound) {
+			return tournamentError(c, err)
+		}
+	}
+
+	if err := h.repo.Delete(c.Params("id")); err != nil {
+		if errors.Is(err, repository.ErrGameNotFound) {
+			return repositoryError(c, err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to delete game")
+	}
+
+	return utils.SuccessResponse(c, nil, "Game deleted")
+}
+
+// GetStatistics returns statistics of a single game
+func (h *GameHandler) GetStatistics(c *fiber.Ctx) error {
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	return utils.SuccessResponse(c, h.service.GetGameStatistics(game), "")
+}
+
+// JoinGame seats the current user in a waiting game
+func (h *GameHandler) JoinGame(c *fiber.Ctx) error {
+	var req JoinGameRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	player := currentPlayer(c)
+	player.AssignSymbol(req.Symbol)
+
+	blocked, err := h.blockedFromGame(game, player.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Failed to check blocked users")
+	}
+	if blocked {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "You cannot join this game")
+	}
+
+	if err := h.service.JoinGame(game, player); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return h.save(c, game, "Joined game")
+}
+
+// MakeMove places the current user's symbol on the board
+func (h *GameHandler) MakeMove(c *fiber.Ctx) error {
+	var req MoveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	return h.playerAction(c, "Move made", func(game *domain.Game, player *domain.Player) error {
+		if req.Column != nil {
+			return h.service.DropPiece(game, player, *req.Column, req.Symbol)
+		}
+		return h.service.MakeMoveWithSymbol(game, player, req.Position, req.Symbol)
+	})
+}
+
+// Resign resigns the game for the current user
+func (h *GameHandler) Resign(c *fiber.Ctx) error {
+	return h.playerAction(c, "Game resigned", func(game *domain.Game, player *domain.Player) error {
+		return h.service.Resign(game, player)
+	})
+}
+
+// OfferDraw offers a draw to the opponent
+func (h *GameHandler) OfferDraw(c *fiber.Ctx) error {
+	return h.playerAction(c, "Draw offered", func(game *domain.Game, player *domain.Player) error {
+		return h.service.OfferDraw(game, player)
+	})
+}
+
+// AcceptDraw accepts the opponent's draw offer
+func (h *GameHandler) AcceptDraw(c *fiber.Ctx) error {
+	return h.playerAction(c, "Draw accepted", func(game *domain.Game, player *domain.Player) error {
+		return h.service.AcceptDraw(game, player)
+	})
+}
+
+// DeclineDraw declines the opponent's draw offer
+func (h *GameHandler) DeclineDraw(c *fiber.Ctx) error {
+	return h.playerAction(c, "Draw declined", func(game *domain.Game, player *domain.Player) error {
+		return h.service.DeclineDraw(game, player)
+	})
+}
+
+// RequestTakeback asks the opponent to take back the current user's last move
+func (h *GameHandler) RequestTakeback(c *fiber.Ctx) error {
+	return h.playerAction(c, "Takeback requested", func(game *domain.Game, player *domain.Player) error {
+		return h.service.RequestTakeback(game, player)
+	})
+}
+
+// AcceptTakeback accepts the opponent's takeback request
+func (h *GameHandler) AcceptTakeback(c *fiber.Ctx) error {
+	return h.playerAction(c, "Takeback accepted", func(game *domain.Game, player *domain.Player) error {
+		return h.service.AcceptTakeback(game, player)
+	})
+}
+
+// DeclineTakeback declines the opponent's takeback request
+func (h *GameHandler) DeclineTakeback(c *fiber.Ctx) error {
+	return h.playerAction(c, "Takeback declined", func(game *domain.Game, player *domain.Player) error {
+		return h.service.DeclineTakeback(game, player)
+	})
+}
+
+// Rematch accepts a rematch and creates the next game once both players agreed
+func (h *GameHandler) Rematch(c *fiber.Ctx) error {
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	player := game.PlayerByID(currentPlayer(c).ID)
+	if player == nil {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "You are not a player in this game")
+	}
+
+	ready, err := h.service.AcceptRematch(game, player)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if !ready {
+		return h.save(c, game, "Rematch requested")
+	}
+
+	seriesGames, err := h.repo.FindBySeriesID(game.SeriesID)
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	rematch, err := h.service.CreateRematch(game, seriesGames)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	// Both games are saved at once, so the previous game never links to a rematch that was not saved.
+	// The version check on the previous game admits a single rematch when both players accept at once.
+	if err := h.persist(game, rematch); err != nil {
+		return h.saveError(c, game.ID, err)
+	}
+
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, rematch.GetGameState(), "Rematch created")
+}
+
+// GetSeries returns the score of the series the game belongs to
+func (h *GameHandler) GetSeries(c *fiber.Ctx) error {
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	seriesGames, err := h.repo.FindBySeriesID(game.SeriesID)
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	return utils.SuccessResponse(c, h.service.GetSeriesScore(seriesGames, game.SeriesID), "")
+}
+
+// Abandon forfeits the game for a player reported as gone by the chat service
+func (h *GameHandler) Abandon(c *fiber.Ctx) error {
+	var req AbandonRequest
+	if err := c.BodyParser(&req); err != nil || req.PlayerID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "player_id is required")
+	}
+
+	forfeit, message := h.service.Abandon, "Game abandoned"
+	switch domain.FinishReason(req.Reason) {
+	case "", domain.FinishReasonAbandon:
+	case domain.FinishReasonTimeout:
+		forfeit, message = h.service.TimeOut, "Game timed out"
+	default:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "reason must be abandon or timeout")
+	}
+
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	player := game.PlayerByID(req.PlayerID)
+	if player == nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Player is not in this game")
+	}
+
+	if err := forfeit(game, player); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return h.save(c, game, message)
+}
+
+// Seats - the players seated in an active game, as watched by the chat service
+type Seats struct {
+	GameID    string   `json:"game_id"`
+	Version   int      `json:"version"`
+	PlayerIDs []string `json:"player_ids"`
+}
+
+// ActiveSeats returns the seated players of every active game,
+// which the chat service watches again after a restart
+func (h *GameHandler) ActiveSeats(c *fiber.Ctx) error {
+	games, err := h.repo.FindActive()
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	seats := make([]Seats, 0, len(games))
+	for _, game := range games {
+		seats = append(seats, Seats{GameID: game.ID, Version: game.Version, PlayerIDs: game.SeatedPlayerIDs()})
+	}
+	return utils.SuccessResponse(c, seats, "")
+}
+
+// Takeback applies a takeback action sent over the chat service WebSocket
+func (h *GameHandler) Takeback(c *fiber.Ctx) error {
+	var req TakebackRequest
+	if err := c.BodyParser(&req); err != nil || req.PlayerID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "player_id is required")
+	}
+
+	type takebackAction struct {
+		run     func(*domain.Game, *domain.Player) error
+		message string
+	}
+	actions := map[string]takebackAction{
+		"request": {h.service.RequestTakeback, "Takeback requested"},
+		"accept":  {h.service.AcceptTakeback, "Takeback accepted"},
+		"decline": {h.service.DeclineTakeback, "Takeback declined"},
+	}
+	action, ok := actions[c.Params("action")]
+	if !ok {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Unknown takeback action")
+	}
+
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	player := game.PlayerByID(req.PlayerID)
+	if player == nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Player is not in this game")
+	}
+
+	if err := action.run(game, player); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return h.save(c, game, action.message)
+}
+
+// playerAction loads the game, runs action for the current participant and saves the result
+func (h *GameHandler) playerAction(c *fiber.Ctx, message string, action func(*domain.Game, *domain.Player) error) error {
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	player := game.PlayerByID(currentPlayer(c).ID)
+	if player == nil {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "You are not a player in this game")
+	}
+
+	if err := action(game, player); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return h.save(c, game, message)
+}
+
+// persist saves the games in one transaction and dispatches their pending events
+func (h *GameHandler) persist(games ...*domain.Game) error {
+	if err := h.repo.SaveAll(games...); err != nil {
+		return err
+	}
+
+	for _, game := range games {
+		h.bus.Publish(game.PullEvents()...)
+	}
+	return nil
+}
+
+// save persists the game and responds with its state
+func (h *GameHandler) save(c *fiber.Ctx, game *domain.Game, message string) error {
+	if err := h.persist(game); err != nil {
+		return h.saveError(c, game.ID, err)
+	}
+
+	return utils.SuccessResponse(c, game.GetGameState(), message)
+}
+
+// currentPlayer builds a player from the authenticated user
+func currentPlayer(c *fiber.Ctx) *domain.Player {
+	userID, _ := c.Locals("user_id").(string)
+	username, _ := c.Locals("username").(string)
+	email, _ := c.Locals("email").(string)
+	return domain.NewPlayer(userID, username, email)
+}
+
+// repositoryError maps repository errors to HTTP responses
+func repositoryError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrGameNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Game not found")
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load game")
+}
+
+// saveError maps save errors to HTTP responses.
+// Conflicts carry the latest game state so that clients can retry without reloading.
+func (h *GameHandler) saveError(c *fiber.Ctx, gameID string, err error) error {
+	if !errors.Is(err, repository.ErrConcurrentModification) {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to save game")
+	}
+
+	response := utils.Response{
+		Success: false,
+		Error:   "Game was changed by another request",
+	}
+	if latest, err := h.repo.FindByID(gameID); err == nil {
+		response.Data = latest.GetGameState()
+	}
+	return c.Status(fiber.StatusConflict).JSON(response)
+}
@@ -0,0 +1,101 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	"game-service/handlers"
+)
+
+func TestInternalRoutesRequireServiceSecret(t *testing.T) {
+	app := newTestApp()
+
+	status, created := do(t, app, "1", http.MethodPost, "/games/", "")
+	if status != fiber.StatusCreated {
+		t.Fatalf("create: status %d: %s", status, created.Error)
+	}
+	path := "/internal/games/" + created.Data.ID + "/abandon"
+
+	tests := []struct {
+		name   string
+		secret string
+		want   int
+	}{
+		{"missing", "", fiber.StatusUnauthorized},
+		{"wrong", "guess", fiber.StatusUnauthorized},
+		{"shared", testServiceSecret, fiber.StatusBadRequest}, // the game has not started
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"player_id":"1"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.secret != "" {
+				req.Header.Set(middleware.ServiceTokenHeader, tt.secret)
+			}
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveSeatsListsSeatedPlayersOfActiveGames(t *testing.T) {
+	app := newTestApp()
+
+	_, waiting := do(t, app, "1", http.MethodPost, "/games/", "")
+	_, active := do(t, app, "1", http.MethodPost, "/games/", "")
+	if status, joined := do(t, app, "2", http.MethodPost, "/games/"+active.Data.ID+"/join", ""); status != fiber.StatusOK {
+		t.Fatalf("join: status %d: %s", status, joined.Error)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/internal/games/active/seats", nil)
+	req.Header.Set(middleware.ServiceTokenHeader, testServiceSecret)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Data []handlers.Seats `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Data) != 1 {
+		t.Fatalf("%d games with seats, want only the active one and not %s", len(decoded.Data), waiting.Data.ID)
+	}
+	seats := decoded.Data[0]
+	if seats.GameID != active.Data.ID || !slices.Equal(seats.PlayerIDs, []string{"1", "2"}) || seats.Version == 0 {
+		t.Fatalf("seats %+v", seats)
+	}
+}
+
+func TestPlayersCarryTheUsernameOfTheirToken(t *testing.T) {
+	app := newTestApp()
+
+	_, created := do(t, app, "1", http.MethodPost, "/games/", "")
+	status, joined := do(t, app, "2", http.MethodPost, "/games/"+created.Data.ID+"/join", "")
+	if status != fiber.StatusOK {
+		t.Fatalf("join: status %d: %s", status, joined.Error)
+	}
+
+	for _, player := range joined.Data.Players {
+		if player.Username != testUsername(player.ID) {
+			t.Errorf("player %s has username %q, want %q", player.ID, player.Username, testUsername(player.ID))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+)
+
+// ImportGameRequest - JSON body of an import request; other bodies are read as the record itself
+type ImportGameRequest struct {
+	Notation string `json:"notation"`
+}
+
+// ImportedGame - a game rebuilt from its record
+type ImportedGame struct {
+	Game        domain.GameState        `json:"game"`
+	Headers     map[string]string       `json:"headers"`
+	Annotations []domain.MoveAnnotation `json:"annotations,omitempty"`
+}
+
+// ExportGame returns the record of a game in the requested notation
+func (h *GameHandler) ExportGame(c *fiber.Ctx) error {
+	if format := c.Query("format", "ttn"); format != "ttn" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("Unsupported format %s", format))
+	}
+
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	record, err := domain.FormatTTN(game)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.ttn"`, game.ID))
+	c.Type("txt", "utf-8")
+	return c.SendString(record)
+}
+
+// ImportGame validates a game record and returns the rebuilt game for analysis.
+// Imported games are not stored; annotate=true compares every move with perfect play.
+func (h *GameHandler) ImportGame(c *fiber.Ctx) error {
+	text := string(c.Body())
+	if c.Is("json") {
+		var req ImportGameRequest
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+		text = req.Notation
+	}
+
+	game, record, err := h.service.ImportGame(text)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	imported := ImportedGame{
+		Game:    game.GetGameState(),
+		Headers: record.Headers,
+	}
+	if c.QueryBool("annotate") {
+		if imported.Annotations, err = h.service.AnnotateGame(game, domain.DefaultAnnotationNodes); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	return utils.SuccessResponse(c, imported, "Game imported")
+}
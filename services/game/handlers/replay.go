@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+)
+
+const (
+	// maxReplaySpeed - fastest speed of a replay stream
+	maxReplaySpeed = 64
+	// defaultReplayDelay - pause between moves without timestamps at 1× speed
+	defaultReplayDelay = time.Second
+	// maxReplayDelay - longest pause between two moves at 1× speed
+	maxReplayDelay = 30 * time.Second
+)
+
+// ReplayMessage - message of a replay stream
+type ReplayMessage struct {
+	Type  string              `json:"type"` // "frame" or "end"
+	Frame *domain.ReplayFrame `json:"frame,omitempty"`
+}
+
+// replayStream - a finished game prepared for streaming over a WebSocket
+type replayStream struct {
+	frames []domain.ReplayFrame
+	speed  float64
+}
+
+// GetReplay returns the board, turn and clocks of a game at ply N, the last ply by default
+func (h *GameHandler) GetReplay(c *fiber.Ctx) error {
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	frame, err := h.service.GetReplayFrame(game, c.QueryInt("ply", len(game.Moves)))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SuccessResponse(c, frame, "")
+}
+
+// PrepareReplayStream loads a finished game before the connection is upgraded to a replay stream
+func (h *GameHandler) PrepareReplayStream(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return utils.ErrorResponse(c, fiber.StatusUpgradeRequired, "WebSocket upgrade required")
+	}
+
+	speed := c.QueryFloat("speed", 1)
+	if speed < 1 || speed > maxReplaySpeed {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("speed must be between 1 and %d", maxReplaySpeed))
+	}
+
+	game, err := h.repo.FindByID(c.Params("id"))
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	frames, err := h.service.GetReplayFrames(game)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	from := c.QueryInt("from", 0)
+	if from < 0 || from >= len(frames) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("from must be between 0 and %d", len(frames)-1))
+	}
+
+	c.Locals("replay", replayStream{frames: frames[from:], speed: speed})
+	return c.Next()
+}
+
+// StreamReplay sends the frames of a finished game with the pauses of the original game divided by speed
+func (h *GameHandler) StreamReplay(conn *websocket.Conn) {
+	stream, _ := conn.Locals("replay").(replayStream)
+
+	// Reading detects clients closing the stream early
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for i := range stream.frames {
+		if i > 0 {
+			select {
+			case <-time.After(replayDelay(stream.frames[i-1], stream.frames[i], stream.speed)):
+			case <-closed:
+				return
+			}
+		}
+
+		if err := conn.WriteJSON(ReplayMessage{Type: "frame", Frame: &stream.frames[i]}); err != nil {
+			log.Printf("Replay stream write: %v", err)
+			return
+		}
+	}
+
+	if err := conn.WriteJSON(ReplayMessage{Type: "end"}); err != nil {
+		log.Printf("Replay stream write: %v", err)
+	}
+}
+
+// replayDelay returns the pause before frame next as taken in the original game
+func replayDelay(previous, next domain.ReplayFrame, speed float64) time.Duration {
+	delay := defaultReplayDelay
+	if previous.Move != nil && !previous.Move.Timestamp.IsZero() && !next.Move.Timestamp.IsZero() {
+		delay = min(next.Move.Timestamp.Sub(previous.Move.Timestamp), maxReplayDelay)
+	}
+	return time.Duration(float64(delay) / speed)
+}
@@ -0,0 +1,45 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+)
+
+func TestReplayStreamAcceptsTokenQuery(t *testing.T) {
+	app := newTestApp()
+
+	token, err := utils.GenerateToken("1", "1@example.com", testSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"no token", "", fiber.StatusUnauthorized},
+		{"invalid token", "?token=invalid", fiber.StatusUnauthorized},
+		// Authenticated requests reach the stream, which only serves WebSocket upgrades
+		{"token query", "?token=" + token, fiber.StatusUpgradeRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/games/x/replay/stream"+tt.query, nil)
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
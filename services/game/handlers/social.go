@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+)
+
+// BlockChecker reports whether a block exists between the user and any of the others,
+// in either direction
+type BlockChecker func(userID string, otherIDs []string) (bool, error)
+
+// SetBlockChecker sets the check that keeps users out of games of players who blocked them
+// or whom they blocked. Without one, anyone may join.
+func (h *GameHandler) SetBlockChecker(check BlockChecker) {
+	h.blocks = check
+}
+
+// blockedFromGame checks the joining user against the players already seated
+func (h *GameHandler) blockedFromGame(game *domain.Game, userID string) (bool, error) {
+	if h.blocks == nil {
+		return false, nil
+	}
+
+	playerIDs := make([]string, 0, len(game.Players))
+	for _, player := range game.Players {
+		playerIDs = append(playerIDs, player.ID)
+	}
+	return h.blocks(userID, playerIDs)
+}
+
+// Invitations lists the waiting games with a seat reserved for the current user
+func (h *GameHandler) Invitations(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	games, err := h.repo.FindInvitations(userID)
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	invitations := make([]domain.GameState, 0, len(games))
+	for _, game := range games {
+		invitations = append(invitations, game.GetGameState())
+	}
+
+	return utils.SuccessResponse(c, invitations, "")
+}
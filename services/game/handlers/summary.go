@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+	"game-service/repository"
+)
+
+// Page sizes of the finished games listing
+const (
+	defaultSummaryLimit = 100
+	maxSummaryLimit     = 1000
+)
+
+// FinishedGames lists summaries of finished games in the order they finished,
+// so that other services can rebuild data derived from game.finished messages.
+// Pages continue after the finished_at and game_id of the last summary of the previous page,
+// given as after_finished_at (RFC 3339) and after_id, so games finishing meanwhile are not skipped.
+func (h *GameHandler) FinishedGames(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", defaultSummaryLimit)
+	if limit < 1 || limit > maxSummaryLimit {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "limit must be between 1 and 1000")
+	}
+
+	var after repository.FinishedCursor
+	if value := c.Query("after_finished_at"); value != "" {
+		finishedAt, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "after_finished_at must be an RFC 3339 time")
+		}
+		after = repository.FinishedCursor{FinishedAt: finishedAt, ID: c.Query("after_id")}
+	}
+
+	games, err := h.repo.FindFinished(after, limit)
+	if err != nil {
+		return repositoryError(c, err)
+	}
+
+	summaries := make([]domain.GameSummary, 0, len(games))
+	for _, game := range games {
+		summaries = append(summaries, game.Summary())
+	}
+
+	return utils.SuccessResponse(c, summaries, "")
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+	"game-service/events"
+	"game-service/repository"
+)
+
+// maxTournamentSaveAttempts bounds the retries of recording a game result
+// when other games of the round finished at the same time
+const maxTournamentSaveAttempts = 5
+
+// TournamentHandler serves tournaments and creates their games
+type TournamentHandler struct {
+	service     *domain.GameService
+	games       repository.GameRepository
+	tournaments repository.TournamentRepository
+	bus         *events.Bus
+}
+
+// NewTournamentHandler creates a new tournament handler
+func NewTournamentHandler(service *domain.GameService, games repository.GameRepository, tournaments repository.TournamentRepository, bus *events.Bus) *TournamentHandler {
+	return &TournamentHandler{
+		service:     service,
+		games:       games,
+		tournaments: tournaments,
+		bus:         bus,
+	}
+}
+
+// CreateTournamentRequest - body of a create tournament request
+type CreateTournamentRequest struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+
+	// Rounds of a Swiss tournament, enough for a single leader when 0
+	Rounds     int `json:"rounds"`
+	MaxPlayers int `json:"max_players"`
+
+	// Settings of every game
+	Variant       string `json:"variant"`
+	BoardSize     int    `json:"board_size"`
+	WinLength     int    `json:"win_length"`
+	Gravity       bool   `json:"gravity"`
+	TakebackLimit *int   `json:"takeback_limit"`
+}
+
+// TournamentSummary - a tournament in listings
+type TournamentSummary struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Format       domain.TournamentFormat `json:"format"`
+	Status       domain.TournamentStatus `json:"status"`
+	OrganizerID  string                  `json:"organizer_id"`
+	Players      int                     `json:"players"`
+	MaxPlayers   int                     `json:"max_players"`
+	CurrentRound int                     `json:"current_round"`
+	TotalRounds  int                     `json:"total_rounds"`
+	WinnerID     string                  `json:"winner_id,omitempty"`
+	CreatedAt    time.Time               `json:"created_at"`
+}
+
+// CreateTournament creates a tournament organized by the current user
+func (h *TournamentHandler) CreateTournament(c *fiber.Ctx) error {
+	var req CreateTournamentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	settings := domain.DefaultGameSettings()
+	if req.Variant != "" {
+		settings.Variant = domain.Variant(req.Variant)
+	}
+	settings.BoardSize = req.BoardSize
+	settings.WinLength = req.WinLength
+	settings.Gravity = req.Gravity
+	if req.TakebackLimit != nil {
+		settings.TakebackLimit = *req.TakebackLimit
+	}
+
+	tournament, err := h.service.CreateTournament(currentPlayer(c), req.Name, domain.TournamentFormat(req.Format), settings, req.Rounds, req.MaxPlayers)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.tournaments.Save(tournament); err != nil {
+		return tournamentSaveError(c, err)
+	}
+
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, tournament, "Tournament created")
+}
+
+// ListTournaments lists tournaments in creation order, optionally only those with the given status
+func (h *TournamentHandler) ListTournaments(c *fiber.Ctx) error {
+	tournaments, err := h.tournaments.FindAll()
+	if err != nil {
+		return tournamentError(c, err)
+	}
+
+	status := domain.TournamentStatus(c.Query("status"))
+	summaries := make([]TournamentSummary, 0, len(tournaments))
+	for _, tournament := range tournaments {
+		if status != "" && tournament.Status != status {
+			continue
+		}
+		summaries = append(summaries, TournamentSummary{
+			ID:           tournament.ID,
+			Name:         tournament.Name,
+			Format:       tournament.Format,
+			Status:       tournament.Status,
+			OrganizerID:  tournament.OrganizerID,
+			Players:      len(tournament.Entrants),
+			MaxPlayers:   tournament.MaxPlayers,
+			CurrentRound: len(tournament.Rounds),
+			TotalRounds:  tournament.TotalRounds,
+			WinnerID:     tournament.WinnerID,
+			CreatedAt:    tournament.CreatedAt,
+		})
+	}
+
+	return utils.SuccessResponse(c, summaries, "")
+}
+
+// GetTournament returns a tournament with its entrants and rounds
+func (h *TournamentHandler) GetTournament(c *fiber.Ctx) error {
+	tournament, err := h.tournaments.FindByID(c.Params("id"))
+	if err != nil {
+		return tournamentError(c, err)
+	}
+
+	return utils.SuccessResponse(c, tournament, "")
+}
+
+// GetStandings returns the ranking of a tournament with its tiebreaks
+func (h *TournamentHandler) GetStandings(c *fiber.Ctx) error {
+	tournament, err := h.tournaments.FindByID(c.Params("id"))
+	if err != nil {
+		return tournamentError(c, err)
+	}
+
+	return utils.SuccessResponse(c, tournament.Standings(), "")
+}
+
+// GetBracket returns every round of a tournament, including those still to be played where known
+func (h *TournamentHandler) GetBracket(c *fiber.Ctx) error {
+	tournament, err := h.tournaments.FindByID(c.Params("id"))
+	if err != nil {
+		return tournamentError(c, err)
+	}
+
+	return utils.SuccessResponse(c, tournament.Bracket(), "")
+}
+
+// Register signs the current user up for a tournament
+func (h *TournamentHandler) Register(c *fiber.Ctx) error {
+	return h.tournamentAction(c, "Registered", func(tournament *domain.Tournament) error {
+		return tournament.Register(currentPlayer(c))
+	})
+}
+
+// Withdraw removes the current user from a tournament that has not started
+func (h *TournamentHandler) Withdraw(c *fiber.Ctx) error {
+	return h.tournamentAction(c, "Withdrawn", func(tournament *domain.Tournament) error {
+		return tournament.Withdraw(currentPlayer(c).ID)
+	})
+}
+
+// Start closes registration and creates the games of the first round. Only the organizer may start.
+func (h *TournamentHandler) Start(c *fiber.Ctx) error {
+	tournament, err := h.tournaments.FindByID(c.Params("id"))
+	if err != nil {
+		return tournamentError(c, err)
+	}
+
+	if tournament.OrganizerID != currentPlayer(c).ID {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Only the organizer can start the tournament")
+	}
+
+	if err := tournament.Start(); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	games, err := h.pairGames(tournament)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create games")
+	}
+
+	if err := h.tournaments.Save(tournament); err != nil {
+		return tournamentSaveError(c, err)
+	}
+
+	if err := h.saveGames(games); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create games")
+	}
+
+	return utils.SuccessResponse(c, tournament, "Tournament started")
+}
+
+// GameFinished records the result of a finished game if it belongs to a tournament,
+// pairing the next round once it was the last game of its round
+func (h *TournamentHandler) GameFinished(gameID string) {
+	for attempt := 0; attempt < maxTournamentSaveAttempts; attempt++ {
+		err := h.recordGame(gameID)
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, repository.ErrConcurrentModification) {
+			log.Printf("Failed to record tournament game %s: %v", gameID, err)
+			return
+		}
+	}
+	log.Printf("Failed to record tournament game %s: tournament kept changing", gameID)
+}
+
+// recordGame loads the tournament of the game, records the result and saves it
+func (h *TournamentHandler) recordGame(gameID string) error {
+	tournament, err := h.tournaments.FindByGameID(gameID)
+	if errors.Is(err, repository.ErrTournamentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	game, err := h.games.FindByID(gameID)
+	if err != nil {
+		return err
+	}
+
+	changed, err := tournament.RecordGame(game)
+	if err != nil || !changed {
+		return err
+	}
+
+	games, err := h.pairGames(tournament)
+	if err != nil {
+		return err
+	}
+	if err := h.tournaments.Save(tournament); err != nil {
+		return err
+	}
+	return h.saveGames(games)
+}
+
+// pairGames creates the games of the current round that do not exist yet and assigns them
+// to their pairings. They are only saved once the tournament is saved with them, so that
+// a tournament save lost to a concurrent change and retried leaves no duplicate games behind.
+func (h *TournamentHandler) pairGames(tournament *domain.Tournament) ([]*domain.Game, error) {
+	var games []*domain.Game
+	for _, pairing := range tournament.PairingsWithoutGame() {
+		game, err := h.service.CreateTournamentGame(tournament, pairing)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// saveGames saves and announces the games paired for a saved tournament
+func (h *TournamentHandler) saveGames(games []*domain.Game) error {
+	for _, game := range games {
+		if err := h.games.Save(game); err != nil {
+			return err
+		}
+		h.bus.Publish(game.PullEvents()...)
+	}
+	return nil
+}
+
+// tournamentAction loads the tournament, runs action and saves the result
+func (h *TournamentHandler) tournamentAction(c *fiber.Ctx, message string, action func(*domain.Tournament) error) error {
+	tournament, err := h.tournaments.FindByID(c.Params("id"))
+	if err != nil {
+		return tournamentError(c, err)
+	}
+
+	if err := action(tournament); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.tournaments.Save(tournament); err != nil {
+		return tournamentSaveError(c, err)
+	}
+
+	return utils.SuccessResponse(c, tournament, message)
+}
+
+// tournamentError maps repository errors to HTTP responses
+func tournamentError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrTournamentNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Tournament not found")
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load tournament")
+}
+
+// tournamentSaveError maps save errors to HTTP responses
+func tournamentSaveError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrConcurrentModification) {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Tournament was changed by another request")
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to save tournament")
+}
@@ -0,0 +1,154 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"game-service/domain"
+	"game-service/events"
+	"game-service/handlers"
+	"game-service/repository"
+	"game-service/routes"
+)
+
+// conflictingTournamentRepository fails the next saves as if the tournament had been changed concurrently
+type conflictingTournamentRepository struct {
+	repository.TournamentRepository
+	conflicts int
+}
+
+func (r *conflictingTournamentRepository) Save(tournament *domain.Tournament) error {
+	if r.conflicts > 0 {
+		r.conflicts--
+		return repository.ErrConcurrentModification
+	}
+	return r.TournamentRepository.Save(tournament)
+}
+
+func doTournament(t *testing.T, app *fiber.App, userID, method, path, body string) (int, domain.Tournament) {
+	t.Helper()
+
+	token, err := utils.GenerateToken(userID, userID+"@example.com", testSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Data domain.Tournament `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		t.Fatalf("decode %s %s: %v", method, path, err)
+	}
+	return resp.StatusCode, decoded.Data
+}
+
+func TestTournamentSaveConflictsCreateNoDuplicateGames(t *testing.T) {
+	service := domain.NewGameService()
+	games := repository.NewMemoryGameRepository()
+	tournaments := &conflictingTournamentRepository{TournamentRepository: repository.NewMemoryTournamentRepository()}
+	tournamentHandler := handlers.NewTournamentHandler(service, games, tournaments, events.NewBus())
+
+	app := fiber.New()
+	gameHandler := handlers.NewGameHandler(service, games, events.NewBus())
+	gameHandler.SetTournamentRepository(tournaments)
+	routes.Setup(app, gameHandler, tournamentHandler, testSecret, testServiceSecret)
+
+	status, tournament := doTournament(t, app, "1", http.MethodPost, "/tournaments/", `{"name": "Cup", "format": "round_robin"}`)
+	if status != fiber.StatusCreated {
+		t.Fatalf("create: status %d", status)
+	}
+	path := "/tournaments/" + tournament.ID
+	for player := 1; player <= 4; player++ {
+		if status, _ := doTournament(t, app, fmt.Sprint(player), http.MethodPost, path+"/register", ""); status != fiber.StatusOK {
+			t.Fatalf("register %d: status %d", player, status)
+		}
+	}
+
+	storedGames := func() int {
+		t.Helper()
+		all, err := games.FindAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(all)
+	}
+
+	tournaments.conflicts = 1
+	if status, _ := doTournament(t, app, "1", http.MethodPost, path+"/start", ""); status != fiber.StatusConflict {
+		t.Fatalf("conflicting start: status %d", status)
+	}
+	if n := storedGames(); n != 0 {
+		t.Fatalf("conflicting start stored %d games", n)
+	}
+
+	status, tournament = doTournament(t, app, "1", http.MethodPost, path+"/start", "")
+	if status != fiber.StatusOK {
+		t.Fatalf("start: status %d", status)
+	}
+	if n := storedGames(); n != 2 {
+		t.Fatalf("first round has %d games, want 2", n)
+	}
+
+	token, err := utils.GenerateTokenWithRoles("9", "9@example.com", "admin", []string{middleware.RoleAdmin},
+		[]string{middleware.PermissionDeleteGames}, testSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodDelete, "/games/"+tournament.CurrentRound().Pairings[0].GameID, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusConflict || storedGames() != 2 {
+		t.Fatalf("deleting a tournament game: status %d", resp.StatusCode)
+	}
+
+	// The last game of the round pairs the next one, retrying the conflicting save
+	for i, pairing := range tournament.CurrentRound().Pairings {
+		game, err := games.FindByID(pairing.GameID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, position := range []int{0, 3, 1, 4, 2} {
+			if err := game.MakeMove(game.CurrentTurn, position); err != nil {
+				t.Fatalf("move %d: %v", position, err)
+			}
+		}
+		if err := games.Save(game); err != nil {
+			t.Fatal(err)
+		}
+
+		if i == 1 {
+			tournaments.conflicts = 1
+		}
+		tournamentHandler.GameFinished(game.ID)
+	}
+
+	if n := storedGames(); n != 4 {
+		t.Fatalf("%d games stored after two rounds were paired, want 4", n)
+	}
+	if tournaments.conflicts != 0 {
+		t.Fatal("the conflicting save was not retried")
+	}
+}
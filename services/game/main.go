@@ -1,13 +1,55 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/your-org/go-tic-tac-toe/pkg/config"
+	"github.com/your-org/go-tic-tac-toe/pkg/database"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/outbox"
+
+	"game-service/domain"
+	"game-service/events"
+	"game-service/handlers"
+	"game-service/repository"
+	"game-service/routes"
 )
 
 func main() {
+	cfg := config.Load()
+
+	gameService := domain.NewGameService()
+	gameRepo, tournamentRepo := newRepositories(cfg)
+	eventBus := events.NewBus()
+	eventBus.SubscribeAll(func(event domain.Event) {
+		log.Printf("Game %s: %s", event.AggregateID(), event.EventName())
+	})
+	gameHandler := handlers.NewGameHandler(gameService, gameRepo, eventBus)
+	gameHandler.SetTournamentRepository(tournamentRepo)
+	tournamentHandler := handlers.NewTournamentHandler(gameService, gameRepo, tournamentRepo, eventBus)
+
+	// Finished tournament games advance their tournament
+	events.Subscribe(eventBus, func(event domain.GameWon) { tournamentHandler.GameFinished(event.AggregateID()) })
+	events.Subscribe(eventBus, func(event domain.GameDrawn) { tournamentHandler.GameFinished(event.AggregateID()) })
+	events.Subscribe(eventBus, func(event domain.GameAbandoned) { tournamentHandler.GameFinished(event.AggregateID()) })
+	if userServiceURL := os.Getenv("USER_SERVICE_URL"); userServiceURL != "" {
+		gameHandler.SetBlockChecker(userServiceBlockChecker(userServiceURL, cfg.Service.Secret))
+	}
+
+	// Seated players of active games forfeit when they do not connect to the chat service or stay away
+	if chatServiceURL := os.Getenv("CHAT_SERVICE_URL"); chatServiceURL != "" {
+		reportSeats := chatServiceSeats(chatServiceURL, cfg.Service.Secret, gameRepo)
+		events.Subscribe(eventBus, func(event domain.PlayerJoined) { reportSeats(event.AggregateID()) })
+		events.Subscribe(eventBus, func(event domain.PlayerEliminated) { reportSeats(event.AggregateID()) })
+		events.Subscribe(eventBus, func(event domain.GameWon) { reportSeats(event.AggregateID()) })
+		events.Subscribe(eventBus, func(event domain.GameDrawn) { reportSeats(event.AggregateID()) })
+		events.Subscribe(eventBus, func(event domain.GameAbandoned) { reportSeats(event.AggregateID()) })
+	}
+
 	app := fiber.New()
 
 	// CORS middleware
@@ -29,26 +71,66 @@ func main() {
 		})
 	})
 
-	app.Post("/games", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "Create game endpoint",
-			"status": "not implemented yet",
-		})
-	})
+	routes.Setup(app, gameHandler, tournamentHandler, cfg.JWT.Secret, cfg.Service.Secret)
 
-	app.Get("/games/:id", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "Get game endpoint",
-			"status": "not implemented yet",
-		})
-	})
+	log.Fatal(app.Listen(":8083"))
+}
 
-	app.Post("/games/:id/move", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "Make move endpoint",
-			"status": "not implemented yet",
-		})
-	})
+// newRepositories returns an event-sourced game repository with a running outbox relay
+// and a Postgres tournament repository, or in-memory ones if asked to with STORAGE=memory.
+// It exits when the database is unavailable rather than losing games and their events.
+func newRepositories(cfg *config.Config) (repository.GameRepository, repository.TournamentRepository) {
+	if cfg.Database.InMemory {
+		log.Println("STORAGE=memory, games and their events are lost on exit")
+		return repository.NewMemoryGameRepository(), repository.NewMemoryTournamentRepository()
+	}
 
-	log.Fatal(app.Listen(":8083"))
-} 
\ No newline at end of file
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Database unavailable: %v", err)
+	}
+
+	if err := repository.MigrateGameIDs(db); err != nil {
+		log.Fatal(err)
+	}
+	if err := database.AutoMigrate(db, &models.Game{}, &models.GameStream{}, &models.GameEvent{}, &models.GameSnapshot{}, &models.Tournament{}, &models.TournamentGame{}); err != nil {
+		log.Fatal(err)
+	}
+	if err := repository.BackfillGameStreams(db); err != nil {
+		log.Fatal(err)
+	}
+	if err := outbox.Migrate(db); err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.Outbox.MaxAttempts < 1 {
+		log.Fatal("OUTBOX_MAX_ATTEMPTS must be at least 1")
+	}
+
+	var publishers []outbox.Publisher
+	if len(cfg.Outbox.WebhookURLs) > 0 {
+		// Subscribers get a secret of their own rather than the service secret
+		if len(cfg.Outbox.WebhookSecrets) != len(cfg.Outbox.WebhookURLs) {
+			log.Fatal("OUTBOX_WEBHOOK_SECRETS must have a secret for every URL of OUTBOX_WEBHOOK_URLS")
+		}
+		webhooks := make([]outbox.Webhook, 0, len(cfg.Outbox.WebhookURLs))
+		for i, url := range cfg.Outbox.WebhookURLs {
+			webhooks = append(webhooks, outbox.Webhook{URL: url, Secret: cfg.Outbox.WebhookSecrets[i]})
+		}
+		publishers = append(publishers, outbox.NewWebhookPublisher(webhooks...))
+	}
+	if cfg.Outbox.NotifyChannel != "" {
+		publishers = append(publishers, outbox.NewNotifyPublisher(db, cfg.Outbox.NotifyChannel))
+	}
+
+	// Without subscribers, messages stay pending until a relay with some is started
+	relay, err := outbox.NewRelay(db, publishers...)
+	if err != nil {
+		log.Printf("Outbox relay not started: %v", err)
+	} else {
+		relay.MaxAttempts = cfg.Outbox.MaxAttempts
+		go relay.Run(context.Background())
+	}
+
+	return repository.NewEventStoreGameRepository(db), repository.NewPostgresTournamentRepository(db)
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"game-service/domain"
+)
+
+// defaultSnapshotInterval is the number of events between snapshots
+const defaultSnapshotInterval = 10
+
+// EventStoreGameRepository persists games as append-only event streams.
+// Loads start from the latest snapshot and fold the remaining events;
+// saves append pending events only if the stream is still at the loaded version.
+// The games table is kept as a read model for listings.
+type EventStoreGameRepository struct {
+	db               *gorm.DB
+	snapshotInterval int
+}
+
+// NewEventStoreGameRepository creates an event-sourced repository
+func NewEventStoreGameRepository(db *gorm.DB) *EventStoreGameRepository {
+	return &EventStoreGameRepository{
+		db:               db,
+		snapshotInterval: defaultSnapshotInterval,
+	}
+}
+
+// Save appends pending events, updates the read model and enqueues events to the outbox
+func (r *EventStoreGameRepository) Save(game *domain.Game) error {
+	return r.SaveAll(game)
+}
+
+// SaveAll saves the games like Save in one transaction
+func (r *EventStoreGameRepository) SaveAll(games ...*domain.Game) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, game := range games {
+			if err := r.save(tx, game); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// save appends the pending events of the game with tx
+func (r *EventStoreGameRepository) save(tx *gorm.DB, game *domain.Game) error {
+	pending := game.PendingEvents()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	expected := game.PersistedVersion()
+	if err := advanceStream(tx, game.ID, expected, game.Version); err != nil {
+		return err
+	}
+
+	rows := make([]models.GameEvent, 0, len(pending))
+	for i, event := range pending {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		rows = append(rows, models.GameEvent{
+			StreamID:   game.ID,
+			Version:    expected + i + 1,
+			EventType:  event.EventName(),
+			Payload:    string(payload),
+			OccurredAt: event.OccurredOn(),
+		})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return fmt.Errorf("failed to append events: %w", err)
+	}
+
+	if game.Version/r.snapshotInterval > expected/r.snapshotInterval {
+		if err := saveSnapshot(tx, game); err != nil {
+			return err
+		}
+	}
+
+	if err := upsertGame(tx, game); err != nil {
+		return err
+	}
+	return enqueueEvents(tx, game)
+}
+
+// FindByID rebuilds the game from its latest snapshot and later events.
+// Games listed by the read model without a stream are adopted into the event store first.
+func (r *EventStoreGameRepository) FindByID(id string) (*domain.Game, error) {
+	game, err := r.loadSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := 0
+	if game != nil {
+		from = game.Version
+	}
+	var rows []models.GameEvent
+	if err := r.db.Where("stream_id = ? AND version > ?", id, from).Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+
+	if game == nil && len(rows) == 0 {
+		return r.adopt(id)
+	}
+	if game == nil {
+		game = &domain.Game{}
+	}
+
+	events := make([]domain.Event, 0, len(rows))
+	for _, row := range rows {
+		event, err := domain.DecodeEvent(row.EventType, []byte(row.Payload))
+		if err != nil {
+			return nil, fmt.Errorf("game %s version %d: %w", id, row.Version, err)
+		}
+		events = append(events, event)
+	}
+
+	if err := game.ApplyEvents(events); err != nil {
+		return nil, fmt.Errorf("game %s: %w", id, err)
+	}
+	return game, nil
+}
+
+// FindAll returns all games from the read model
+func (r *EventStoreGameRepository) FindAll() ([]*domain.Game, error) {
+	return NewPostgresGameRepository(r.db).FindAll()
+}
+
+// FindBySeriesID returns all games of a rematch series from the read model
+func (r *EventStoreGameRepository) FindBySeriesID(seriesID string) ([]*domain.Game, error) {
+	return NewPostgresGameRepository(r.db).FindBySeriesID(seriesID)
+}
+
+// FindActive returns the games in progress from the read model
+func (r *EventStoreGameRepository) FindActive() ([]*domain.Game, error) {
+	return NewPostgresGameRepository(r.db).FindActive()
+}
+
+// FindInvitations returns the waiting games reserving a seat for the user from the read model
+func (r *EventStoreGameRepository) FindInvitations(userID string) ([]*domain.Game, error) {
+	return NewPostgresGameRepository(r.db).FindInvitations(userID)
+}
+
+// FindFinished returns up to limit finished games after the cursor from the read model
+func (r *EventStoreGameRepository) FindFinished(after FinishedCursor, limit int) ([]*domain.Game, error) {
+	return NewPostgresGameRepository(r.db).FindFinished(after, limit)
+}
+
+// Delete removes the event stream of the game with its snapshot and soft-deletes the read model.
+// Saves of the game loaded before fail with ErrConcurrentModification.
+func (r *EventStoreGameRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		streams := tx.Where("stream_id = ?", id).Delete(&models.GameStream{})
+		if streams.Error != nil {
+			return fmt.Errorf("failed to delete stream: %w", streams.Error)
+		}
+		if err := tx.Where("stream_id = ?", id).Delete(&models.GameEvent{}).Error; err != nil {
+			return fmt.Errorf("failed to delete events: %w", err)
+		}
+		if err := tx.Where("stream_id = ?", id).Delete(&models.GameSnapshot{}).Error; err != nil {
+			return fmt.Errorf("failed to delete snapshot: %w", err)
+		}
+		games := tx.Where("id = ?", id).Delete(&models.Game{})
+		if games.Error != nil {
+			return fmt.Errorf("failed to delete game: %w", games.Error)
+		}
+		// Games not adopted yet only have their read model
+		if streams.RowsAffected == 0 && games.RowsAffected == 0 {
+			return ErrGameNotFound
+		}
+		return nil
+	})
+}
+
+// adopt loads a game the read model has no stream for, such as one stored before games
+// were event-sourced, and starts its stream at the version of the row
+func (r *EventStoreGameRepository) adopt(id string) (*domain.Game, error) {
+	game, err := NewPostgresGameRepository(r.db).FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error { return adoptGame(tx, game) }); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// loadSnapshot returns the snapshotted game or nil if the stream has no snapshot
+func (r *EventStoreGameRepository) loadSnapshot(id string) (*domain.Game, error) {
+	var row models.GameSnapshot
+	err := r.db.Where("stream_id = ?", id).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	var snapshot domain.GameSnapshot
+	if err := json.Unmarshal([]byte(row.State), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return domain.RestoreGame(snapshot)
+}
+
+// advanceStream moves the stream from the expected version to the new one.
+// It fails with ErrConcurrentModification if another save got there first.
+func advanceStream(tx *gorm.DB, streamID string, expected, version int) error {
+	now := time.Now()
+
+	if expected == 0 {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.GameStream{
+			StreamID:  streamID,
+			Version:   version,
+			UpdatedAt: now,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to create stream: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+		// The stream exists, which is fine for a game adopted at version 0 that is still there
+	}
+
+	result := tx.Model(&models.GameStream{}).
+		Where("stream_id = ? AND version = ?", streamID, expected).
+		Updates(map[string]interface{}{"version": version, "updated_at": now})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update stream: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+// adoptGame starts the stream of a game stored only in the read model at its current version,
+// with a snapshot of its state as the stream has no events to fold. Streams started
+// in the meantime are left alone.
+func adoptGame(tx *gorm.DB, game *domain.Game) error {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.GameStream{
+		StreamID:  game.ID,
+		Version:   game.Version,
+		UpdatedAt: time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to create stream: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+	return saveSnapshot(tx, game)
+}
+
+// saveSnapshot replaces the snapshot of the game stream
+func saveSnapshot(tx *gorm.DB, game *domain.Game) error {
+	state, err := json.Marshal(game.Snapshot())
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	err = tx.Save(&models.GameSnapshot{
+		StreamID:  game.ID,
+		Version:   game.Version,
+		State:     string(state),
+		CreatedAt: time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
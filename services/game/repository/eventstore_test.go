@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/database"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/outbox"
+	"gorm.io/gorm"
+)
+
+// eventStoreTables are the tables of the event-sourced repository, recreated by migrateEventStore
+var eventStoreTables = []interface{}{&models.Game{}, &models.GameStream{}, &models.GameEvent{}, &models.GameSnapshot{}}
+
+// migrateEventStore creates the tables of the event-sourced repository and the outbox,
+// dropping them again after the test
+func migrateEventStore(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	t.Cleanup(func() { db.Migrator().DropTable(eventStoreTables...) })
+
+	if err := database.AutoMigrate(db, eventStoreTables...); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEventStoreLoadsListedGamesWithoutStream(t *testing.T) {
+	db := testDatabase(t)
+	if err := db.Migrator().DropTable(eventStoreTables...); err != nil {
+		t.Fatal(err)
+	}
+	migrateEventStore(t, db)
+
+	// Stored in the read model only, like games saved before they were event-sourced
+	legacy := newActiveGame(t, NewPostgresGameRepository(db))
+	repo := NewEventStoreGameRepository(db)
+
+	listed, err := repo.FindAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].ID != legacy.ID {
+		t.Fatalf("listed %d games, want game %s", len(listed), legacy.ID)
+	}
+
+	game, err := repo.FindByID(listed[0].ID)
+	if err != nil {
+		t.Fatalf("loading a listed game: %v", err)
+	}
+	if game.Version != legacy.Version || len(game.Players) != 2 {
+		t.Fatalf("loaded version %d with %d players, want version %d with 2", game.Version, len(game.Players), legacy.Version)
+	}
+
+	if err := game.MakeMove(game.CurrentTurn, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(game); err != nil {
+		t.Fatalf("saving an adopted game: %v", err)
+	}
+
+	reloaded, err := repo.FindByID(game.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Version != legacy.Version+1 || reloaded.Board.GetCell(1, 1) != "X" {
+		t.Fatalf("reloaded version %d with board %v", reloaded.Version, reloaded.Board.GetState())
+	}
+}
+
+func TestEventStoreFindInvitations(t *testing.T) {
+	db := testDatabase(t)
+	if err := db.Migrator().DropTable(eventStoreTables...); err != nil {
+		t.Fatal(err)
+	}
+	migrateEventStore(t, db)
+
+	testFindInvitations(t, NewEventStoreGameRepository(db))
+}
+
+func TestEventStoreSaveAllIsAtomic(t *testing.T) {
+	db := testDatabase(t)
+	if err := db.Migrator().DropTable(eventStoreTables...); err != nil {
+		t.Fatal(err)
+	}
+	migrateEventStore(t, db)
+
+	testSaveAllIsAtomic(t, NewEventStoreGameRepository(db))
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"game-service/domain"
+)
+
+// MemoryGameRepository keeps games in process memory.
+// Games are stored as snapshots so that callers never share an aggregate.
+type MemoryGameRepository struct {
+	games map[string]domain.GameSnapshot
+	mu    sync.RWMutex
+}
+
+// NewMemoryGameRepository creates an empty in-memory repository
+func NewMemoryGameRepository() *MemoryGameRepository {
+	return &MemoryGameRepository{
+		games: make(map[string]domain.GameSnapshot),
+	}
+}
+
+// Save stores the game if nobody saved it since it was loaded
+func (r *MemoryGameRepository) Save(game *domain.Game) error {
+	return r.SaveAll(game)
+}
+
+// SaveAll stores the games if nobody saved any of them since they were loaded
+func (r *MemoryGameRepository) SaveAll(games ...*domain.Game) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, game := range games {
+		stored, exists := r.games[game.ID]
+		if exists && stored.Version != game.PersistedVersion() {
+			return ErrConcurrentModification
+		}
+		if !exists && game.PersistedVersion() != 0 {
+			return ErrConcurrentModification
+		}
+	}
+
+	for _, game := range games {
+		r.games[game.ID] = game.Snapshot()
+	}
+	return nil
+}
+
+// FindByID returns the game with the given ID
+func (r *MemoryGameRepository) FindByID(id string) (*domain.Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot, ok := r.games[id]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	return domain.RestoreGame(snapshot)
+}
+
+// FindAll returns all stored games
+func (r *MemoryGameRepository) FindAll() ([]*domain.Game, error) {
+	return r.find(func(domain.GameSnapshot) bool { return true })
+}
+
+// FindBySeriesID returns all games of a rematch series
+func (r *MemoryGameRepository) FindBySeriesID(seriesID string) ([]*domain.Game, error) {
+	return r.find(func(snapshot domain.GameSnapshot) bool {
+		return snapshot.SeriesID == seriesID
+	})
+}
+
+// FindActive returns the games in progress
+func (r *MemoryGameRepository) FindActive() ([]*domain.Game, error) {
+	return r.find(func(snapshot domain.GameSnapshot) bool {
+		return snapshot.Status == domain.GameStatusActive
+	})
+}
+
+// FindInvitations returns the waiting games with a seat reserved for the user, who has not taken it yet
+func (r *MemoryGameRepository) FindInvitations(userID string) ([]*domain.Game, error) {
+	return r.find(func(snapshot domain.GameSnapshot) bool {
+		if snapshot.Status != domain.GameStatusWaiting || !slices.Contains(snapshot.Settings.Invited, userID) {
+			return false
+		}
+		return !slices.ContainsFunc(snapshot.Players, func(player *domain.Player) bool { return player.ID == userID })
+	})
+}
+
+// FindFinished returns up to limit finished games after the cursor, ordered by finish time and ID
+func (r *MemoryGameRepository) FindFinished(after FinishedCursor, limit int) ([]*domain.Game, error) {
+	games, err := r.find(func(snapshot domain.GameSnapshot) bool {
+		return snapshot.Status == domain.GameStatusFinished && snapshot.FinishedAt != nil &&
+			finishedAfter(*snapshot.FinishedAt, snapshot.ID, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		return finishedAfter(*games[j].FinishedAt, games[j].ID, FinishedCursor{FinishedAt: *games[i].FinishedAt, ID: games[i].ID})
+	})
+	if len(games) > limit {
+		games = games[:limit]
+	}
+	return games, nil
+}
+
+// finishedAfter checks if a game finished at finishedAt with the given ID comes after the cursor
+func finishedAfter(finishedAt time.Time, id string, cursor FinishedCursor) bool {
+	if !finishedAt.Equal(cursor.FinishedAt) {
+		return finishedAt.After(cursor.FinishedAt)
+	}
+	return id > cursor.ID
+}
+
+// Delete removes the game with the given ID
+func (r *MemoryGameRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.games[id]; !ok {
+		return ErrGameNotFound
+	}
+	delete(r.games, id)
+	return nil
+}
+
+// find restores stored games matching filter in creation order, as the Postgres repository does
+func (r *MemoryGameRepository) find(filter func(domain.GameSnapshot) bool) ([]*domain.Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var games []*domain.Game
+	for _, snapshot := range r.games {
+		if !filter(snapshot) {
+			continue
+		}
+		game, err := domain.RestoreGame(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		if !games[i].CreatedAt.Equal(games[j].CreatedAt) {
+			return games[i].CreatedAt.Before(games[j].CreatedAt)
+		}
+		return games[i].ID < games[j].ID
+	})
+	return games, nil
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"game-service/domain"
+)
+
+func newActiveGame(t *testing.T, repo GameRepository) *domain.Game {
+	t.Helper()
+
+	service := domain.NewGameService()
+	game := service.CreateGame(domain.NewPlayer("1", "alice", ""))
+	if err := service.JoinGame(game, domain.NewPlayer("2", "bob", "")); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if err := repo.Save(game); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	game.PullEvents()
+	return game
+}
+
+func TestMemoryGameRepositoryRejectsStaleSave(t *testing.T) {
+	repo := NewMemoryGameRepository()
+	game := newActiveGame(t, repo)
+
+	first, err := repo.FindByID(game.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	second, err := repo.FindByID(game.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if err := first.MakeMove(first.CurrentTurn, 0); err != nil {
+		t.Fatalf("first move: %v", err)
+	}
+	if err := second.MakeMove(second.CurrentTurn, 4); err != nil {
+		t.Fatalf("second move: %v", err)
+	}
+
+	if err := repo.Save(first); err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+	if err := repo.Save(second); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("second save: got %v, want ErrConcurrentModification", err)
+	}
+
+	latest, err := repo.FindByID(game.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if latest.Board.GetCell(0, 0) != "X" || latest.Board.GetCell(1, 1) != "" {
+		t.Fatalf("unexpected board %v", latest.Board.GetState())
+	}
+}
+
+func TestMemoryGameRepositoryConcurrentMoves(t *testing.T) {
+	repo := NewMemoryGameRepository()
+	game := newActiveGame(t, repo)
+
+	const workers = 32
+	var saved, conflicts atomic.Int64
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for attempt := 0; attempt < 20; attempt++ {
+				current, err := repo.FindByID(game.ID)
+				if err != nil {
+					t.Errorf("load: %v", err)
+					return
+				}
+				if current.Status != domain.GameStatusActive {
+					return
+				}
+
+				position := (w + attempt) % 9
+				if err := current.MakeMove(current.CurrentTurn, position); err != nil {
+					continue
+				}
+
+				switch err := repo.Save(current); {
+				case err == nil:
+					saved.Add(1)
+				case errors.Is(err, ErrConcurrentModification):
+					conflicts.Add(1)
+				default:
+					t.Errorf("save: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	final, err := repo.FindByID(game.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	var x, o int
+	for _, row := range final.Board.GetState() {
+		for _, cell := range row {
+			switch cell {
+			case "X":
+				x++
+			case "O":
+				o++
+			}
+		}
+	}
+
+	if int64(x+o) != saved.Load() {
+		t.Fatalf("board has %d symbols, but %d moves were saved", x+o, saved.Load())
+	}
+	if x-o != 0 && x-o != 1 {
+		t.Fatalf("turn order broken: %d X and %d O", x, o)
+	}
+	t.Logf("saved %d moves, rejected %d stale saves", saved.Load(), conflicts.Load())
+}
+
+func TestMemoryGameRepositoryFindFinishedPages(t *testing.T) {
+	repo := NewMemoryGameRepository()
+	finish := func(game *domain.Game) {
+		t.Helper()
+		if err := game.Resign(game.Players[0]); err != nil {
+			t.Fatalf("resign: %v", err)
+		}
+		if err := repo.Save(game); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	var games []*domain.Game
+	for i := 0; i < 5; i++ {
+		games = append(games, newActiveGame(t, repo))
+	}
+	newActiveGame(t, repo)
+
+	// Games finish in another order than they were created
+	var want []string
+	for _, i := range []int{3, 0, 4} {
+		finish(games[i])
+		want = append(want, games[i].ID)
+	}
+
+	var got []string
+	var after FinishedCursor
+	for {
+		page, err := repo.FindFinished(after, 2)
+		if err != nil {
+			t.Fatalf("find: %v", err)
+		}
+		for _, game := range page {
+			got = append(got, game.ID)
+		}
+		if len(page) < 2 {
+			break
+		}
+
+		last := page[len(page)-1]
+		after = FinishedCursor{FinishedAt: *last.FinishedAt, ID: last.ID}
+
+		// A game finishing during the backfill is found on a later page
+		if len(want) == 3 {
+			finish(games[1])
+			want = append(want, games[1].ID)
+		}
+	}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// testFindInvitations checks that repo finds the waiting games reserving a seat for a user
+func testFindInvitations(t *testing.T, repo GameRepository) {
+	t.Helper()
+	service := domain.NewGameService()
+	create := func(settings domain.GameSettings, joined ...string) *domain.Game {
+		t.Helper()
+		game, err := service.CreateGameWithSettings(domain.NewPlayer("1", "alice", ""), settings)
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		for _, id := range joined {
+			if err := service.JoinGame(game, domain.NewPlayer(id, "", "")); err != nil {
+				t.Fatalf("join: %v", err)
+			}
+		}
+		if err := repo.Save(game); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		return game
+	}
+
+	invited := domain.DefaultGameSettings()
+	invited.Invited = []string{"2"}
+	forTwo := create(invited)
+	create(invited, "2") // started
+	open := domain.DefaultGameSettings()
+	create(open)
+
+	party := domain.DefaultGameSettings()
+	party.Variant, party.Players, party.Invited = domain.VariantConnect, 3, []string{"2", "3"}
+	joinedByTwo := create(party, "2")
+
+	for userID, want := range map[string][]string{"2": {forTwo.ID}, "3": {joinedByTwo.ID}, "4": nil} {
+		games, err := repo.FindInvitations(userID)
+		if err != nil {
+			t.Fatalf("find: %v", err)
+		}
+		var got []string
+		for _, game := range games {
+			got = append(got, game.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("invitations of %s: %v, want %v", userID, got, want)
+		}
+	}
+}
+
+func TestMemoryGameRepositoryFindInvitations(t *testing.T) {
+	testFindInvitations(t, NewMemoryGameRepository())
+}
+
+// testSaveAllIsAtomic checks that repo saves none of the games when one of them is stale
+func testSaveAllIsAtomic(t *testing.T, repo GameRepository) {
+	t.Helper()
+	game := newActiveGame(t, repo)
+	stale, err := repo.FindByID(game.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if err := game.MakeMove(game.CurrentTurn, 0); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if err := repo.Save(game); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	game.PullEvents()
+	if err := stale.MakeMove(stale.CurrentTurn, 4); err != nil {
+		t.Fatalf("stale move: %v", err)
+	}
+
+	created := domain.NewGameService().CreateGame(domain.NewPlayer("1", "alice", ""))
+	if err := repo.SaveAll(created, stale); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("save all: got %v, want ErrConcurrentModification", err)
+	}
+	if _, err := repo.FindByID(created.ID); !errors.Is(err, ErrGameNotFound) {
+		t.Fatalf("game saved with a stale one: %v", err)
+	}
+
+	if err := repo.SaveAll(created, game); err != nil {
+		t.Fatalf("save all: %v", err)
+	}
+	if _, err := repo.FindByID(created.ID); err != nil {
+		t.Fatalf("load saved game: %v", err)
+	}
+}
+
+func TestMemoryGameRepositorySaveAllIsAtomic(t *testing.T) {
+	testSaveAllIsAtomic(t, NewMemoryGameRepository())
+}
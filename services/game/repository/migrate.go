@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+)
+
+// legacyGameIDPrefix prefixes the integer keys of games created before games had domain IDs
+const legacyGameIDPrefix = "game_"
+
+// backfillBatchSize is the number of games given a stream per transaction by BackfillGameStreams
+const backfillBatchSize = 100
+
+// MigrateGameIDs converts a games table keyed by an auto-increment integer into one keyed
+// by the domain game ID: existing games get their key with legacyGameIDPrefix as ID
+// and start a rematch series of their own. It must run before AutoMigrate
+// and does nothing on new or converted tables; BackfillGameStreams then gives them event streams.
+func MigrateGameIDs(db *gorm.DB) error {
+	if !db.Migrator().HasTable("games") {
+		return nil
+	}
+
+	var idType string
+	err := db.Raw(`SELECT data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'games' AND column_name = 'id'`).Scan(&idType).Error
+	if err != nil {
+		return fmt.Errorf("failed to inspect games table: %w", err)
+	}
+	if idType != "bigint" && idType != "integer" {
+		return nil
+	}
+
+	statements := []string{
+		`ALTER TABLE games ALTER COLUMN id DROP DEFAULT`,
+		`ALTER TABLE games ALTER COLUMN id TYPE varchar(64) USING '` + legacyGameIDPrefix + `' || id::text`,
+		`DROP SEQUENCE IF EXISTS games_id_seq`,
+		`ALTER TABLE games ADD COLUMN IF NOT EXISTS series_id varchar(64)`,
+		`UPDATE games SET series_id = id WHERE series_id IS NULL OR series_id = ''`,
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to migrate game IDs: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// BackfillGameStreams starts an event stream for every game of the read model without one,
+// such as those converted by MigrateGameIDs, with a snapshot of the game at its current version.
+// It must run after AutoMigrate and does nothing once every game has a stream.
+func BackfillGameStreams(db *gorm.DB) error {
+	for {
+		var rows []models.Game
+		err := db.Where("NOT EXISTS (SELECT 1 FROM game_streams WHERE game_streams.stream_id = games.id)").
+			Order("id").
+			Limit(backfillBatchSize).
+			Find(&rows).Error
+		if err != nil {
+			return fmt.Errorf("failed to load games without stream: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			for i := range rows {
+				game, err := fromModel(&rows[i])
+				if err != nil {
+					return err
+				}
+				if err := adoptGame(tx, game); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to backfill game streams: %w", err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/config"
+	"github.com/your-org/go-tic-tac-toe/pkg/database"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+)
+
+// baselineGame has the shape of the games table before games had domain IDs
+type baselineGame struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	Player1ID   uint           `gorm:"not null"`
+	Player2ID   *uint
+	Status      string `gorm:"default:'waiting'"`
+	WinnerID    *uint
+	Board       string `gorm:"default:'---------'"`
+	CurrentTurn uint   `gorm:"default:1"`
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
+
+func (baselineGame) TableName() string {
+	return "games"
+}
+
+// testDatabase connects to the database named by TEST_DB_NAME with the other DB_* settings,
+// skipping the test without one. Tests drop and create tables in it.
+func testDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" {
+		t.Skip("TEST_DB_NAME is not set")
+	}
+
+	cfg := config.Load().Database
+	cfg.Name = name
+	db, err := database.Connect(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMigrateGameIDsFromBaseline(t *testing.T) {
+	db := testDatabase(t)
+	if err := db.Migrator().DropTable(eventStoreTables...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Migrator().DropTable(eventStoreTables...) })
+
+	if err := db.AutoMigrate(&baselineGame{}); err != nil {
+		t.Fatal(err)
+	}
+	legacy := []baselineGame{{Player1ID: 1, Status: "finished"}, {Player1ID: 2, Status: "waiting"}}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MigrateGameIDs(db); err != nil {
+		t.Fatal(err)
+	}
+	migrateEventStore(t, db)
+	if err := BackfillGameStreams(db); err != nil {
+		t.Fatal(err)
+	}
+	// Converted tables and backfilled streams are left alone
+	if err := MigrateGameIDs(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := BackfillGameStreams(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var games []models.Game
+	if err := db.Order("id").Find(&games).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("%d games after the migration, want 2", len(games))
+	}
+	for i, game := range games {
+		want := legacyGameIDPrefix + []string{"1", "2"}[i]
+		if game.ID != want || game.SeriesID != want || game.Player1ID != legacy[i].Player1ID {
+			t.Errorf("game %d migrated to ID %q in series %q of player %d", i+1, game.ID, game.SeriesID, game.Player1ID)
+		}
+	}
+
+	repo := NewEventStoreGameRepository(db)
+	for _, want := range legacy {
+		id := fmt.Sprintf("%s%d", legacyGameIDPrefix, want.ID)
+		game, err := repo.FindByID(id)
+		if err != nil {
+			t.Fatalf("loading migrated game %s from the event store: %v", id, err)
+		}
+		if len(game.Players) != 1 || game.Players[0].ID != fmt.Sprint(want.Player1ID) || string(game.Status) != want.Status {
+			t.Errorf("migrated game %s loaded with %d players in status %s", id, len(game.Players), game.Status)
+		}
+	}
+	var streams int64
+	if err := db.Model(&models.GameStream{}).Count(&streams).Error; err != nil {
+		t.Fatal(err)
+	}
+	if streams != 2 {
+		t.Errorf("%d streams after the backfill, want 2", streams)
+	}
+
+	created := models.Game{ID: "01HZX3E4V8Y5K2N7Q9R1T6W0AB", SeriesID: "01HZX3E4V8Y5K2N7Q9R1T6W0AB", Player1ID: 3}
+	if err := db.Create(&created).Error; err != nil {
+		t.Fatalf("creating a game with a domain ID: %v", err)
+	}
+}
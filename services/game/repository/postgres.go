@@ -0,0 +1,463 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/outbox"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"game-service/domain"
+)
+
+// gameAggregateType is the outbox aggregate type of game events
+const gameAggregateType = "game"
+
+// PostgresGameRepository stores games in Postgres and writes their
+// pending domain events to the outbox in the same transaction
+type PostgresGameRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresGameRepository creates a Postgres-backed repository
+func NewPostgresGameRepository(db *gorm.DB) *PostgresGameRepository {
+	return &PostgresGameRepository{db: db}
+}
+
+// Save upserts the game and enqueues its pending events
+func (r *PostgresGameRepository) Save(game *domain.Game) error {
+	return r.SaveAll(game)
+}
+
+// SaveAll upserts the games and enqueues their pending events in one transaction
+func (r *PostgresGameRepository) SaveAll(games ...*domain.Game) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, game := range games {
+			if err := upsertGame(tx, game); err != nil {
+				return err
+			}
+			if err := enqueueEvents(tx, game); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindByID returns the game with the given ID
+func (r *PostgresGameRepository) FindByID(id string) (*domain.Game, error) {
+	var model models.Game
+	err := r.db.Where("id = ?", id).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrGameNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game: %w", err)
+	}
+
+	return fromModel(&model)
+}
+
+// FindAll returns all stored games
+func (r *PostgresGameRepository) FindAll() ([]*domain.Game, error) {
+	return r.find(r.db.Order("created_at, id"))
+}
+
+// FindBySeriesID returns all games of a rematch series
+func (r *PostgresGameRepository) FindBySeriesID(seriesID string) ([]*domain.Game, error) {
+	return r.find(r.db.Where("series_id = ?", seriesID).Order("created_at, id"))
+}
+
+// FindActive returns the games in progress
+func (r *PostgresGameRepository) FindActive() ([]*domain.Game, error) {
+	return r.find(r.db.Where("status = ?", string(domain.GameStatusActive)).Order("created_at, id"))
+}
+
+// FindInvitations returns the waiting games with a seat reserved for the user, who has not taken it yet
+func (r *PostgresGameRepository) FindInvitations(userID string) ([]*domain.Game, error) {
+	return r.find(r.db.
+		Where("status = ?", string(domain.GameStatusWaiting)).
+		Where("? = ANY(string_to_array(invited, ','))", userID).
+		Where("NOT ? = ANY(string_to_array(player_ids, ','))", userID).
+		Order("created_at, id"))
+}
+
+// FindFinished returns up to limit finished games after the cursor, ordered by finish time and ID
+func (r *PostgresGameRepository) FindFinished(after FinishedCursor, limit int) ([]*domain.Game, error) {
+	query := r.db.Where("status = ? AND finished_at IS NOT NULL", string(domain.GameStatusFinished))
+	if !after.FinishedAt.IsZero() {
+		query = query.Where("(finished_at, id) > (?, ?)", after.FinishedAt, after.ID)
+	}
+	return r.find(query.Order("finished_at, id").Limit(limit))
+}
+
+// Delete soft-deletes the game with the given ID
+func (r *PostgresGameRepository) Delete(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&models.Game{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete game: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrGameNotFound
+	}
+	return nil
+}
+
+// find loads games matching query
+func (r *PostgresGameRepository) find(query *gorm.DB) ([]*domain.Game, error) {
+	var rows []models.Game
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load games: %w", err)
+	}
+
+	games := make([]*domain.Game, 0, len(rows))
+	for i := range rows {
+		game, err := fromModel(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// upsertGame writes the current state of the game to its row.
+// The row is only updated if it still has the version the game was loaded at.
+func upsertGame(tx *gorm.DB, game *domain.Game) error {
+	model, err := toModel(game)
+	if err != nil {
+		return err
+	}
+
+	var existing models.Game
+	err = tx.Select("id", "created_at").Where("id = ?", game.ID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create game: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrConcurrentModification
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load game: %w", err)
+	}
+
+	model.CreatedAt = existing.CreatedAt
+
+	result := tx.Model(model).
+		Where("version = ?", game.PersistedVersion()).
+		Select("*").
+		Omit("created_at").
+		Updates(model)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save game: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+// enqueueEvents writes pending events of the game to the outbox
+func enqueueEvents(tx *gorm.DB, game *domain.Game) error {
+	var messages []*outbox.Message
+	finished := false
+	for _, event := range game.PendingEvents() {
+		message, err := outbox.NewMessage(gameAggregateType, event.AggregateID(), event.EventName(), event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+
+		switch event.(type) {
+		case domain.GameWon, domain.GameDrawn, domain.GameAbandoned:
+			finished = true
+		}
+	}
+
+	// Consumers of finished games get the whole outcome rather than rebuilding it from the stream
+	if finished {
+		message, err := outbox.NewMessage(gameAggregateType, game.ID, domain.GameFinishedEvent, game.Summary())
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+
+	return outbox.Enqueue(tx, messages...)
+}
+
+// toModel maps a game aggregate to its database row
+func toModel(game *domain.Game) (*models.Game, error) {
+	model := &models.Game{
+		ID:                game.ID,
+		CreatedAt:         game.CreatedAt,
+		Status:            string(game.Status),
+		FinishReason:      string(game.FinishReason),
+		Board:             boardToString(game.Board),
+		CurrentTurn:       1,
+		StartedAt:         game.StartedAt,
+		FinishedAt:        game.FinishedAt,
+		TurnStartedAt:     game.TurnStartedAt,
+		BestOf:            game.Settings.BestOf,
+		Variant:           string(game.Settings.Variant),
+		BoardSize:         game.Settings.BoardSize,
+		WinLength:         game.Settings.WinLength,
+		Gravity:           game.Settings.Gravity,
+		PlayerCount:       game.Settings.PlayerCount(),
+		TakebackLimit:     game.Settings.TakebackLimit,
+		AI:                string(game.Settings.AI),
+		Eliminated:        strings.Join(game.Eliminated, ","),
+		Invited:           strings.Join(game.Settings.Invited, ","),
+		SeriesID:          game.SeriesID,
+		PreviousGameID:    game.PreviousGameID,
+		NextGameID:        game.NextGameID,
+		RematchAcceptedBy: strings.Join(game.RematchAcceptedBy, ","),
+		Version:           game.Version,
+	}
+
+	ids := make([]string, 0, len(game.Players))
+	symbols := make([]string, 0, len(game.Players))
+	for seat, player := range game.Players {
+		id, err := parseUserID(player.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch seat {
+		case 0:
+			model.Player1ID = id
+		case 1:
+			model.Player2ID = &id
+		}
+		if game.CurrentTurn != nil && game.CurrentTurn.ID == player.ID {
+			model.CurrentTurn = uint(seat + 1)
+		}
+
+		ids = append(ids, player.ID)
+		symbols = append(symbols, player.Symbol)
+	}
+	model.PlayerIDs = strings.Join(ids, ",")
+	model.Symbols = strings.Join(symbols, ",")
+
+	if game.LastMove != nil {
+		position := game.LastMove.Position
+		model.LastMove = &position
+	}
+
+	moves, err := json.Marshal(game.Moves)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode moves: %w", err)
+	}
+	model.Moves = string(moves)
+
+	takebacksUsed, err := json.Marshal(game.TakebacksUsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode takebacks: %w", err)
+	}
+	model.TakebacksUsed = string(takebacksUsed)
+
+	if model.WinnerID, err = parseOptionalUserID(game.Winner); err != nil {
+		return nil, err
+	}
+	if model.DrawOfferedByID, err = parseOptionalUserID(game.DrawOfferedBy); err != nil {
+		return nil, err
+	}
+	if model.TakebackRequestedByID, err = parseOptionalUserID(game.TakebackRequestedBy); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// fromModel rebuilds a game aggregate from its database row
+func fromModel(model *models.Game) (*domain.Game, error) {
+	settings := domain.GameSettings{
+		BestOf:        model.BestOf,
+		Variant:       domain.Variant(model.Variant),
+		BoardSize:     model.BoardSize,
+		WinLength:     model.WinLength,
+		Gravity:       model.Gravity,
+		TakebackLimit: model.TakebackLimit,
+		AI:            domain.AIDifficulty(model.AI),
+	}
+	if model.PlayerCount > 2 {
+		settings.Players = model.PlayerCount
+	}
+	if model.Invited != "" {
+		settings.Invited = strings.Split(model.Invited, ",")
+	}
+
+	board, err := boardFromString(model.Board, settings.Rules().NewBoard())
+	if err != nil {
+		return nil, fmt.Errorf("game %s: %w", model.ID, err)
+	}
+
+	game := &domain.Game{
+		ID:             model.ID,
+		Board:          board,
+		Status:         domain.GameStatus(model.Status),
+		CreatedAt:      model.CreatedAt,
+		StartedAt:      model.StartedAt,
+		FinishedAt:     model.FinishedAt,
+		TurnStartedAt:  model.TurnStartedAt,
+		FinishReason:   domain.FinishReason(model.FinishReason),
+		Settings:       settings,
+		SeriesID:       model.SeriesID,
+		PreviousGameID: model.PreviousGameID,
+		NextGameID:     model.NextGameID,
+		Version:        model.Version,
+	}
+
+	if game.Players, err = restorePlayers(model); err != nil {
+		return nil, fmt.Errorf("game %s: %w", model.ID, err)
+	}
+
+	if game.Status == domain.GameStatusActive {
+		seat := int(model.CurrentTurn) - 1
+		if seat < 0 || seat >= len(game.Players) {
+			seat = 0
+		}
+		game.CurrentTurn = game.Players[seat]
+	}
+
+	if model.Eliminated != "" {
+		game.Eliminated = strings.Split(model.Eliminated, ",")
+	}
+
+	if model.Moves != "" {
+		if err := json.Unmarshal([]byte(model.Moves), &game.Moves); err != nil {
+			return nil, fmt.Errorf("game %s: invalid moves: %w", model.ID, err)
+		}
+	}
+	if model.TakebacksUsed != "" && model.TakebacksUsed != "null" {
+		if err := json.Unmarshal([]byte(model.TakebacksUsed), &game.TakebacksUsed); err != nil {
+			return nil, fmt.Errorf("game %s: invalid takebacks: %w", model.ID, err)
+		}
+	}
+
+	switch {
+	case len(game.Moves) > 0:
+		last := game.Moves[len(game.Moves)-1]
+		game.LastMove = &last
+	case model.LastMove != nil:
+		// Rows stored before the move history only know the last position
+		game.LastMove = &domain.Move{
+			Position: *model.LastMove,
+			Symbol:   board.CellAt(*model.LastMove),
+		}
+	}
+
+	if model.WinnerID != nil {
+		game.Winner = game.PlayerByID(formatUserID(*model.WinnerID))
+	}
+	if model.DrawOfferedByID != nil {
+		game.DrawOfferedBy = game.PlayerByID(formatUserID(*model.DrawOfferedByID))
+	}
+	if model.TakebackRequestedByID != nil {
+		game.TakebackRequestedBy = game.PlayerByID(formatUserID(*model.TakebackRequestedByID))
+	}
+	if model.RematchAcceptedBy != "" {
+		game.RematchAcceptedBy = strings.Split(model.RematchAcceptedBy, ",")
+	}
+
+	return game, nil
+}
+
+// restorePlayers creates the players of a stored game in seat order.
+// Rows stored before games had a player list only have two seats playing X and O.
+func restorePlayers(model *models.Game) ([]*domain.Player, error) {
+	if model.PlayerIDs == "" {
+		players := []*domain.Player{restorePlayer(formatUserID(model.Player1ID), "X")}
+		if model.Player2ID != nil {
+			players = append(players, restorePlayer(formatUserID(*model.Player2ID), "O"))
+		}
+		return players, nil
+	}
+
+	ids := strings.Split(model.PlayerIDs, ",")
+	symbols := strings.Split(model.Symbols, ",")
+	if len(ids) != len(symbols) {
+		return nil, errors.New("players and symbols do not match")
+	}
+
+	players := make([]*domain.Player, 0, len(ids))
+	for i, id := range ids {
+		players = append(players, restorePlayer(id, symbols[i]))
+	}
+	return players, nil
+}
+
+// boardToString encodes the board row by row as one symbol per cell and - for empty cells
+func boardToString(board *domain.Board) string {
+	var sb strings.Builder
+	for _, row := range board.GetState() {
+		for _, cell := range row {
+			if cell == "" {
+				cell = "-"
+			}
+			sb.WriteString(cell)
+		}
+	}
+	return sb.String()
+}
+
+// boardFromString fills an empty board with cells encoded by boardToString
+func boardFromString(encoded string, board *domain.Board) (*domain.Board, error) {
+	cells := []rune(encoded)
+	if len(cells) != board.Size()*board.Size() {
+		return nil, fmt.Errorf("board must have %d cells", board.Size()*board.Size())
+	}
+
+	for position, cell := range cells {
+		if cell == '-' {
+			continue
+		}
+		if err := board.MakeMove(position, string(cell)); err != nil {
+			return nil, err
+		}
+	}
+	return board, nil
+}
+
+// restorePlayer creates a player of a stored game
+func restorePlayer(userID string, symbol string) *domain.Player {
+	player := domain.NewPlayer(userID, "", "")
+	player.AssignSymbol(symbol)
+	return player
+}
+
+// parseOptionalUserID converts the ID of a possibly absent player
+func parseOptionalUserID(player *domain.Player) (*uint, error) {
+	if player == nil {
+		return nil, nil
+	}
+
+	id, err := parseUserID(player.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// parseUserID converts a player ID to the user table key
+func parseUserID(id string) (uint, error) {
+	value, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID %q", id)
+	}
+	return uint(value), nil
+}
+
+// formatUserID converts a user table key to a player ID
+func formatUserID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
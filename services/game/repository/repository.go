@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"game-service/domain"
+)
+
+var (
+	// ErrGameNotFound is returned when a game does not exist
+	ErrGameNotFound = errors.New("game not found")
+
+	// ErrConcurrentModification is returned when the game was changed since it was loaded
+	ErrConcurrentModification = errors.New("game was modified concurrently")
+
+	// ErrTournamentNotFound is returned when a tournament does not exist
+	ErrTournamentNotFound = errors.New("tournament not found")
+)
+
+// GameRepository stores game aggregates
+type GameRepository interface {
+	Save(game *domain.Game) error
+	// SaveAll saves the games in one transaction, none of them if saving one fails
+	SaveAll(games ...*domain.Game) error
+	FindByID(id string) (*domain.Game, error)
+	FindAll() ([]*domain.Game, error)
+	FindBySeriesID(seriesID string) ([]*domain.Game, error)
+	// FindActive returns the games in progress
+	FindActive() ([]*domain.Game, error)
+	// FindInvitations returns the waiting games with a seat reserved for the user, who has not taken it yet
+	FindInvitations(userID string) ([]*domain.Game, error)
+	// FindFinished returns up to limit finished games after the cursor, ordered by finish time and ID
+	FindFinished(after FinishedCursor, limit int) ([]*domain.Game, error)
+	// Delete removes a game, ErrGameNotFound if it does not exist
+	Delete(id string) error
+}
+
+// FinishedCursor - finish time and ID of the last finished game of a page.
+// The zero cursor starts at the first finished game.
+type FinishedCursor struct {
+	FinishedAt time.Time
+	ID         string
+}
+
+// TournamentRepository stores tournament aggregates.
+// Save fails with ErrConcurrentModification if the tournament was saved since it was loaded.
+type TournamentRepository interface {
+	Save(tournament *domain.Tournament) error
+	FindByID(id string) (*domain.Tournament, error)
+	FindAll() ([]*domain.Tournament, error)
+	// FindByGameID returns the tournament the game was paired in
+	FindByGameID(gameID string) (*domain.Tournament, error)
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"game-service/domain"
+)
+
+// MemoryTournamentRepository keeps tournaments in process memory.
+// Tournaments are stored encoded so that callers never share an aggregate.
+type MemoryTournamentRepository struct {
+	tournaments map[string][]byte
+	games       map[string]string // tournament IDs by game ID
+	mu          sync.RWMutex
+}
+
+// NewMemoryTournamentRepository creates an empty in-memory repository
+func NewMemoryTournamentRepository() *MemoryTournamentRepository {
+	return &MemoryTournamentRepository{
+		tournaments: make(map[string][]byte),
+		games:       make(map[string]string),
+	}
+}
+
+// Save stores the tournament if nobody saved it since it was loaded
+func (r *MemoryTournamentRepository) Save(tournament *domain.Tournament) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	version := 0
+	if stored, ok := r.tournaments[tournament.ID]; ok {
+		existing, err := decodeTournament(stored)
+		if err != nil {
+			return err
+		}
+		version = existing.Version
+	}
+	if version != tournament.Version {
+		return ErrConcurrentModification
+	}
+
+	data, err := encodeTournament(tournament, version+1)
+	if err != nil {
+		return err
+	}
+	r.tournaments[tournament.ID] = data
+	for _, gameID := range tournament.GameIDs() {
+		r.games[gameID] = tournament.ID
+	}
+
+	tournament.Version = version + 1
+	return nil
+}
+
+// FindByID returns the tournament with the given ID
+func (r *MemoryTournamentRepository) FindByID(id string) (*domain.Tournament, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data, ok := r.tournaments[id]
+	if !ok {
+		return nil, ErrTournamentNotFound
+	}
+	return decodeTournament(data)
+}
+
+// FindAll returns all stored tournaments in creation order, as the Postgres repository does
+func (r *MemoryTournamentRepository) FindAll() ([]*domain.Tournament, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tournaments := make([]*domain.Tournament, 0, len(r.tournaments))
+	for _, data := range r.tournaments {
+		tournament, err := decodeTournament(data)
+		if err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, tournament)
+	}
+
+	sort.Slice(tournaments, func(i, j int) bool {
+		if !tournaments[i].CreatedAt.Equal(tournaments[j].CreatedAt) {
+			return tournaments[i].CreatedAt.Before(tournaments[j].CreatedAt)
+		}
+		return tournaments[i].ID < tournaments[j].ID
+	})
+	return tournaments, nil
+}
+
+// FindByGameID returns the tournament the game was paired in
+func (r *MemoryTournamentRepository) FindByGameID(gameID string) (*domain.Tournament, error) {
+	r.mu.RLock()
+	id, ok := r.games[gameID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrTournamentNotFound
+	}
+	return r.FindByID(id)
+}
+
+// PostgresTournamentRepository stores tournaments in Postgres as JSON documents,
+// with a lookup table from their games
+type PostgresTournamentRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresTournamentRepository creates a Postgres-backed repository
+func NewPostgresTournamentRepository(db *gorm.DB) *PostgresTournamentRepository {
+	return &PostgresTournamentRepository{db: db}
+}
+
+// Save stores the tournament if nobody saved it since it was loaded
+func (r *PostgresTournamentRepository) Save(tournament *domain.Tournament) error {
+	version := tournament.Version + 1
+	data, err := encodeTournament(tournament, version)
+	if err != nil {
+		return err
+	}
+
+	model := models.Tournament{
+		ID:        tournament.ID,
+		CreatedAt: tournament.CreatedAt,
+		Name:      tournament.Name,
+		Format:    string(tournament.Format),
+		Status:    string(tournament.Status),
+		Version:   version,
+		State:     string(data),
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		if tournament.Version == 0 {
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		} else {
+			result = tx.Model(&models.Tournament{}).
+				Where("id = ? AND version = ?", tournament.ID, tournament.Version).
+				Updates(map[string]interface{}{
+					"name":    model.Name,
+					"status":  model.Status,
+					"version": model.Version,
+					"state":   model.State,
+				})
+		}
+		if result.Error != nil {
+			return fmt.Errorf("failed to save tournament: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrConcurrentModification
+		}
+
+		gameIDs := tournament.GameIDs()
+		if len(gameIDs) == 0 {
+			return nil
+		}
+		links := make([]models.TournamentGame, 0, len(gameIDs))
+		for _, gameID := range gameIDs {
+			links = append(links, models.TournamentGame{GameID: gameID, TournamentID: tournament.ID})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error; err != nil {
+			return fmt.Errorf("failed to save tournament games: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tournament.Version = version
+	return nil
+}
+
+// FindByID returns the tournament with the given ID
+func (r *PostgresTournamentRepository) FindByID(id string) (*domain.Tournament, error) {
+	var model models.Tournament
+	err := r.db.Where("id = ?", id).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTournamentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tournament: %w", err)
+	}
+	return decodeTournament([]byte(model.State))
+}
+
+// FindAll returns all stored tournaments in creation order
+func (r *PostgresTournamentRepository) FindAll() ([]*domain.Tournament, error) {
+	var rows []models.Tournament
+	if err := r.db.Order("created_at, id").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load tournaments: %w", err)
+	}
+
+	tournaments := make([]*domain.Tournament, 0, len(rows))
+	for _, row := range rows {
+		tournament, err := decodeTournament([]byte(row.State))
+		if err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, tournament)
+	}
+	return tournaments, nil
+}
+
+// FindByGameID returns the tournament the game was paired in
+func (r *PostgresTournamentRepository) FindByGameID(gameID string) (*domain.Tournament, error) {
+	var link models.TournamentGame
+	err := r.db.Where("game_id = ?", gameID).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTournamentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tournament game: %w", err)
+	}
+	return r.FindByID(link.TournamentID)
+}
+
+// encodeTournament encodes the tournament as it is stored at version
+func encodeTournament(tournament *domain.Tournament, version int) ([]byte, error) {
+	stored := *tournament
+	stored.Version = version
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tournament: %w", err)
+	}
+	return data, nil
+}
+
+// decodeTournament restores a stored tournament
+func decodeTournament(data []byte) (*domain.Tournament, error) {
+	var tournament domain.Tournament
+	if err := json.Unmarshal(data, &tournament); err != nil {
+		return nil, fmt.Errorf("failed to decode tournament: %w", err)
+	}
+	return &tournament, nil
+}
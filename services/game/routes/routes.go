@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	"game-service/handlers"
+)
+
+// Setup registers game service routes
+func Setup(app *fiber.App, gameHandler *handlers.GameHandler, tournamentHandler *handlers.TournamentHandler, jwtSecret, serviceSecret string) {
+	// Browsers cannot set headers on WebSockets, so the replay stream also takes the token as a
+	// query parameter. It is registered ahead of the group to run before the group's Auth.
+	app.Get("/games/:id/replay/stream", middleware.WebSocketAuth(jwtSecret), gameHandler.PrepareReplayStream, websocket.New(gameHandler.StreamReplay))
+
+	games := app.Group("/games", middleware.Auth(jwtSecret))
+	games.Post("/", gameHandler.CreateGame)
+	games.Post("/import", gameHandler.ImportGame)
+	games.Get("/invitations", gameHandler.Invitations)
+	games.Get("/:id", gameHandler.GetGame)
+	games.Delete("/:id", middleware.RequirePermission(middleware.PermissionDeleteGames), gameHandler.DeleteGame)
+	games.Get("/:id/stats", gameHandler.GetStatistics)
+	games.Post("/:id/end", middleware.RequirePermission(middleware.PermissionEndGames), gameHandler.EndGame)
+	games.Post("/:id/join", gameHandler.JoinGame)
+	games.Post("/:id/move", gameHandler.MakeMove)
+	games.Post("/:id/resign", gameHandler.Resign)
+	games.Post("/:id/draw/offer", gameHandler.OfferDraw)
+	games.Post("/:id/draw/accept", gameHandler.AcceptDraw)
+	games.Post("/:id/draw/decline", gameHandler.DeclineDraw)
+	games.Post("/:id/takeback", gameHandler.RequestTakeback)
+	games.Post("/:id/takeback/accept", gameHandler.AcceptTakeback)
+	games.Post("/:id/takeback/decline", gameHandler.DeclineTakeback)
+	games.Post("/:id/rematch", gameHandler.Rematch)
+	games.Get("/:id/series", gameHandler.GetSeries)
+	games.Get("/:id/analysis", gameHandler.AnnotateGame)
+	games.Get("/:id/export", gameHandler.ExportGame)
+	games.Get("/:id/replay", gameHandler.GetReplay)
+
+	app.Post("/analysis", middleware.Auth(jwtSecret), gameHandler.Analyze)
+
+	tournaments := app.Group("/tournaments", middleware.Auth(jwtSecret))
+	tournaments.Post("/", tournamentHandler.CreateTournament)
+	tournaments.Get("/", tournamentHandler.ListTournaments)
+	tournaments.Get("/:id", tournamentHandler.GetTournament)
+	tournaments.Post("/:id/register", tournamentHandler.Register)
+	tournaments.Delete("/:id/register", tournamentHandler.Withdraw)
+	tournaments.Post("/:id/start", tournamentHandler.Start)
+	tournaments.Get("/:id/standings", tournamentHandler.GetStandings)
+	tournaments.Get("/:id/bracket", tournamentHandler.GetBracket)
+
+	// Service-to-service endpoints, authenticated with the shared service secret
+	internal := app.Group("/internal", middleware.ServiceAuth(serviceSecret))
+	internal.Get("/games/finished", gameHandler.FinishedGames)
+	internal.Get("/games/active/seats", gameHandler.ActiveSeats)
+	internal.Post("/games/:id/abandon", gameHandler.Abandon)
+	internal.Post("/games/:id/takeback/:action", gameHandler.Takeback)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	"game-service/handlers"
+)
+
+// userServiceBlockChecker checks blocks between players with the user service
+func userServiceBlockChecker(userServiceURL, serviceSecret string) handlers.BlockChecker {
+	client := middleware.ServiceClient(serviceSecret, 5*time.Second)
+
+	return func(userID string, otherIDs []string) (bool, error) {
+		if len(otherIDs) == 0 {
+			return false, nil
+		}
+
+		resp, err := client.Get(fmt.Sprintf("%s/internal/users/%s/blocks", userServiceURL, userID))
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return false, fmt.Errorf("user service rejected request: %s", resp.Status)
+		}
+
+		var result struct {
+			Data struct {
+				UserIDs []string `json:"user_ids"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return false, fmt.Errorf("failed to decode blocks: %w", err)
+		}
+
+		for _, blockedID := range result.Data.UserIDs {
+			for _, otherID := range otherIDs {
+				if blockedID == otherID {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+}
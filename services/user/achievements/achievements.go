@@ -0,0 +1,133 @@
+package achievements
+
+import (
+	"user-service/stats"
+)
+
+// ChatMessageSentEvent is the event type of chat messages reported by the chat service
+const ChatMessageSentEvent = "chat.message_sent"
+
+// Definition describes a badge
+type Definition struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Progress is what rules are evaluated against after an event of a user
+type Progress struct {
+	// Game is the finished game the event is about, nil for other events
+	Game *stats.GameSummary
+	// PlayerID is the ID of the user in Game
+	PlayerID string
+	// Stats cover all recorded games of the user, including Game
+	Stats stats.Stats
+	// ChatMessages is the number of chat messages the user sent
+	ChatMessages int
+}
+
+// Rule unlocks a badge once its condition holds
+type Rule struct {
+	Definition
+	unlocked func(p Progress) bool
+}
+
+// Rules are all achievements in the order they are listed
+var Rules = []Rule{
+	{
+		Definition: Definition{Key: "first_win", Name: "First Win", Description: "Win a game"},
+		unlocked: func(p Progress) bool {
+			return p.Stats.Wins >= 1
+		},
+	},
+	{
+		Definition: Definition{Key: "quick_win", Name: "Quick Win", Description: "Win a game in 3 moves"},
+		unlocked: func(p Progress) bool {
+			// Wins by resignation or abandonment do not count, however few moves were made
+			return p.won() && p.Game.Reason == stats.ReasonWinLine && playerMoves(p.Game, p.PlayerID) <= 3
+		},
+	},
+	{
+		Definition: Definition{Key: "win_streak_10", Name: "Unstoppable", Description: "Win 10 games in a row"},
+		unlocked: func(p Progress) bool {
+			return p.Stats.LongestWinStreak >= 10
+		},
+	},
+	{
+		Definition: Definition{Key: "beat_hard_ai", Name: "Machine Breaker", Description: "Beat the hard AI"},
+		unlocked: func(p Progress) bool {
+			return p.won() && p.Game.AI == stats.AIHard
+		},
+	},
+	{
+		Definition: Definition{Key: "veteran", Name: "Veteran", Description: "Play 100 games"},
+		unlocked: func(p Progress) bool {
+			return p.Stats.Played >= 100
+		},
+	},
+	{
+		Definition: Definition{Key: "chatterbox", Name: "Chatterbox", Description: "Send 100 chat messages"},
+		unlocked: func(p Progress) bool {
+			return p.ChatMessages >= 100
+		},
+	},
+}
+
+// Unlocked returns the definitions of all rules whose condition holds for p,
+// including those the user earned before
+func Unlocked(p Progress) []Definition {
+	var unlocked []Definition
+	for _, rule := range Rules {
+		if rule.unlocked(p) {
+			unlocked = append(unlocked, rule.Definition)
+		}
+	}
+	return unlocked
+}
+
+// Lookup returns the definition of the badge with the given key
+func Lookup(key string) (Definition, bool) {
+	for _, rule := range Rules {
+		if rule.Key == key {
+			return rule.Definition, true
+		}
+	}
+	return Definition{}, false
+}
+
+// won reports whether the user won the game of the event
+func (p Progress) won() bool {
+	if p.Game == nil {
+		return false
+	}
+	for _, player := range p.Game.Players {
+		if player.ID == p.PlayerID {
+			return player.Result == stats.ResultWin
+		}
+	}
+	return false
+}
+
+// playerMoves returns the moves the player made in the game, assuming turns pass
+// in seat order starting with the first player
+func playerMoves(game *stats.GameSummary, playerID string) int {
+	seat, first := -1, 0
+	for i, player := range game.Players {
+		if player.ID == playerID {
+			seat = i
+		}
+		if player.ID == game.FirstPlayerID {
+			first = i
+		}
+	}
+	if seat < 0 {
+		return 0
+	}
+
+	players := len(game.Players)
+	offset := (seat - first + players) % players
+	if game.Moves <= offset {
+		return 0
+	}
+	return (game.Moves-offset-1)/players + 1
+}
@@ -0,0 +1,150 @@
+package achievements
+
+import (
+	"slices"
+	"testing"
+
+	"user-service/stats"
+)
+
+// game returns a summary of a game won by winnerID, with players seated in the order of ids
+func game(moves int, reason, firstID, winnerID string, ids ...string) *stats.GameSummary {
+	summary := &stats.GameSummary{GameID: "g1", Moves: moves, Reason: reason, FirstPlayerID: firstID, WinnerID: winnerID}
+	for _, id := range ids {
+		result := stats.ResultLoss
+		if id == winnerID {
+			result = stats.ResultWin
+		}
+		summary.Players = append(summary.Players, stats.PlayerSummary{ID: id, Result: result})
+	}
+	return summary
+}
+
+// againstAI marks the game as played against the AI of the given difficulty
+func againstAI(summary *stats.GameSummary, difficulty string) *stats.GameSummary {
+	summary.AI = difficulty
+	return summary
+}
+
+func keys(definitions []Definition) []string {
+	var keys []string
+	for _, definition := range definitions {
+		keys = append(keys, definition.Key)
+	}
+	return keys
+}
+
+func TestUnlocked(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress Progress
+		want     []string
+	}{
+		{"nothing yet", Progress{}, nil},
+		{"first win", Progress{Stats: stats.Stats{Record: stats.Record{Played: 1, Wins: 1}}}, []string{"first_win"}},
+		{"nine wins in a row", Progress{Stats: stats.Stats{LongestWinStreak: 9}}, nil},
+		{"ten wins in a row", Progress{Stats: stats.Stats{LongestWinStreak: 10}}, []string{"win_streak_10"}},
+		{"99 games", Progress{Stats: stats.Stats{Record: stats.Record{Played: 99}}}, nil},
+		{"100 games", Progress{Stats: stats.Stats{Record: stats.Record{Played: 100}}}, []string{"veteran"}},
+		{"99 chat messages", Progress{ChatMessages: 99}, nil},
+		{"100 chat messages", Progress{ChatMessages: 100}, []string{"chatterbox"}},
+		{
+			name:     "line in three moves",
+			progress: Progress{Game: game(5, stats.ReasonWinLine, "1", "1", "1", "2"), PlayerID: "1"},
+			want:     []string{"quick_win"},
+		},
+		{
+			name:     "line in four moves",
+			progress: Progress{Game: game(7, stats.ReasonWinLine, "1", "1", "1", "2"), PlayerID: "1"},
+		},
+		{
+			name:     "second player with a line in three moves",
+			progress: Progress{Game: game(6, stats.ReasonWinLine, "1", "2", "1", "2"), PlayerID: "2"},
+			want:     []string{"quick_win"},
+		},
+		{
+			name:     "resignation after three moves",
+			progress: Progress{Game: game(5, "resign", "1", "1", "1", "2"), PlayerID: "1"},
+		},
+		{
+			name:     "loser of a short game",
+			progress: Progress{Game: game(5, stats.ReasonWinLine, "1", "1", "1", "2"), PlayerID: "2"},
+		},
+		{
+			// Turns go 2, 3, 1, 2, 3, 1, 2: the third seat made two of the seven moves
+			name:     "three players with a later seat starting",
+			progress: Progress{Game: game(7, stats.ReasonWinLine, "2", "1", "1", "2", "3"), PlayerID: "1"},
+			want:     []string{"quick_win"},
+		},
+		{
+			// Turns go 3, 4, 1, 2, 3, 4, 1, 2, 3, 4, 1: the first seat made three of the eleven moves
+			name:     "four players with the third seat starting",
+			progress: Progress{Game: game(11, stats.ReasonWinLine, "3", "1", "1", "2", "3", "4"), PlayerID: "1"},
+			want:     []string{"quick_win"},
+		},
+		{
+			// The starting seat made its fourth move as the thirteenth of the game
+			name:     "four players with the starter on its fourth move",
+			progress: Progress{Game: game(13, stats.ReasonWinLine, "3", "3", "1", "2", "3", "4"), PlayerID: "3"},
+		},
+		{
+			name:     "win against the hard AI",
+			progress: Progress{Game: againstAI(game(7, stats.ReasonWinLine, "1", "1", "1", "0"), stats.AIHard), PlayerID: "1"},
+			want:     []string{"beat_hard_ai"},
+		},
+		{
+			name:     "loss against the hard AI",
+			progress: Progress{Game: againstAI(game(6, stats.ReasonWinLine, "1", "0", "1", "0"), stats.AIHard), PlayerID: "1"},
+		},
+		{
+			name:     "win against the medium AI",
+			progress: Progress{Game: againstAI(game(7, stats.ReasonWinLine, "1", "1", "1", "0"), "medium"), PlayerID: "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keys(Unlocked(tt.progress)); !slices.Equal(got, tt.want) {
+				t.Errorf("unlocked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlayerMoves(t *testing.T) {
+	tests := []struct {
+		name     string
+		game     *stats.GameSummary
+		playerID string
+		want     int
+	}{
+		{"first of two", game(5, "", "1", "", "1", "2"), "1", 3},
+		{"second of two", game(5, "", "1", "", "1", "2"), "2", 2},
+		{"second seat starting", game(5, "", "2", "", "1", "2"), "1", 2},
+		{"no move yet", game(1, "", "1", "", "1", "2"), "2", 0},
+		{"last of three", game(7, "", "1", "", "1", "2", "3"), "3", 2},
+		{"first seat after a later starter", game(7, "", "2", "", "1", "2", "3"), "1", 2},
+		{"starter of four", game(9, "", "3", "", "1", "2", "3", "4"), "3", 3},
+		{"seat before the starter of four", game(9, "", "3", "", "1", "2", "3", "4"), "2", 2},
+		{"not in the game", game(9, "", "1", "", "1", "2"), "5", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := playerMoves(tt.game, tt.playerID); got != tt.want {
+				t.Errorf("%d moves, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, rule := range Rules {
+		if definition, ok := Lookup(rule.Key); !ok || definition != rule.Definition {
+			t.Errorf("lookup of %s returned %+v", rule.Key, definition)
+		}
+	}
+	if _, ok := Lookup("hard_ai"); ok {
+		t.Error("found a removed badge")
+	}
+}
@@ -0,0 +1,158 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"  // registers the GIF decoder
+	_ "image/jpeg" // registers the JPEG decoder
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxUploadSize is the largest accepted upload in bytes
+	MaxUploadSize = 2 << 20
+
+	// MaxDimension bounds width and height, so that small files cannot decode to huge images
+	MaxDimension = 4096
+
+	// MinDimension is the smallest accepted width and height
+	MinDimension = 32
+
+	// OriginalSize caps the side of the stored original
+	OriginalSize = 512
+)
+
+// ThumbnailSizes are the sides of the generated square thumbnails
+var ThumbnailSizes = []int{256, 64}
+
+// ContentTypes are the accepted image formats
+var ContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+}
+
+var (
+	// ErrTooLarge is returned for uploads above MaxUploadSize
+	ErrTooLarge = fmt.Errorf("avatar must be at most %d bytes", MaxUploadSize)
+
+	// ErrUnsupportedType is returned for files that are not PNG, JPEG or GIF images
+	ErrUnsupportedType = errors.New("avatar must be a PNG, JPEG or GIF image")
+
+	// ErrBadDimensions is returned for images that are too small or too large
+	ErrBadDimensions = fmt.Errorf("avatar must be between %d and %d pixels wide and high", MinDimension, MaxDimension)
+)
+
+// Image is a square PNG rendition of an avatar
+type Image struct {
+	Name string // "original" or the thumbnail side
+	Size int
+	Data []byte
+}
+
+// Process validates an upload and renders the original and its thumbnails.
+// Images are cropped to the centered square and re-encoded as PNG,
+// which also drops any metadata of the upload.
+func Process(data []byte) ([]Image, error) {
+	if len(data) > MaxUploadSize {
+		return nil, ErrTooLarge
+	}
+	if !ContentTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width < MinDimension || config.Height < MinDimension ||
+		config.Width > MaxDimension || config.Height > MaxDimension {
+		return nil, ErrBadDimensions
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	square := crop(img)
+
+	original := square.Bounds().Dx()
+	if original > OriginalSize {
+		original = OriginalSize
+	}
+	images := []Image{{Name: "original", Size: original}}
+	for _, size := range ThumbnailSizes {
+		images = append(images, Image{Name: fmt.Sprint(size), Size: size})
+	}
+
+	for i := range images {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, scale(square, images[i].Size)); err != nil {
+			return nil, fmt.Errorf("failed to encode avatar: %w", err)
+		}
+		images[i].Data = buf.Bytes()
+	}
+	return images, nil
+}
+
+// crop copies the centered square of img into an RGBA image
+func crop(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	origin := bounds.Min.Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), img, origin, draw.Src)
+	return square
+}
+
+// scale resizes a square image to size by averaging the source pixels
+// covered by every target pixel. Upscaling repeats pixels.
+func scale(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	if size == side {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, side, size)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, side, size)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[sx*4+c])
+					}
+				}
+			}
+
+			count := (y1 - y0) * (x1 - x0)
+			offset := y*dst.Stride + x*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[offset+c] = uint8((sum[c] + count/2) / count)
+			}
+		}
+	}
+	return dst
+}
+
+// span returns the source range [from, to) covered by target index i
+func span(i, side, size int) (from, to int) {
+	from = i * side / size
+	to = (i + 1) * side / size
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
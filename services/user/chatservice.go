@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	"user-service/handlers"
+)
+
+// chatServicePresence asks the chat service which users are connected
+func chatServicePresence(chatServiceURL, serviceSecret string) handlers.PresenceChecker {
+	client := middleware.ServiceClient(serviceSecret, 2*time.Second)
+
+	return func(userIDs []uint) (map[uint]bool, error) {
+		ids := make([]string, 0, len(userIDs))
+		for _, id := range userIDs {
+			ids = append(ids, strconv.FormatUint(uint64(id), 10))
+		}
+
+		resp, err := client.Get(chatServiceURL + "/internal/presence?user_ids=" + url.QueryEscape(strings.Join(ids, ",")))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("chat service rejected request: %s", resp.Status)
+		}
+
+		var result struct {
+			Online map[string]bool `json:"online"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode presence: %w", err)
+		}
+
+		online := make(map[uint]bool, len(result.Online))
+		for id, isOnline := range result.Online {
+			if userID, err := strconv.ParseUint(id, 10, 0); err == nil {
+				online[uint(userID)] = isOnline
+			}
+		}
+		return online, nil
+	}
+}
+
+// chatServiceNotifier pushes system messages to users through the chat service
+func chatServiceNotifier(chatServiceURL, serviceSecret string) handlers.Notifier {
+	client := middleware.ServiceClient(serviceSecret, 2*time.Second)
+
+	return func(userID uint, action, content string, data interface{}) error {
+		body, err := json.Marshal(map[string]interface{}{
+			"action":  action,
+			"content": content,
+			"data":    data,
+		})
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Post(fmt.Sprintf("%s/internal/users/%d/messages", chatServiceURL, userID), "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("chat service rejected notification: %s", resp.Status)
+		}
+		return nil
+	}
+}
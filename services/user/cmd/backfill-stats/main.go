@@ -0,0 +1,112 @@
+// Command backfill-stats recomputes the game results behind player statistics
+// from the finished games stored by the game service. Results are upserted page by page,
+// so results recorded live while it runs are kept.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/config"
+	"github.com/your-org/go-tic-tac-toe/pkg/database"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+
+	"user-service/repository"
+	"user-service/stats"
+)
+
+func main() {
+	gameServiceURL := flag.String("game-service", os.Getenv("GAME_SERVICE_URL"), "base URL of the game service")
+	pageSize := flag.Int("page-size", 500, "games fetched per request")
+	dryRun := flag.Bool("dry-run", false, "fetch and count results without storing them")
+	flag.Parse()
+
+	if *gameServiceURL == "" {
+		*gameServiceURL = "http://localhost:8083"
+	}
+
+	cfg := config.Load()
+	client := middleware.ServiceClient(cfg.Service.Secret, 30*time.Second)
+
+	var repo repository.StatsRepository
+	if !*dryRun {
+		db, err := database.Connect(&cfg.Database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := database.AutoMigrate(db, &models.GameResult{}); err != nil {
+			log.Fatal(err)
+		}
+		repo = repository.NewPostgresUserRepository(db)
+	}
+
+	var games, results int
+	var after *stats.GameSummary
+	for {
+		page, err := fetchPage(client, pageURL(*gameServiceURL, after, *pageSize))
+		if err != nil {
+			log.Fatalf("Failed to fetch finished games: %v", err)
+		}
+
+		var pageResults []models.GameResult
+		for _, summary := range page {
+			pageResults = append(pageResults, stats.Results(summary)...)
+		}
+		if repo != nil {
+			if err := repo.UpsertResults(pageResults); err != nil {
+				log.Fatal(err)
+			}
+		}
+		games += len(page)
+		results += len(pageResults)
+
+		if len(page) < *pageSize {
+			break
+		}
+		after = &page[len(page)-1]
+	}
+
+	if *dryRun {
+		log.Printf("Fetched %d finished games with %d player results", games, results)
+		return
+	}
+	log.Printf("Stored %d player results of %d finished games", results, games)
+}
+
+// pageURL returns the URL of the page of finished games following the summary after, or the first page
+func pageURL(gameServiceURL string, after *stats.GameSummary, pageSize int) string {
+	query := url.Values{"limit": {fmt.Sprint(pageSize)}}
+	if after != nil && after.FinishedAt != nil {
+		query.Set("after_finished_at", after.FinishedAt.Format(time.RFC3339Nano))
+		query.Set("after_id", after.GameID)
+	}
+	return gameServiceURL + "/internal/games/finished?" + query.Encode()
+}
+
+// fetchPage fetches one page of finished game summaries
+func fetchPage(client *http.Client, endpoint string) ([]stats.GameSummary, error) {
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Data  []stats.GameSummary `json:"data"`
+		Error string              `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("game service rejected request: %s %s", resp.Status, body.Error)
+	}
+	return body.Data, nil
+}
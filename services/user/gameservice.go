@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"user-service/handlers"
+)
+
+// gameServiceCreator creates challenge games with the game service as the challenger
+func gameServiceCreator(gameServiceURL string) handlers.GameCreator {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	return func(authorization string, req handlers.ChallengeRequest) (json.RawMessage, error) {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+
+		httpReq, err := http.NewRequest(http.MethodPost, gameServiceURL+"/games/", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", authorization)
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		var result struct {
+			Data  json.RawMessage `json:"data"`
+			Error string          `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		// Invalid settings are the challenger's to fix, anything else is a failure of the game service
+		if resp.StatusCode == http.StatusBadRequest && result.Error != "" {
+			return nil, fiber.NewError(resp.StatusCode, result.Error)
+		}
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("game service rejected request: %s %s", resp.Status, result.Error)
+		}
+		return result.Data, nil
+	}
+}
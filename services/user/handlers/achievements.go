@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"user-service/achievements"
+	"user-service/repository"
+	"user-service/stats"
+)
+
+// AchievementUnlockedAction is the action of the system message announcing a new badge
+const AchievementUnlockedAction = "achievement_unlocked"
+
+// Notifier pushes a system message to the connected clients of a user
+type Notifier func(userID uint, action, content string, data interface{}) error
+
+// AchievementHandler serves earned badges and unlocks them from game and chat events
+type AchievementHandler struct {
+	users        repository.UserRepository
+	achievements repository.AchievementRepository
+	notify       Notifier
+}
+
+// NewAchievementHandler creates a new achievement handler
+func NewAchievementHandler(users repository.UserRepository, achievements repository.AchievementRepository) *AchievementHandler {
+	return &AchievementHandler{
+		users:        users,
+		achievements: achievements,
+	}
+}
+
+// SetNotifier sets how users learn about new badges. Without one, badges are unlocked silently.
+func (h *AchievementHandler) SetNotifier(notify Notifier) {
+	h.notify = notify
+}
+
+// Badge - an achievement earned by a user
+type Badge struct {
+	achievements.Definition
+	GameID     string    `json:"game_id,omitempty"`
+	UnlockedAt time.Time `json:"unlocked_at"`
+}
+
+// ChatMessageSent - payload of a chat.message_sent event of the chat service
+type ChatMessageSent struct {
+	UserID string `json:"user_id"`
+	GameID string `json:"game_id"`
+}
+
+// GetAchievements returns the badges of a user in the order they were unlocked
+func (h *AchievementHandler) GetAchievements(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	user, err := h.users.FindByID(uint(id))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	earned, err := h.achievements.FindAchievements(user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load achievements")
+	}
+
+	badges := make([]Badge, 0, len(earned))
+	for _, achievement := range earned {
+		// Badges of retired rules are no longer shown
+		if badge, ok := toBadge(achievement); ok {
+			badges = append(badges, badge)
+		}
+	}
+	return utils.SuccessResponse(c, badges, "")
+}
+
+// gameFinished unlocks with repo the badges the players of a finished game earned with it and returns
+// those they did not have before. The results of the game must already be recorded.
+func (h *AchievementHandler) gameFinished(repo repository.ConsumerRepository, summary stats.GameSummary) ([]models.Achievement, error) {
+	var unlocked []models.Achievement
+	for _, result := range stats.Results(summary) {
+		results, err := repo.FindResults(result.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		progress := achievements.Progress{
+			Game:     &summary,
+			PlayerID: strconv.FormatUint(uint64(result.UserID), 10),
+			Stats:    *stats.Compute(result.UserID, results),
+		}
+		badges, err := unlock(repo, result.UserID, summary.GameID, achievements.Unlocked(progress))
+		if err != nil {
+			return nil, err
+		}
+		unlocked = append(unlocked, badges...)
+	}
+	return unlocked, nil
+}
+
+// chatMessageSent counts a chat message with repo and unlocks the badges it earned,
+// returning those the user did not have before. Messages of players without a numeric user ID are ignored.
+func (h *AchievementHandler) chatMessageSent(repo repository.ConsumerRepository, event ChatMessageSent) ([]models.Achievement, error) {
+	userID, err := strconv.ParseUint(event.UserID, 10, 0)
+	if err != nil || userID == 0 {
+		return nil, nil
+	}
+
+	messages, err := repo.RecordChatMessage(uint(userID))
+	if err != nil {
+		return nil, err
+	}
+	return unlock(repo, uint(userID), "", achievements.Unlocked(achievements.Progress{ChatMessages: messages}))
+}
+
+// announce tells users about badges they unlocked. Badges are stored either way,
+// a user who is offline sees them on their profile.
+func (h *AchievementHandler) announce(unlocked []models.Achievement) {
+	if h.notify == nil {
+		return
+	}
+
+	for _, achievement := range unlocked {
+		badge, ok := toBadge(achievement)
+		if !ok {
+			continue
+		}
+		content := fmt.Sprintf("Achievement unlocked: %s", badge.Name)
+		if err := h.notify(achievement.UserID, AchievementUnlockedAction, content, badge); err != nil {
+			log.Printf("Failed to announce achievement %s to user %d: %v", badge.Key, achievement.UserID, err)
+		}
+	}
+}
+
+// unlock stores the badges with repo and returns those the user did not have before
+func unlock(repo repository.AchievementRepository, userID uint, gameID string, definitions []achievements.Definition) ([]models.Achievement, error) {
+	if len(definitions) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	earned := make([]models.Achievement, 0, len(definitions))
+	for _, definition := range definitions {
+		earned = append(earned, models.Achievement{
+			UserID:     userID,
+			Key:        definition.Key,
+			GameID:     gameID,
+			UnlockedAt: now,
+		})
+	}
+	return repo.UnlockAchievements(earned)
+}
+
+// toBadge returns the badge of an earned achievement, false if its rule no longer exists
+func toBadge(achievement models.Achievement) (Badge, bool) {
+	definition, ok := achievements.Lookup(achievement.Key)
+	if !ok {
+		return Badge{}, false
+	}
+	return Badge{
+		Definition: definition,
+		GameID:     achievement.GameID,
+		UnlockedAt: achievement.UnlockedAt,
+	}, true
+}
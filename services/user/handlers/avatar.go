@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"user-service/avatar"
+	"user-service/storage"
+)
+
+// avatarField is the multipart form field of avatar uploads
+const avatarField = "avatar"
+
+// UploadAvatar replaces the avatar of the current user with a multipart upload
+func (h *UserHandler) UploadAvatar(c *fiber.Ctx) error {
+	header, err := c.FormFile(avatarField)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Avatar file required in field \"avatar\"")
+	}
+	if header.Size > avatar.MaxUploadSize {
+		return utils.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, avatar.ErrTooLarge.Error())
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to read avatar")
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, avatar.MaxUploadSize+1))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to read avatar")
+	}
+
+	images, err := avatar.Process(data)
+	switch {
+	case errors.Is(err, avatar.ErrTooLarge):
+		return utils.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, avatar.ErrUnsupportedType):
+		return utils.ErrorResponse(c, fiber.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, avatar.ErrBadDimensions):
+		return utils.ValidationErrorResponse(c, map[string]string{avatarField: err.Error()})
+	case err != nil:
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to process avatar")
+	}
+
+	user, err := h.currentUser(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	// Every upload gets a fresh prefix, so cached URLs of the old avatar never show the new one
+	key := fmt.Sprintf("avatars/%d/%d", user.ID, time.Now().UnixNano())
+	for _, img := range images {
+		if err := h.store.Put(key+"/"+img.Name+".png", img.Data, "image/png"); err != nil {
+			h.deleteAvatar(key)
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to store avatar")
+		}
+	}
+
+	previous := user.AvatarKey
+	user.AvatarKey = key
+	if err := h.repo.Update(user); err != nil {
+		h.deleteAvatar(key)
+		return errorResponse(c, err)
+	}
+	h.deleteAvatar(previous)
+
+	return utils.SuccessResponse(c, h.profile(user), "Avatar updated")
+}
+
+// DeleteAvatar removes the avatar of the current user
+func (h *UserHandler) DeleteAvatar(c *fiber.Ctx) error {
+	user, err := h.currentUser(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	previous := user.AvatarKey
+	user.AvatarKey = ""
+	if err := h.repo.Update(user); err != nil {
+		return errorResponse(c, err)
+	}
+	h.deleteAvatar(previous)
+
+	return utils.SuccessResponse(c, h.profile(user), "Avatar removed")
+}
+
+// avatarURLs returns the URLs of every rendition of an avatar
+func avatarURLs(store storage.BlobStore, key string) map[string]string {
+	if key == "" {
+		return nil
+	}
+
+	urls := map[string]string{"original": store.URL(key + "/original.png")}
+	for _, size := range avatar.ThumbnailSizes {
+		name := fmt.Sprint(size)
+		urls[name] = store.URL(key + "/" + name + ".png")
+	}
+	return urls
+}
+
+// deleteAvatar removes the stored renditions of an avatar.
+// Failures only leave orphaned files behind, so they are logged rather than returned.
+func (h *UserHandler) deleteAvatar(key string) {
+	if key == "" {
+		return
+	}
+	if err := h.store.Delete(key); err != nil {
+		log.Printf("Failed to delete avatar %s: %v", key, err)
+	}
+}
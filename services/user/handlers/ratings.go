@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"user-service/rating"
+	"user-service/repository"
+	"user-service/seasons"
+	"user-service/stats"
+)
+
+// maxSeasonNameLength bounds season names in characters
+const maxSeasonNameLength = 100
+
+// Page sizes of leaderboards
+const (
+	defaultLeaderboardLimit = 50
+	maxLeaderboardLimit     = 100
+)
+
+// RatingHandler serves ratings, seasons and their leaderboards and rates finished games
+type RatingHandler struct {
+	users   repository.UserRepository
+	ratings repository.RatingRepository
+	seasons repository.SeasonRepository
+	manager *seasons.Manager
+}
+
+// NewRatingHandler creates a new rating handler
+func NewRatingHandler(users repository.UserRepository, ratings repository.RatingRepository, seasonRepo repository.SeasonRepository, manager *seasons.Manager) *RatingHandler {
+	return &RatingHandler{
+		users:   users,
+		ratings: ratings,
+		seasons: seasonRepo,
+		manager: manager,
+	}
+}
+
+// CreateSeasonRequest - body of a create season request
+type CreateSeasonRequest struct {
+	Name     string     `json:"name"`
+	StartsAt *time.Time `json:"starts_at"` // now when omitted
+	EndsAt   time.Time  `json:"ends_at"`
+}
+
+// GetRating returns the rating of a user, the rating of a new player if they have no rated games
+func (h *RatingHandler) GetRating(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	user, err := h.users.FindByID(uint(id))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	r, err := h.ratings.FindRating(user.ID)
+	if errors.Is(err, repository.ErrRatingNotFound) {
+		r = &models.Rating{
+			UserID:     user.ID,
+			Rating:     rating.DefaultRating,
+			Deviation:  rating.DefaultDeviation,
+			Volatility: rating.DefaultVolatility,
+		}
+	} else if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load rating")
+	}
+	return utils.SuccessResponse(c, r, "")
+}
+
+// GetLeaderboard returns the live leaderboard of the current ratings.
+// Pages are selected with offset and limit.
+func (h *RatingHandler) GetLeaderboard(c *fiber.Ctx) error {
+	offset, limit, err := leaderboardPage(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	entries, err := h.manager.Leaderboard(offset, limit)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load leaderboard")
+	}
+	return utils.SuccessResponse(c, entries, "")
+}
+
+// ListSeasons lists all seasons in the order they start
+func (h *RatingHandler) ListSeasons(c *fiber.Ctx) error {
+	list, err := h.seasons.FindSeasons()
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, list, "")
+}
+
+// GetCurrentSeason returns the season being played
+func (h *RatingHandler) GetCurrentSeason(c *fiber.Ctx) error {
+	list, err := h.seasons.FindSeasons()
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	for _, season := range list {
+		if season.Status == repository.SeasonActive {
+			return utils.SuccessResponse(c, season, "")
+		}
+	}
+	return utils.ErrorResponse(c, fiber.StatusNotFound, "No season is running")
+}
+
+// GetSeason returns a season
+func (h *RatingHandler) GetSeason(c *fiber.Ctx) error {
+	season, err := h.season(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, season, "")
+}
+
+// GetSeasonLeaderboard returns the leaderboard of a season: archived once it closed,
+// live while it runs and empty before it starts. Pages are selected with offset and limit.
+func (h *RatingHandler) GetSeasonLeaderboard(c *fiber.Ctx) error {
+	season, err := h.season(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	offset, limit, err := leaderboardPage(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	entries := []models.LeaderboardEntry{}
+	switch season.Status {
+	case repository.SeasonActive:
+		entries, err = h.manager.Leaderboard(offset, limit)
+		for i := range entries {
+			entries[i].SeasonID = season.ID
+		}
+	case repository.SeasonClosed:
+		entries, err = h.seasons.FindLeaderboard(season.ID)
+		entries = entries[min(offset, len(entries)):min(offset+limit, len(entries))]
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load leaderboard")
+	}
+	return utils.SuccessResponse(c, entries, "")
+}
+
+// CreateSeason schedules a season, which starts right away if it is already due
+func (h *RatingHandler) CreateSeason(c *fiber.Ctx) error {
+	var req CreateSeasonRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	now := time.Now()
+	season := &models.Season{
+		Name:     strings.TrimSpace(req.Name),
+		StartsAt: now,
+		EndsAt:   req.EndsAt,
+	}
+	if req.StartsAt != nil {
+		season.StartsAt = *req.StartsAt
+	}
+
+	if season.Name == "" || utf8.RuneCountInString(season.Name) > maxSeasonNameLength {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Name must be between 1 and 100 characters")
+	}
+	if !season.EndsAt.After(season.StartsAt) || !season.EndsAt.After(now) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Season must end after it starts and in the future")
+	}
+
+	err := h.manager.Schedule(season)
+	if err != nil {
+		if errors.Is(err, seasons.ErrOverlap) {
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Season overlaps a season that is not closed")
+		}
+		return errorResponse(c, err)
+	}
+	if !season.StartsAt.After(now) {
+		// closes a season that ended but was not rolled over yet before starting this one
+		if err := h.manager.Rollover(now); err != nil {
+			return errorResponse(c, err)
+		}
+		if season, err = h.seasons.FindSeason(season.ID); err != nil {
+			return errorResponse(c, err)
+		}
+	}
+
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, season, "Season created")
+}
+
+// CloseSeason ends the running season now and archives its leaderboard
+func (h *RatingHandler) CloseSeason(c *fiber.Ctx) error {
+	season, err := h.season(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if err := h.manager.Close(season, time.Now()); err != nil {
+		if errors.Is(err, seasons.ErrNotActive) {
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Only a running season can be closed")
+		}
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, season, "Season closed")
+}
+
+// rateGame applies a finished game between two registered players to their ratings with repo.
+// Games against players without an account, of more than two players or redelivered ones are not rated.
+func (h *RatingHandler) rateGame(repo repository.RatingRepository, summary stats.GameSummary) error {
+	results := stats.Results(summary)
+	if len(summary.Players) != 2 || len(results) != 2 || results[0].UserID == results[1].UserID {
+		return nil
+	}
+
+	var score float64
+	switch results[0].Result {
+	case stats.ResultWin:
+		score = rating.Win
+	case stats.ResultDraw:
+		score = rating.Draw
+	case stats.ResultLoss:
+		score = rating.Loss
+	default:
+		return nil
+	}
+
+	at := time.Now()
+	if summary.FinishedAt != nil {
+		at = *summary.FinishedAt
+	}
+
+	_, err := repo.RateGame(summary.GameID, results[0].UserID, results[1].UserID, func(first, second *models.Rating) {
+		rating.RateGame(first, second, score, at, h.manager.DecayPeriod)
+	})
+	return err
+}
+
+// season loads the season of the id route parameter
+func (h *RatingHandler) season(c *fiber.Ctx) (*models.Season, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid season ID")
+	}
+	return h.seasons.FindSeason(uint(id))
+}
+
+// leaderboardPage returns the offset and limit of a leaderboard request
+func leaderboardPage(c *fiber.Ctx) (int, int, error) {
+	offset := c.QueryInt("offset", 0)
+	limit := c.QueryInt("limit", defaultLeaderboardLimit)
+	if offset < 0 || limit < 1 || limit > maxLeaderboardLimit {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "offset must not be negative and limit must be between 1 and 100")
+	}
+	return offset, limit, nil
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"user-service/repository"
+)
+
+// maxReportDetailsLength bounds the details of a report in characters
+const maxReportDetailsLength = 1000
+
+// Page sizes of the report list
+const (
+	defaultReportLimit = 50
+	maxReportLimit     = 100
+)
+
+// reportReasons are the reasons a user can be reported for
+var reportReasons = map[string]bool{
+	"cheating": true,
+	"abuse":    true,
+	"spam":     true,
+	"other":    true,
+}
+
+// ReportHandler lets players report each other and moderators review the reports
+type ReportHandler struct {
+	users   repository.UserRepository
+	reports repository.ReportRepository
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(users repository.UserRepository, reports repository.ReportRepository) *ReportHandler {
+	return &ReportHandler{
+		users:   users,
+		reports: reports,
+	}
+}
+
+// ReportRequest - body of a report about a user
+type ReportRequest struct {
+	Reason  string `json:"reason"` // cheating, abuse, spam or other
+	GameID  string `json:"game_id"`
+	Details string `json:"details"`
+}
+
+// ReportUser reports the user given by ID to the moderators
+func (h *ReportHandler) ReportUser(c *fiber.Ctx) error {
+	var req ReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	reporter, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if reporter.ID == uint(id) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "You cannot report yourself")
+	}
+	reported, err := h.users.FindByID(uint(id))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	report := &models.Report{
+		ReporterID: reporter.ID,
+		ReportedID: reported.ID,
+		GameID:     strings.TrimSpace(req.GameID),
+		Reason:     req.Reason,
+		Details:    strings.TrimSpace(req.Details),
+	}
+
+	errs := make(map[string]string)
+	if !reportReasons[report.Reason] {
+		errs["reason"] = "must be cheating, abuse, spam or other"
+	}
+	if len(report.GameID) > 64 {
+		errs["game_id"] = "must be at most 64 characters"
+	}
+	if utf8.RuneCountInString(report.Details) > maxReportDetailsLength {
+		errs["details"] = "must be at most 1000 characters"
+	}
+	if len(errs) > 0 {
+		return utils.ValidationErrorResponse(c, errs)
+	}
+
+	if err := h.reports.CreateReport(report); err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, report, "User reported")
+}
+
+// ListReports returns the reports for moderators, newest first.
+// Pages are selected with offset and limit.
+func (h *ReportHandler) ListReports(c *fiber.Ctx) error {
+	offset := c.QueryInt("offset", 0)
+	limit := c.QueryInt("limit", defaultReportLimit)
+	if offset < 0 || limit < 1 || limit > maxReportLimit {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "offset must not be negative and limit must be between 1 and 100")
+	}
+
+	reports, err := h.reports.FindReports(offset, limit)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load reports")
+	}
+	return utils.SuccessResponse(c, reports, "")
+}
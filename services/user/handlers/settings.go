@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+)
+
+// Board sizes users can choose as their default
+const (
+	minBoardSize = 3
+	maxBoardSize = 19
+)
+
+// themes are the accepted values of the theme setting
+var themes = map[string]bool{
+	"light":  true,
+	"dark":   true,
+	"system": true,
+}
+
+// UpdateSettingsRequest - body of a settings update, omitted fields are kept
+type UpdateSettingsRequest struct {
+	DefaultBoardSize *int    `json:"default_board_size"`
+	DefaultWinLength *int    `json:"default_win_length"`
+	Theme            *string `json:"theme"`
+
+	NotifyGameInvites *bool `json:"notify_game_invites"`
+	NotifyYourTurn    *bool `json:"notify_your_turn"`
+	NotifyChat        *bool `json:"notify_chat"`
+	NotifyEmail       *bool `json:"notify_email"`
+}
+
+// GetSettings returns the settings of the current user
+func (h *UserHandler) GetSettings(c *fiber.Ctx) error {
+	user, err := h.currentUser(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	settings, err := h.repo.FindSettings(user.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, settings, "")
+}
+
+// UpdateSettings changes the settings of the current user
+func (h *UserHandler) UpdateSettings(c *fiber.Ctx) error {
+	var req UpdateSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.currentUser(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	settings, err := h.repo.FindSettings(user.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if req.DefaultBoardSize != nil {
+		settings.DefaultBoardSize = *req.DefaultBoardSize
+	}
+	if req.DefaultWinLength != nil {
+		settings.DefaultWinLength = *req.DefaultWinLength
+	}
+	if req.Theme != nil {
+		settings.Theme = *req.Theme
+	}
+	setBool(&settings.NotifyGameInvites, req.NotifyGameInvites)
+	setBool(&settings.NotifyYourTurn, req.NotifyYourTurn)
+	setBool(&settings.NotifyChat, req.NotifyChat)
+	setBool(&settings.NotifyEmail, req.NotifyEmail)
+
+	errs := make(map[string]string)
+	if settings.DefaultBoardSize < minBoardSize || settings.DefaultBoardSize > maxBoardSize {
+		errs["default_board_size"] = fmt.Sprintf("must be between %d and %d", minBoardSize, maxBoardSize)
+	} else if settings.DefaultWinLength < minBoardSize || settings.DefaultWinLength > settings.DefaultBoardSize {
+		errs["default_win_length"] = fmt.Sprintf("must be between %d and the board size", minBoardSize)
+	}
+	if !themes[settings.Theme] {
+		errs["theme"] = "must be light, dark or system"
+	}
+	if len(errs) > 0 {
+		return utils.ValidationErrorResponse(c, errs)
+	}
+
+	if err := h.repo.SaveSettings(settings); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, settings, "Settings updated")
+}
+
+// setBool overwrites dst when a value was given
+func setBool(dst *bool, value *bool) {
+	if value != nil {
+		*dst = *value
+	}
+}
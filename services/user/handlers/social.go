@@ -0,0 +1,502 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"user-service/repository"
+	"user-service/storage"
+)
+
+// PresenceChecker reports which of the users are connected to the chat service
+type PresenceChecker func(userIDs []uint) (map[uint]bool, error)
+
+// GameCreator creates a game on behalf of the user whose Authorization header is forwarded
+// and returns the state of the created game. Rejections of the game service are *fiber.Error.
+type GameCreator func(authorization string, req ChallengeRequest) (json.RawMessage, error)
+
+// SocialHandler serves friends, friend requests and blocks
+type SocialHandler struct {
+	users    repository.UserRepository
+	social   repository.SocialRepository
+	store    storage.BlobStore
+	presence PresenceChecker
+	games    GameCreator
+}
+
+// NewSocialHandler creates a new social handler
+func NewSocialHandler(users repository.UserRepository, social repository.SocialRepository, store storage.BlobStore) *SocialHandler {
+	return &SocialHandler{
+		users:  users,
+		social: social,
+		store:  store,
+	}
+}
+
+// SetPresenceChecker sets the lookup of online friends. Without one, presence is omitted.
+func (h *SocialHandler) SetPresenceChecker(check PresenceChecker) {
+	h.presence = check
+}
+
+// SetGameCreator sets how challenges create games. Without one, challenges are unavailable.
+func (h *SocialHandler) SetGameCreator(create GameCreator) {
+	h.games = create
+}
+
+// Friend - a friend of the current user
+type Friend struct {
+	PublicProfile
+	Since  *time.Time `json:"since"`
+	Online *bool      `json:"online,omitempty"` // omitted when presence is unknown
+}
+
+// FriendRequest - a pending request from or to the current user
+type FriendRequest struct {
+	ID        uint          `json:"id"`
+	User      PublicProfile `json:"user"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// FriendRequests - pending requests of the current user
+type FriendRequests struct {
+	Incoming []FriendRequest `json:"incoming"`
+	Outgoing []FriendRequest `json:"outgoing"`
+}
+
+// BlockedUser - a user blocked by the current user
+type BlockedUser struct {
+	User      PublicProfile `json:"user"`
+	BlockedAt time.Time     `json:"blocked_at"`
+}
+
+// SendFriendRequestBody - body of a friend request, addressing the user by ID or username
+type SendFriendRequestBody struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// ChallengeRequest - settings of a challenge, the challenger's default board when omitted
+type ChallengeRequest struct {
+	Variant   string `json:"variant,omitempty"`
+	BoardSize int    `json:"board_size,omitempty"`
+	WinLength int    `json:"win_length,omitempty"`
+	Gravity   bool   `json:"gravity,omitempty"`
+	BestOf    int    `json:"best_of,omitempty"`
+	Symbol    string `json:"symbol,omitempty"`
+
+	// Invited reserves the second seat for the challenged friend
+	Invited []string `json:"invited"`
+}
+
+// ListFriends returns the friends of the current user with their presence
+func (h *SocialHandler) ListFriends(c *fiber.Ctx) error {
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	friendships, err := h.social.FindFriendships(user.ID, repository.FriendshipAccepted)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	friends := make([]Friend, 0, len(friendships))
+	ids := make([]uint, 0, len(friendships))
+	for _, friendship := range friendships {
+		other, err := h.users.FindByID(otherUserID(friendship, user.ID))
+		if errors.Is(err, repository.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		friends = append(friends, Friend{
+			PublicProfile: publicProfile(h.store, other),
+			Since:         friendship.AcceptedAt,
+		})
+		ids = append(ids, other.ID)
+	}
+
+	if h.presence != nil && len(ids) > 0 {
+		// Friends are still listed when the chat service is unavailable
+		if online, err := h.presence(ids); err == nil {
+			for i := range friends {
+				isOnline := online[friends[i].ID]
+				friends[i].Online = &isOnline
+			}
+		}
+	}
+
+	return utils.SuccessResponse(c, friends, "")
+}
+
+// ListFriendRequests returns the pending requests sent to and by the current user
+func (h *SocialHandler) ListFriendRequests(c *fiber.Ctx) error {
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	friendships, err := h.social.FindFriendships(user.ID, repository.FriendshipPending)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	requests := FriendRequests{
+		Incoming: make([]FriendRequest, 0),
+		Outgoing: make([]FriendRequest, 0),
+	}
+	for _, friendship := range friendships {
+		other, err := h.users.FindByID(otherUserID(friendship, user.ID))
+		if errors.Is(err, repository.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return errorResponse(c, err)
+		}
+
+		request := h.friendRequest(friendship, other)
+		if friendship.AddresseeID == user.ID {
+			requests.Incoming = append(requests.Incoming, request)
+		} else {
+			requests.Outgoing = append(requests.Outgoing, request)
+		}
+	}
+
+	return utils.SuccessResponse(c, requests, "")
+}
+
+// SendFriendRequest asks another user to become friends.
+// A pending request in the other direction is accepted instead.
+func (h *SocialHandler) SendFriendRequest(c *fiber.Ctx) error {
+	var req SendFriendRequestBody
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.UserID == 0 && req.Username == "" {
+		return utils.ValidationErrorResponse(c, map[string]string{"user_id": "user_id or username is required"})
+	}
+
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	var other *models.User
+	if req.UserID != 0 {
+		other, err = h.users.FindByID(req.UserID)
+	} else {
+		other, err = h.users.FindByUsername(req.Username)
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if other.ID == user.ID {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "You cannot befriend yourself")
+	}
+
+	blocked, err := h.isBlocked(user.ID, other.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if blocked {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "You cannot send a friend request to this user")
+	}
+
+	existing, err := h.social.FindFriendship(user.ID, other.ID)
+	switch {
+	case errors.Is(err, repository.ErrFriendshipNotFound):
+	case err != nil:
+		return errorResponse(c, err)
+	case existing.Status == repository.FriendshipAccepted:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "You are already friends")
+	case existing.RequesterID == user.ID:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Friend request already sent")
+	default:
+		if err := h.accept(existing); err != nil {
+			return errorResponse(c, err)
+		}
+		return utils.SuccessResponse(c, h.friendRequest(*existing, other), "Friend request accepted")
+	}
+
+	friendship := &models.Friendship{
+		RequesterID: user.ID,
+		AddresseeID: other.ID,
+		Status:      repository.FriendshipPending,
+	}
+	if err := h.social.CreateFriendship(friendship); err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, h.friendRequest(*friendship, other), "Friend request sent")
+}
+
+// AcceptFriendRequest accepts a request sent to the current user
+func (h *SocialHandler) AcceptFriendRequest(c *fiber.Ctx) error {
+	user, friendship, err := h.pendingRequest(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if friendship.AddresseeID != user.ID {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Friend request not found")
+	}
+
+	if err := h.accept(friendship); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, friendship, "Friend request accepted")
+}
+
+// DeclineFriendRequest declines a request sent to the current user or cancels one they sent
+func (h *SocialHandler) DeclineFriendRequest(c *fiber.Ctx) error {
+	user, friendship, err := h.pendingRequest(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if err := h.social.DeleteFriendship(friendship.ID); err != nil {
+		return errorResponse(c, err)
+	}
+	if friendship.RequesterID == user.ID {
+		return utils.SuccessResponse(c, nil, "Friend request cancelled")
+	}
+	return utils.SuccessResponse(c, nil, "Friend request declined")
+}
+
+// RemoveFriend ends the friendship with the user given by ID
+func (h *SocialHandler) RemoveFriend(c *fiber.Ctx) error {
+	_, friendship, err := h.friendship(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if err := h.social.DeleteFriendship(friendship.ID); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, nil, "Friend removed")
+}
+
+// ChallengeFriend creates a game with the second seat reserved for a friend
+func (h *SocialHandler) ChallengeFriend(c *fiber.Ctx) error {
+	var req ChallengeRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	if h.games == nil {
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Challenges are unavailable")
+	}
+
+	user, friendship, err := h.friendship(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if req.Variant == "" && req.BoardSize == 0 && req.WinLength == 0 {
+		settings, err := h.users.FindSettings(user.ID)
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		// The classic variant is the 3x3 board, larger defaults are played as connect
+		if settings.DefaultBoardSize != repository.DefaultBoardSize || settings.DefaultWinLength != repository.DefaultWinLength {
+			req.Variant = "connect"
+			req.BoardSize = settings.DefaultBoardSize
+			req.WinLength = settings.DefaultWinLength
+		}
+	}
+	req.Invited = []string{strconv.FormatUint(uint64(otherUserID(*friendship, user.ID)), 10)}
+
+	game, err := h.games(c.Get(fiber.HeaderAuthorization), req)
+	var rejected *fiber.Error
+	if errors.As(err, &rejected) {
+		return utils.ErrorResponse(c, rejected.Code, rejected.Message)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadGateway, "Failed to create game")
+	}
+
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, game, "Challenge sent")
+}
+
+// ListBlocks returns the users blocked by the current user
+func (h *SocialHandler) ListBlocks(c *fiber.Ctx) error {
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	blocks, err := h.social.FindBlocks(user.ID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	blocked := make([]BlockedUser, 0, len(blocks))
+	for _, block := range blocks {
+		other, err := h.users.FindByID(block.BlockedID)
+		if errors.Is(err, repository.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		blocked = append(blocked, BlockedUser{User: publicProfile(h.store, other), BlockedAt: block.CreatedAt})
+	}
+
+	return utils.SuccessResponse(c, blocked, "")
+}
+
+// BlockUser blocks the user given by ID, ending any friendship with them
+func (h *SocialHandler) BlockUser(c *fiber.Ctx) error {
+	user, other, err := h.otherUser(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if err := h.social.Block(user.ID, other.ID); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, publicProfile(h.store, other), "User blocked")
+}
+
+// UnblockUser lifts a block of the current user
+func (h *SocialHandler) UnblockUser(c *fiber.Ctx) error {
+	user, other, err := h.otherUser(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if err := h.social.Unblock(user.ID, other.ID); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, publicProfile(h.store, other), "User unblocked")
+}
+
+// BlockedUserIDs returns the users blocked by or blocking a user, for the chat and game services
+func (h *SocialHandler) BlockedUserIDs(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	blocked, err := h.social.BlockedUserIDs(uint(id))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	userIDs := make([]string, 0, len(blocked))
+	for _, blockedID := range blocked {
+		userIDs = append(userIDs, strconv.FormatUint(uint64(blockedID), 10))
+	}
+	return utils.SuccessResponse(c, fiber.Map{"user_ids": userIDs}, "")
+}
+
+// pendingRequest loads the current user and the pending request given by ID,
+// which must have been sent to or by them
+func (h *SocialHandler) pendingRequest(c *fiber.Ctx) (*models.User, *models.Friendship, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "Invalid friend request ID")
+	}
+
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	friendship, err := h.social.FindFriendshipByID(uint(id))
+	if err != nil {
+		return nil, nil, err
+	}
+	if friendship.Status != repository.FriendshipPending ||
+		(friendship.RequesterID != user.ID && friendship.AddresseeID != user.ID) {
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "Friend request not found")
+	}
+	return user, friendship, nil
+}
+
+// friendship loads the current user and their friendship with the user given by ID
+func (h *SocialHandler) friendship(c *fiber.Ctx) (*models.User, *models.Friendship, error) {
+	user, other, err := h.otherUser(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	friendship, err := h.social.FindFriendship(user.ID, other.ID)
+	if err != nil && !errors.Is(err, repository.ErrFriendshipNotFound) {
+		return nil, nil, err
+	}
+	if friendship == nil || friendship.Status != repository.FriendshipAccepted {
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "You are not friends with this user")
+	}
+	return user, friendship, nil
+}
+
+// otherUser loads the current user and another user given by ID
+func (h *SocialHandler) otherUser(c *fiber.Ctx) (*models.User, *models.User, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	user, err := loadCurrentUser(c, h.users)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.ID == uint(id) {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "You cannot do this to yourself")
+	}
+
+	other, err := h.users.FindByID(uint(id))
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, other, nil
+}
+
+// isBlocked checks if either user blocked the other
+func (h *SocialHandler) isBlocked(userID, otherID uint) (bool, error) {
+	blocked, err := h.social.BlockedUserIDs(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range blocked {
+		if id == otherID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// accept turns a pending request into a friendship
+func (h *SocialHandler) accept(friendship *models.Friendship) error {
+	now := time.Now()
+	friendship.Status = repository.FriendshipAccepted
+	friendship.AcceptedAt = &now
+	return h.social.SaveFriendship(friendship)
+}
+
+// friendRequest returns the view of a request with the other user
+func (h *SocialHandler) friendRequest(friendship models.Friendship, other *models.User) FriendRequest {
+	return FriendRequest{
+		ID:        friendship.ID,
+		User:      publicProfile(h.store, other),
+		CreatedAt: friendship.CreatedAt,
+	}
+}
+
+// otherUserID returns the user of the friendship who is not userID
+func otherUserID(friendship models.Friendship, userID uint) uint {
+	if friendship.RequesterID == userID {
+		return friendship.AddresseeID
+	}
+	return friendship.RequesterID
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/outbox"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"user-service/achievements"
+	"user-service/repository"
+	"user-service/stats"
+)
+
+// StatsHandler serves player statistics and records the games they are computed from
+type StatsHandler struct {
+	users        repository.UserRepository
+	results      repository.StatsRepository
+	messages     repository.MessageRepository
+	achievements *AchievementHandler
+	ratings      *RatingHandler
+}
+
+// NewStatsHandler creates a new statistics handler consuming each received message once with messages
+func NewStatsHandler(users repository.UserRepository, results repository.StatsRepository, messages repository.MessageRepository) *StatsHandler {
+	return &StatsHandler{
+		users:    users,
+		results:  results,
+		messages: messages,
+	}
+}
+
+// SetAchievementHandler sets the handler unlocking badges from received events.
+// Without one, events only update statistics.
+func (h *StatsHandler) SetAchievementHandler(achievements *AchievementHandler) {
+	h.achievements = achievements
+}
+
+// SetRatingHandler sets the handler rating finished games. Without one, games are not rated.
+func (h *StatsHandler) SetRatingHandler(ratings *RatingHandler) {
+	h.ratings = ratings
+}
+
+// GetStats returns the aggregate statistics of a user
+func (h *StatsHandler) GetStats(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil || id == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	user, err := h.users.FindByID(uint(id))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	results, err := h.results.FindResults(user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load statistics")
+	}
+	return utils.SuccessResponse(c, stats.Compute(user.ID, results), "")
+}
+
+// ReceiveEvent records the results of a game.finished message delivered by the game service outbox,
+// rates the game and unlocks the badges earned by it or by a chat.message_sent message of the chat service.
+// Games ended by a moderator have no results, so they are neither recorded, rated nor awarded badges,
+// and other events are acknowledged and ignored. Messages are consumed once by their idempotency key,
+// so redelivered ones do not count twice.
+func (h *StatsHandler) ReceiveEvent(c *fiber.Ctx) error {
+	var envelope outbox.Envelope
+	if err := c.BodyParser(&envelope); err != nil || envelope.IdempotencyKey == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid event envelope")
+	}
+
+	var unlocked []models.Achievement
+	var handle func(repo repository.ConsumerRepository) error
+	switch envelope.EventType {
+	case stats.GameFinishedEvent:
+		var summary stats.GameSummary
+		if err := json.Unmarshal(envelope.Payload, &summary); err != nil || summary.GameID == "" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid game summary")
+		}
+		handle = func(repo repository.ConsumerRepository) (err error) {
+			unlocked, err = h.gameFinished(repo, summary)
+			return err
+		}
+
+	case achievements.ChatMessageSentEvent:
+		if h.achievements == nil {
+			return utils.SuccessResponse(c, nil, "Event ignored")
+		}
+
+		var event ChatMessageSent
+		if err := json.Unmarshal(envelope.Payload, &event); err != nil || event.UserID == "" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid chat message event")
+		}
+		handle = func(repo repository.ConsumerRepository) (err error) {
+			unlocked, err = h.achievements.chatMessageSent(repo, event)
+			return err
+		}
+
+	default:
+		return utils.SuccessResponse(c, nil, "Event ignored")
+	}
+
+	if err := h.messages.ConsumeMessage(envelope, handle); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to process event")
+	}
+	// Badges are announced once they are stored with the message
+	if h.achievements != nil {
+		h.achievements.announce(unlocked)
+	}
+	return utils.SuccessResponse(c, nil, "Event processed")
+}
+
+// gameFinished records the results of a finished game with repo, rates it and unlocks the badges
+// earned with it, returning those the players did not have before
+func (h *StatsHandler) gameFinished(repo repository.ConsumerRepository, summary stats.GameSummary) ([]models.Achievement, error) {
+	if err := repo.RecordResults(stats.Results(summary)); err != nil {
+		return nil, err
+	}
+	if h.ratings != nil {
+		if err := h.ratings.rateGame(repo, summary); err != nil {
+			return nil, err
+		}
+	}
+	if h.achievements != nil {
+		return h.achievements.gameFinished(repo, summary)
+	}
+	return nil, nil
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/utils"
+
+	"user-service/repository"
+	"user-service/storage"
+)
+
+// maxNameLength bounds first and last names in characters
+const maxNameLength = 50
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+
+// UserHandler serves profile, avatar and settings endpoints
+type UserHandler struct {
+	repo  repository.UserRepository
+	store storage.BlobStore
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(repo repository.UserRepository, store storage.BlobStore) *UserHandler {
+	return &UserHandler{
+		repo:  repo,
+		store: store,
+	}
+}
+
+// Profile - profile of the current user
+type Profile struct {
+	PublicProfile
+	Email    string `json:"email"`
+	IsActive bool   `json:"is_active"`
+}
+
+// PublicProfile - profile visible to everyone
+type PublicProfile struct {
+	ID        uint              `json:"id"`
+	Username  string            `json:"username"`
+	FirstName string            `json:"first_name"`
+	LastName  string            `json:"last_name"`
+	Avatar    map[string]string `json:"avatar,omitempty"` // URLs by size, "original" included
+	JoinedAt  string            `json:"joined_at"`
+}
+
+// UpdateProfileRequest - body of a profile update, omitted fields are kept
+type UpdateProfileRequest struct {
+	Username  *string `json:"username"`
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+}
+
+// GetProfile returns the profile of the current user
+func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
+	user, err := h.currentUser(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, h.profile(user), "")
+}
+
+// UpdateProfile changes the username and names of the current user
+func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
+	var req UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.currentUser(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	errs := make(map[string]string)
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if !usernamePattern.MatchString(username) {
+			errs["username"] = "must be 3 to 30 letters, digits or underscores"
+		}
+		user.Username = username
+	}
+	if req.FirstName != nil {
+		user.FirstName = strings.TrimSpace(*req.FirstName)
+		if utf8.RuneCountInString(user.FirstName) > maxNameLength {
+			errs["first_name"] = "must be at most 50 characters"
+		}
+	}
+	if req.LastName != nil {
+		user.LastName = strings.TrimSpace(*req.LastName)
+		if utf8.RuneCountInString(user.LastName) > maxNameLength {
+			errs["last_name"] = "must be at most 50 characters"
+		}
+	}
+	if len(errs) > 0 {
+		return utils.ValidationErrorResponse(c, errs)
+	}
+
+	if err := h.repo.Update(user); err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, h.profile(user), "Profile updated")
+}
+
+// CreateUserRequest - body of an internal request creating the profile of a registered user
+type CreateUserRequest struct {
+	ID       uint   `json:"id"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// CreateUser creates the profile of a user registered by the auth service.
+// Creating a profile that already exists succeeds, so the auth service may retry.
+func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
+	var req CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.ID == 0 || req.Email == "" || !usernamePattern.MatchString(req.Username) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID, email and a valid username are required")
+	}
+
+	if user, err := h.repo.FindByID(req.ID); err == nil {
+		return utils.SuccessResponse(c, h.profile(user), "")
+	} else if !errors.Is(err, repository.ErrUserNotFound) {
+		return errorResponse(c, err)
+	}
+
+	user := &models.User{
+		BaseModel: models.BaseModel{ID: req.ID},
+		Email:     req.Email,
+		Username:  req.Username,
+		IsActive:  true,
+	}
+	if err := h.repo.Create(user); err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Status(fiber.StatusCreated)
+	return utils.SuccessResponse(c, h.profile(user), "Profile created")
+}
+
+// GetPublicProfile returns the public profile of a user by username
+func (h *UserHandler) GetPublicProfile(c *fiber.Ctx) error {
+	user, err := h.repo.FindByUsername(c.Params("username"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, publicProfile(h.store, user), "")
+}
+
+// currentUser loads the authenticated user
+func (h *UserHandler) currentUser(c *fiber.Ctx) (*models.User, error) {
+	return loadCurrentUser(c, h.repo)
+}
+
+// loadCurrentUser loads the authenticated user from repo
+func loadCurrentUser(c *fiber.Ctx, repo repository.UserRepository) (*models.User, error) {
+	userID, _ := c.Locals("user_id").(string)
+	id, err := strconv.ParseUint(userID, 10, 0)
+	if err != nil || id == 0 {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid user")
+	}
+	return repo.FindByID(uint(id))
+}
+
+// profile returns the private view of a user
+func (h *UserHandler) profile(user *models.User) Profile {
+	return Profile{
+		PublicProfile: publicProfile(h.store, user),
+		Email:         user.Email,
+		IsActive:      user.IsActive,
+	}
+}
+
+// publicProfile returns the view of a user shown to others
+func publicProfile(store storage.BlobStore, user *models.User) PublicProfile {
+	return PublicProfile{
+		ID:        user.ID,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Avatar:    avatarURLs(store, user.AvatarKey),
+		JoinedAt:  user.CreatedAt.UTC().Format("2006-01-02"),
+	}
+}
+
+// errorResponse maps errors of handler helpers and repositories to HTTP responses
+func errorResponse(c *fiber.Ctx, err error) error {
+	var rejected *fiber.Error
+	switch {
+	case errors.As(err, &rejected):
+		return utils.ErrorResponse(c, rejected.Code, rejected.Message)
+	case errors.Is(err, repository.ErrUserNotFound):
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+	case errors.Is(err, repository.ErrUsernameTaken):
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Username is already taken")
+	case errors.Is(err, repository.ErrFriendshipNotFound):
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Friend request not found")
+	case errors.Is(err, repository.ErrFriendshipExists):
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Friend request already sent")
+	case errors.Is(err, repository.ErrSeasonNotFound):
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Season not found")
+	case errors.Is(err, repository.ErrSeasonChanged):
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Season was changed by another request")
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to access user")
+}
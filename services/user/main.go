@@ -1,13 +1,45 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/your-org/go-tic-tac-toe/pkg/config"
+	"github.com/your-org/go-tic-tac-toe/pkg/database"
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/outbox"
+
+	"user-service/handlers"
+	"user-service/repository"
+	"user-service/routes"
+	"user-service/seasons"
+	"user-service/storage"
 )
 
 func main() {
+	cfg := config.Load()
+
+	store, err := storage.NewLocalStore(cfg.Storage.Dir, cfg.Storage.BaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	repo := newUserRepository(cfg)
+	userHandler := handlers.NewUserHandler(repo, store)
+	statsHandler := handlers.NewStatsHandler(repo, repo, repo)
+	achievementHandler := handlers.NewAchievementHandler(repo, repo)
+	achievementHandler.SetNotifier(chatServiceNotifier(serviceURL("CHAT_SERVICE_URL", "http://localhost:8084"), cfg.Service.Secret))
+	statsHandler.SetAchievementHandler(achievementHandler)
+	seasonManager := seasons.NewManager(repo)
+	ratingHandler := handlers.NewRatingHandler(repo, repo, repo, seasonManager)
+	statsHandler.SetRatingHandler(ratingHandler)
+	reportHandler := handlers.NewReportHandler(repo, repo)
+	socialHandler := handlers.NewSocialHandler(repo, repo, store)
+	socialHandler.SetPresenceChecker(chatServicePresence(serviceURL("CHAT_SERVICE_URL", "http://localhost:8084"), cfg.Service.Secret))
+	socialHandler.SetGameCreator(gameServiceCreator(serviceURL("GAME_SERVICE_URL", "http://localhost:8083")))
+
 	app := fiber.New()
 
 	// CORS middleware
@@ -21,6 +53,9 @@ func main() {
 		})
 	})
 
+	// Avatars and other blobs
+	app.Static(cfg.Storage.BaseURL, store.Dir())
+
 	// User endpoints
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -29,19 +64,52 @@ func main() {
 		})
 	})
 
-	app.Get("/profile", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "Get profile endpoint",
-			"status": "not implemented yet",
-		})
-	})
+	routes.Setup(app, userHandler, statsHandler, achievementHandler, socialHandler, ratingHandler, reportHandler, cfg.JWT.Secret, cfg.Service.Secret, cfg.Outbox.SubscriberSecret)
 
-	app.Put("/profile", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "Update profile endpoint",
-			"status": "not implemented yet",
-		})
-	})
+	// Season rollover and rating decay
+	go seasonManager.Run(context.Background())
 
 	log.Fatal(app.Listen(":8082"))
-} 
\ No newline at end of file
+}
+
+// userRepository stores users together with their game results, social graph, badges, ratings, seasons and reports
+type userRepository interface {
+	repository.UserRepository
+	repository.StatsRepository
+	repository.SocialRepository
+	repository.AchievementRepository
+	repository.RatingRepository
+	repository.SeasonRepository
+	repository.ReportRepository
+	repository.MessageRepository
+}
+
+// newUserRepository returns a Postgres repository, or an in-memory repository if asked to
+// with STORAGE=memory. It exits when the database is unavailable rather than losing consumed events.
+func newUserRepository(cfg *config.Config) userRepository {
+	if cfg.Database.InMemory {
+		log.Println("STORAGE=memory, users and their statistics are lost on exit")
+		return repository.NewMemoryUserRepository()
+	}
+
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Database unavailable: %v", err)
+	}
+
+	if err := database.AutoMigrate(db, &models.User{}, &models.UserSettings{}, &models.GameResult{}, &models.Friendship{}, &models.Block{}, &models.Achievement{}, &models.UserActivity{}, &models.Rating{}, &models.RatedGame{}, &models.Season{}, &models.LeaderboardEntry{}, &models.Report{}); err != nil {
+		log.Fatal(err)
+	}
+	if err := outbox.MigrateConsumer(db); err != nil {
+		log.Fatal(err)
+	}
+	return repository.NewPostgresUserRepository(db)
+}
+
+// serviceURL returns the base URL of another service from the environment
+func serviceURL(key, fallback string) string {
+	if url := os.Getenv(key); url != "" {
+		return url
+	}
+	return fallback
+}
\ No newline at end of file
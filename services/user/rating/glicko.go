@@ -0,0 +1,166 @@
+package rating
+
+import (
+	"math"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+)
+
+// Ratings of new players
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+)
+
+// MinDeviation keeps the ratings of very active players responsive
+const MinDeviation = 30.0
+
+// Soft reset at the start of a season: ratings move halfway back to the default
+// and become uncertain enough to settle again within a few games
+const (
+	SeasonCarryOver      = 0.5
+	SeasonResetDeviation = 200.0
+)
+
+const (
+	// tau constrains how fast volatility changes
+	tau = 0.5
+	// scale converts ratings to and from the Glicko-2 scale
+	scale = 173.7178
+	// convergence is the tolerance of the volatility iteration
+	convergence = 0.000001
+)
+
+// Scores of a game
+const (
+	Win  = 1.0
+	Draw = 0.5
+	Loss = 0.0
+)
+
+// RateGame updates the ratings of two players after a game between them at the given time,
+// each game being its own rating period. Ratings without RatedAt are initialized first.
+// Deviations grow for the whole periods since they were last brought up to date.
+func RateGame(first, second *models.Rating, firstScore float64, at time.Time, period time.Duration) {
+	for _, r := range []*models.Rating{first, second} {
+		if r.RatedAt.IsZero() {
+			initialize(r, at)
+		}
+		Decay(r, at, period)
+	}
+
+	before := *first
+	update(first, []game{{opponent: *second, score: firstScore}})
+	update(second, []game{{opponent: before, score: 1 - firstScore}})
+
+	for _, r := range []*models.Rating{first, second} {
+		r.Games++
+		if at.After(r.RatedAt) {
+			r.RatedAt = at
+		}
+	}
+}
+
+// Decay grows the deviation of a rating for every whole period since it was last brought
+// up to date, as Glicko-2 does for players who skip rating periods, up to the deviation
+// of a new player. It reports whether the rating changed.
+func Decay(r *models.Rating, now time.Time, period time.Duration) bool {
+	periods := int64(now.Sub(r.RatedAt) / period)
+	if periods < 1 {
+		return false
+	}
+
+	phi := r.Deviation / scale
+	phi = math.Sqrt(phi*phi + float64(periods)*r.Volatility*r.Volatility)
+	r.Deviation = math.Min(phi*scale, DefaultDeviation)
+	r.RatedAt = r.RatedAt.Add(time.Duration(periods) * period)
+	return true
+}
+
+// SoftReset pulls a rating towards the default at the start of a season and starts
+// counting the games of the season from zero
+func SoftReset(r *models.Rating, at time.Time) {
+	r.Rating = DefaultRating + (r.Rating-DefaultRating)*SeasonCarryOver
+	r.Deviation = math.Max(r.Deviation, SeasonResetDeviation)
+	r.Games = 0
+	r.RatedAt = at
+}
+
+// initialize gives a player the rating of a new player
+func initialize(r *models.Rating, at time.Time) {
+	r.Rating = DefaultRating
+	r.Deviation = DefaultDeviation
+	r.Volatility = DefaultVolatility
+	r.RatedAt = at
+}
+
+// game - a game of a rating period from the point of view of the rated player
+type game struct {
+	opponent models.Rating
+	score    float64
+}
+
+// update applies the games of a rating period with the Glicko-2 algorithm
+func update(r *models.Rating, games []game) {
+	mu := (r.Rating - DefaultRating) / scale
+	phi := r.Deviation / scale
+
+	var vInverse, improvement float64
+	for _, game := range games {
+		opponentMu := (game.opponent.Rating - DefaultRating) / scale
+		opponentPhi := game.opponent.Deviation / scale
+
+		g := 1 / math.Sqrt(1+3*opponentPhi*opponentPhi/(math.Pi*math.Pi))
+		expected := 1 / (1 + math.Exp(-g*(mu-opponentMu)))
+		vInverse += g * g * expected * (1 - expected)
+		improvement += g * (game.score - expected)
+	}
+	v := 1 / vInverse
+	delta := v * improvement
+
+	sigma := volatility(phi, r.Volatility, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * improvement
+
+	r.Rating = mu*scale + DefaultRating
+	r.Deviation = math.Max(MinDeviation, math.Min(phi*scale, DefaultDeviation))
+	r.Volatility = sigma
+}
+
+// volatility returns the new volatility with the Illinois algorithm of the Glicko-2 paper
+func volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	lower := a
+	var upper float64
+	if delta*delta > phi*phi+v {
+		upper = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		upper = a - k*tau
+	}
+
+	fLower, fUpper := f(lower), f(upper)
+	for math.Abs(upper-lower) > convergence {
+		c := lower + (lower-upper)*fLower/(fUpper-fLower)
+		fc := f(c)
+		if fc*fUpper <= 0 {
+			lower, fLower = upper, fUpper
+		} else {
+			fLower /= 2
+		}
+		upper, fUpper = c, fc
+	}
+	return math.Exp(lower / 2)
+}
@@ -0,0 +1,106 @@
+package rating
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+)
+
+const day = 24 * time.Hour
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestUpdateMatchesGlickmansExample(t *testing.T) {
+	// The worked example of Glickman's "Example of the Glicko-2 system"
+	r := models.Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	update(&r, []game{
+		{opponent: models.Rating{Rating: 1400, Deviation: 30}, score: Win},
+		{opponent: models.Rating{Rating: 1550, Deviation: 100}, score: Loss},
+		{opponent: models.Rating{Rating: 1700, Deviation: 300}, score: Loss},
+	})
+
+	if !near(r.Rating, 1464.06, 0.01) || !near(r.Deviation, 151.52, 0.01) || !near(r.Volatility, 0.05999, 0.00001) {
+		t.Fatalf("got %.2f/%.2f/%.5f, want 1464.06/151.52/0.05999", r.Rating, r.Deviation, r.Volatility)
+	}
+}
+
+func TestRateGame(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var winner, loser models.Rating
+	RateGame(&winner, &loser, Win, start, day)
+
+	if winner.Rating <= DefaultRating || loser.Rating >= DefaultRating {
+		t.Fatalf("ratings %.2f and %.2f did not move apart", winner.Rating, loser.Rating)
+	}
+	if !near(winner.Rating-DefaultRating, DefaultRating-loser.Rating, 1e-6) {
+		t.Errorf("equal players gained %.4f and lost %.4f", winner.Rating-DefaultRating, DefaultRating-loser.Rating)
+	}
+	if winner.Deviation >= DefaultDeviation || winner.Games != 1 || !winner.RatedAt.Equal(start) {
+		t.Errorf("winner %+v", winner)
+	}
+
+	before := winner
+	RateGame(&winner, &loser, Draw, start.Add(time.Hour), day)
+	if winner.Rating >= before.Rating || winner.Games != 2 {
+		t.Errorf("drawing a weaker player moved the rating from %.2f to %.2f", before.Rating, winner.Rating)
+	}
+}
+
+func TestDecay(t *testing.T) {
+	ratedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		deviation float64
+		elapsed   time.Duration
+		changed   bool
+		want      float64
+		ratedAt   time.Time
+	}{
+		{"within the period", 50, 23 * time.Hour, false, 50, ratedAt},
+		// sqrt((50/173.7178)^2 + 2*0.06^2) * 173.7178
+		{"two whole periods", 50, 2*day + 5*time.Hour, true, 52.13, ratedAt.Add(2 * day)},
+		{"capped at a new player", 340, 1000 * day, true, DefaultDeviation, ratedAt.Add(1000 * day)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := models.Rating{Rating: 1600, Deviation: tt.deviation, Volatility: DefaultVolatility, RatedAt: ratedAt}
+			changed := Decay(&r, ratedAt.Add(tt.elapsed), day)
+
+			if changed != tt.changed || !near(r.Deviation, tt.want, 0.01) || !r.RatedAt.Equal(tt.ratedAt) {
+				t.Fatalf("changed %v, deviation %.2f at %v; want %v, %.2f at %v", changed, r.Deviation, r.RatedAt, tt.changed, tt.want, tt.ratedAt)
+			}
+			if r.Rating != 1600 {
+				t.Errorf("rating changed to %.2f", r.Rating)
+			}
+		})
+	}
+}
+
+func TestSoftReset(t *testing.T) {
+	at := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                string
+		rating, deviation   float64
+		wantRating, wantDev float64
+	}{
+		{"strong settled player", 1900, 60, 1700, SeasonResetDeviation},
+		{"weak player", 1300, 80, 1400, SeasonResetDeviation},
+		{"uncertain player keeps the deviation", 1500, 250, 1500, 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := models.Rating{Rating: tt.rating, Deviation: tt.deviation, Volatility: DefaultVolatility, Games: 42}
+			SoftReset(&r, at)
+
+			if r.Rating != tt.wantRating || r.Deviation != tt.wantDev || r.Games != 0 || !r.RatedAt.Equal(at) {
+				t.Fatalf("got %+v, want %.0f/%.0f with no games at %v", r, tt.wantRating, tt.wantDev, at)
+			}
+		})
+	}
+}
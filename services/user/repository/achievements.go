@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FindAchievements returns the badges of a user in the order they were unlocked
+func (r *PostgresUserRepository) FindAchievements(userID uint) ([]models.Achievement, error) {
+	var achievements []models.Achievement
+	err := r.db.Where("user_id = ?", userID).Order("unlocked_at, id").Find(&achievements).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load achievements: %w", err)
+	}
+	return achievements, nil
+}
+
+// UnlockAchievements stores badges and returns those the users did not have before
+func (r *PostgresUserRepository) UnlockAchievements(achievements []models.Achievement) ([]models.Achievement, error) {
+	var unlocked []models.Achievement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		unlocked = nil
+		for _, achievement := range achievements {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&achievement)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				unlocked = append(unlocked, achievement)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock achievements: %w", err)
+	}
+	return unlocked, nil
+}
+
+// RecordChatMessage counts a chat message of the user and returns their total
+func (r *PostgresUserRepository) RecordChatMessage(userID uint) (int, error) {
+	activity := models.UserActivity{UserID: userID, ChatMessages: 1}
+	err := r.db.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"chat_messages": gorm.Expr("user_activities.chat_messages + 1"),
+				"updated_at":    time.Now(),
+			}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "chat_messages"}}},
+	).Create(&activity).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to record chat message: %w", err)
+	}
+	return activity.ChatMessages, nil
+}
+
+// FindAchievements returns the badges of a user in the order they were unlocked
+func (r *MemoryUserRepository) FindAchievements(userID uint) ([]models.Achievement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var achievements []models.Achievement
+	for _, achievement := range r.badges {
+		if achievement.UserID == userID {
+			achievements = append(achievements, achievement)
+		}
+	}
+	return achievements, nil
+}
+
+// UnlockAchievements stores badges and returns those the users did not have before
+func (r *MemoryUserRepository) UnlockAchievements(achievements []models.Achievement) ([]models.Achievement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unlocked []models.Achievement
+	for _, achievement := range achievements {
+		if r.hasAchievement(achievement.UserID, achievement.Key) {
+			continue
+		}
+		r.nextBadgeID++
+		achievement.ID = r.nextBadgeID
+		r.badges = append(r.badges, achievement)
+		unlocked = append(unlocked, achievement)
+	}
+	return unlocked, nil
+}
+
+// RecordChatMessage counts a chat message of the user and returns their total
+func (r *MemoryUserRepository) RecordChatMessage(userID uint) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	activity := r.activity[userID]
+	activity.UserID = userID
+	activity.ChatMessages++
+	activity.UpdatedAt = time.Now()
+	r.activity[userID] = activity
+	return activity.ChatMessages, nil
+}
+
+// hasAchievement reports whether the user earned the badge
+func (r *MemoryUserRepository) hasAchievement(userID uint, key string) bool {
+	for _, achievement := range r.badges {
+		if achievement.UserID == userID && achievement.Key == key {
+			return true
+		}
+	}
+	return false
+}
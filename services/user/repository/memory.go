@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+)
+
+// MemoryUserRepository keeps users and everything the user service stores about them in process memory
+type MemoryUserRepository struct {
+	users       map[uint]models.User
+	settings    map[uint]models.UserSettings
+	results     map[resultKey]models.GameResult
+	friendships map[uint]models.Friendship
+	blocks      []models.Block
+	badges      []models.Achievement
+	activity    map[uint]models.UserActivity
+	ratings     map[uint]models.Rating
+	ratedGames  map[string]bool
+	seasons     []models.Season
+	archive     map[uint][]models.LeaderboardEntry // leaderboards by season ID
+	reports     []models.Report
+	consumed    map[string]bool // idempotency keys of consumed messages
+
+	nextID           uint
+	nextResultID     uint
+	nextFriendshipID uint
+	nextBadgeID      uint
+	nextSeasonID     uint
+	nextReportID     uint
+	mu               sync.RWMutex
+	// consumeMu serializes message consumers, which use the repository while holding it
+	consumeMu sync.Mutex
+}
+
+// NewMemoryUserRepository creates an empty in-memory repository
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		users:       make(map[uint]models.User),
+		settings:    make(map[uint]models.UserSettings),
+		results:     make(map[resultKey]models.GameResult),
+		friendships: make(map[uint]models.Friendship),
+		activity:    make(map[uint]models.UserActivity),
+		ratings:     make(map[uint]models.Rating),
+		ratedGames:  make(map[string]bool),
+		archive:     make(map[uint][]models.LeaderboardEntry),
+		consumed:    make(map[string]bool),
+		nextID:      1,
+	}
+}
+
+// Create stores a new user, assigning an ID if it has none
+func (r *MemoryUserRepository) Create(user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.usernameTaken(user.Username, user.ID) {
+		return ErrUsernameTaken
+	}
+	if user.ID == 0 {
+		user.ID = r.nextID
+	}
+	if user.ID >= r.nextID {
+		r.nextID = user.ID + 1
+	}
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+
+	r.users[user.ID] = *user
+	return nil
+}
+
+// FindByID returns the user with the given ID
+func (r *MemoryUserRepository) FindByID(id uint) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &user, nil
+}
+
+// FindByIDs returns the users with the given IDs, leaving out unknown ones
+func (r *MemoryUserRepository) FindByIDs(ids []uint) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []models.User
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// FindByUsername returns the user with the given username
+func (r *MemoryUserRepository) FindByUsername(username string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+// Update saves the profile fields of an existing user
+func (r *MemoryUserRepository) Update(user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	if r.usernameTaken(user.Username, user.ID) {
+		return ErrUsernameTaken
+	}
+
+	stored.Username = user.Username
+	stored.FirstName = user.FirstName
+	stored.LastName = user.LastName
+	stored.AvatarKey = user.AvatarKey
+	stored.UpdatedAt = time.Now()
+	r.users[user.ID] = stored
+	return nil
+}
+
+// usernameTaken reports whether a user other than id has the username
+func (r *MemoryUserRepository) usernameTaken(username string, id uint) bool {
+	for _, other := range r.users {
+		if other.ID != id && other.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+// FindSettings returns the settings of the user, or the defaults if none were saved
+func (r *MemoryUserRepository) FindSettings(userID uint) (*models.UserSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settings, ok := r.settings[userID]
+	if !ok {
+		return DefaultSettings(userID), nil
+	}
+	return &settings, nil
+}
+
+// SaveSettings stores the settings of a user
+func (r *MemoryUserRepository) SaveSettings(settings *models.UserSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if stored, ok := r.settings[settings.UserID]; ok {
+		settings.ID, settings.CreatedAt = stored.ID, stored.CreatedAt
+	} else {
+		settings.ID, settings.CreatedAt = uint(len(r.settings)+1), now
+	}
+	settings.UpdatedAt = now
+
+	r.settings[settings.UserID] = *settings
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/your-org/go-tic-tac-toe/pkg/outbox"
+	"gorm.io/gorm"
+)
+
+// ConsumeMessage runs handle unless the idempotency key of the message is already recorded,
+// with a repository writing in the transaction that records the key.
+// Concurrent deliveries of a message wait for the first one, which records the key.
+func (r *PostgresUserRepository) ConsumeMessage(envelope outbox.Envelope, handle func(repo ConsumerRepository) error) error {
+	return outbox.Consume(r.db, envelope, func(tx *gorm.DB, _ outbox.Envelope) error {
+		return handle(NewPostgresUserRepository(tx))
+	})
+}
+
+// ConsumeMessage runs handle unless the idempotency key of the message is already recorded
+func (r *MemoryUserRepository) ConsumeMessage(envelope outbox.Envelope, handle func(repo ConsumerRepository) error) error {
+	r.consumeMu.Lock()
+	defer r.consumeMu.Unlock()
+
+	r.mu.RLock()
+	consumed := r.consumed[envelope.IdempotencyKey]
+	r.mu.RUnlock()
+	if consumed {
+		return nil
+	}
+
+	if err := handle(r); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.consumed[envelope.IdempotencyKey] = true
+	r.mu.Unlock()
+	return nil
+}
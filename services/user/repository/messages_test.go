@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/outbox"
+)
+
+func TestMemoryConsumeMessageHandlesEachKeyOnce(t *testing.T) {
+	repo := NewMemoryUserRepository()
+	envelope := outbox.Envelope{IdempotencyKey: "key-1"}
+
+	failed := errors.New("failed")
+	if err := repo.ConsumeMessage(envelope, func(ConsumerRepository) error { return failed }); !errors.Is(err, failed) {
+		t.Fatalf("failing handler: %v", err)
+	}
+
+	calls := 0
+	handle := func(ConsumerRepository) error {
+		calls++
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		if err := repo.ConsumeMessage(envelope, handle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("handled %d times after a failed attempt, want once", calls)
+	}
+
+	if err := repo.ConsumeMessage(outbox.Envelope{IdempotencyKey: "key-2"}, handle); err != nil || calls != 2 {
+		t.Fatalf("other message handled %d times with %v", calls-1, err)
+	}
+}
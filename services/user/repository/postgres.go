@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresUserRepository stores users and everything the user service keeps about them in Postgres
+type PostgresUserRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresUserRepository creates a Postgres-backed repository
+func NewPostgresUserRepository(db *gorm.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+// Create stores the profile of a user registered by the auth service, keeping their ID
+func (r *PostgresUserRepository) Create(user *models.User) error {
+	var taken int64
+	err := r.db.Model(&models.User{}).Where("username = ?", user.Username).Count(&taken).Error
+	if err != nil {
+		return fmt.Errorf("failed to check username: %w", err)
+	}
+	if taken > 0 {
+		return ErrUsernameTaken
+	}
+
+	if err := r.db.Create(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrUsernameTaken
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns the user with the given ID
+func (r *PostgresUserRepository) FindByID(id uint) (*models.User, error) {
+	return r.find(r.db.Where("id = ?", id))
+}
+
+// userBatchSize is the number of users loaded per query, far below the parameter limit of Postgres
+const userBatchSize = 1000
+
+// FindByIDs returns the users with the given IDs, leaving out unknown ones
+func (r *PostgresUserRepository) FindByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+	for start := 0; start < len(ids); start += userBatchSize {
+		end := min(start+userBatchSize, len(ids))
+		var batch []models.User
+		if err := r.db.Where("id IN ?", ids[start:end]).Find(&batch).Error; err != nil {
+			return nil, fmt.Errorf("failed to load users: %w", err)
+		}
+		users = append(users, batch...)
+	}
+	return users, nil
+}
+
+// FindByUsername returns the user with the given username
+func (r *PostgresUserRepository) FindByUsername(username string) (*models.User, error) {
+	return r.find(r.db.Where("username = ?", username))
+}
+
+// find loads the first user matching query
+func (r *PostgresUserRepository) find(query *gorm.DB) (*models.User, error) {
+	var user models.User
+	err := query.First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	return &user, nil
+}
+
+// Update saves the profile fields of an existing user
+func (r *PostgresUserRepository) Update(user *models.User) error {
+	var taken int64
+	err := r.db.Model(&models.User{}).
+		Where("username = ? AND id <> ?", user.Username, user.ID).
+		Count(&taken).Error
+	if err != nil {
+		return fmt.Errorf("failed to check username: %w", err)
+	}
+	if taken > 0 {
+		return ErrUsernameTaken
+	}
+
+	result := r.db.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"username":   user.Username,
+		"first_name": user.FirstName,
+		"last_name":  user.LastName,
+		"avatar_key": user.AvatarKey,
+	})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrUsernameTaken
+		}
+		return fmt.Errorf("failed to save user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// FindSettings returns the settings of the user, or the defaults if none were saved
+func (r *PostgresUserRepository) FindSettings(userID uint) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	err := r.db.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return DefaultSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveSettings upserts the settings of a user.
+// Settings that were never saved have no ID and may race with another first save.
+func (r *PostgresUserRepository) SaveSettings(settings *models.UserSettings) error {
+	if settings.ID != 0 {
+		if err := r.db.Save(settings).Error; err != nil {
+			return fmt.Errorf("failed to save settings: %w", err)
+		}
+		return nil
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at", "default_board_size", "default_win_length", "theme",
+			"notify_game_invites", "notify_your_turn", "notify_chat", "notify_email",
+		}),
+	}).Create(settings).Error
+	if err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	return nil
+}
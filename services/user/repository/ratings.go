@@ -0,0 +1,367 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// leaderboardBatchSize is the number of leaderboard entries inserted per statement
+const leaderboardBatchSize = 500
+
+// FindRating returns the rating of a user
+func (r *PostgresUserRepository) FindRating(userID uint) (*models.Rating, error) {
+	var rating models.Rating
+	err := r.db.Where("user_id = ?", userID).First(&rating).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRatingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rating: %w", err)
+	}
+	return &rating, nil
+}
+
+// FindRatings returns the ratings with at least minGames games, best rating first
+func (r *PostgresUserRepository) FindRatings(minGames int) ([]models.Rating, error) {
+	var ratings []models.Rating
+	err := r.db.Where("games >= ?", minGames).Order("rating DESC, deviation, user_id").Find(&ratings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+// RateGame applies rate to the ratings of two players once per game, with both rows locked
+func (r *PostgresUserRepository) RateGame(gameID string, firstID, secondID uint, rate func(first, second *models.Rating)) (bool, error) {
+	rated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RatedGame{GameID: gameID, RatedAt: time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// lock in ID order so that games of the same players cannot deadlock
+		ratings := map[uint]*models.Rating{firstID: {UserID: firstID}, secondID: {UserID: secondID}}
+		ids := []uint{firstID, secondID}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", id).First(ratings[id]).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		rate(ratings[firstID], ratings[secondID])
+		for _, id := range ids {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(ratings[id]).Error; err != nil {
+				return err
+			}
+		}
+		rated = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to rate game: %w", err)
+	}
+	return rated, nil
+}
+
+// DecayRatings applies decay to the ratings last brought up to date before the given time
+func (r *PostgresUserRepository) DecayRatings(before time.Time, decay func(*models.Rating) bool) (int, error) {
+	changed := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		changed = 0
+		var ratings []models.Rating
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("rated_at < ?", before).Find(&ratings).Error
+		if err != nil {
+			return err
+		}
+		for i := range ratings {
+			if !decay(&ratings[i]) {
+				continue
+			}
+			if err := tx.Save(&ratings[i]).Error; err != nil {
+				return err
+			}
+			changed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to decay ratings: %w", err)
+	}
+	return changed, nil
+}
+
+// CreateSeason stores a new season
+func (r *PostgresUserRepository) CreateSeason(season *models.Season) error {
+	if err := r.db.Create(season).Error; err != nil {
+		return fmt.Errorf("failed to create season: %w", err)
+	}
+	return nil
+}
+
+// FindSeason returns the season with the given ID
+func (r *PostgresUserRepository) FindSeason(id uint) (*models.Season, error) {
+	var season models.Season
+	err := r.db.Where("id = ?", id).First(&season).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSeasonNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load season: %w", err)
+	}
+	return &season, nil
+}
+
+// FindSeasons returns all seasons in the order they start
+func (r *PostgresUserRepository) FindSeasons() ([]models.Season, error) {
+	var seasons []models.Season
+	if err := r.db.Order("starts_at, id").Find(&seasons).Error; err != nil {
+		return nil, fmt.Errorf("failed to load seasons: %w", err)
+	}
+	return seasons, nil
+}
+
+// StartSeason marks a scheduled season active and resets every rating in one transaction
+func (r *PostgresUserRepository) StartSeason(season *models.Season, reset func(*models.Rating)) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		changes := map[string]interface{}{"status": season.Status, "started_at": season.StartedAt}
+		if err := transition(tx, season.ID, SeasonScheduled, changes); err != nil {
+			return err
+		}
+
+		var ratings []models.Rating
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&ratings).Error; err != nil {
+			return err
+		}
+		for i := range ratings {
+			reset(&ratings[i])
+			if err := tx.Save(&ratings[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrSeasonChanged) {
+		return fmt.Errorf("failed to start season: %w", err)
+	}
+	return err
+}
+
+// CloseSeason marks an active season closed and archives its leaderboard in one transaction
+func (r *PostgresUserRepository) CloseSeason(season *models.Season, leaderboard []models.LeaderboardEntry) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		changes := map[string]interface{}{"status": season.Status, "closed_at": season.ClosedAt}
+		if err := transition(tx, season.ID, SeasonActive, changes); err != nil {
+			return err
+		}
+		if len(leaderboard) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(leaderboard, leaderboardBatchSize).Error
+	})
+	if err != nil && !errors.Is(err, ErrSeasonChanged) {
+		return fmt.Errorf("failed to close season: %w", err)
+	}
+	return err
+}
+
+// FindLeaderboard returns the archived leaderboard of a closed season by rank
+func (r *PostgresUserRepository) FindLeaderboard(seasonID uint) ([]models.LeaderboardEntry, error) {
+	var entries []models.LeaderboardEntry
+	if err := r.db.Where("season_id = ?", seasonID).Order("rank").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load leaderboard: %w", err)
+	}
+	return entries, nil
+}
+
+// transition applies changes to a season that is still in status, ErrSeasonChanged otherwise
+func transition(tx *gorm.DB, id uint, status string, changes map[string]interface{}) error {
+	result := tx.Model(&models.Season{}).Where("id = ? AND status = ?", id, status).Updates(changes)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSeasonChanged
+	}
+	return nil
+}
+
+// FindRating returns the rating of a user
+func (r *MemoryUserRepository) FindRating(userID uint) (*models.Rating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rating, ok := r.ratings[userID]
+	if !ok {
+		return nil, ErrRatingNotFound
+	}
+	return &rating, nil
+}
+
+// FindRatings returns the ratings with at least minGames games, best rating first
+func (r *MemoryUserRepository) FindRatings(minGames int) ([]models.Rating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ratings []models.Rating
+	for _, rating := range r.ratings {
+		if rating.Games >= minGames {
+			ratings = append(ratings, rating)
+		}
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		a, b := ratings[i], ratings[j]
+		if a.Rating != b.Rating {
+			return a.Rating > b.Rating
+		}
+		if a.Deviation != b.Deviation {
+			return a.Deviation < b.Deviation
+		}
+		return a.UserID < b.UserID
+	})
+	return ratings, nil
+}
+
+// RateGame applies rate to the ratings of two players once per game
+func (r *MemoryUserRepository) RateGame(gameID string, firstID, secondID uint, rate func(first, second *models.Rating)) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ratedGames[gameID] {
+		return false, nil
+	}
+
+	first, second := r.rating(firstID), r.rating(secondID)
+	rate(&first, &second)
+
+	now := time.Now()
+	first.UpdatedAt, second.UpdatedAt = now, now
+	r.ratings[firstID] = first
+	r.ratings[secondID] = second
+	r.ratedGames[gameID] = true
+	return true, nil
+}
+
+// DecayRatings applies decay to the ratings last brought up to date before the given time
+func (r *MemoryUserRepository) DecayRatings(before time.Time, decay func(*models.Rating) bool) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := 0
+	for userID, rating := range r.ratings {
+		if !rating.RatedAt.Before(before) || !decay(&rating) {
+			continue
+		}
+		rating.UpdatedAt = time.Now()
+		r.ratings[userID] = rating
+		changed++
+	}
+	return changed, nil
+}
+
+// CreateSeason stores a new season, assigning its ID
+func (r *MemoryUserRepository) CreateSeason(season *models.Season) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextSeasonID++
+	season.ID = r.nextSeasonID
+	season.CreatedAt = time.Now()
+	r.seasons = append(r.seasons, *season)
+	return nil
+}
+
+// FindSeason returns the season with the given ID
+func (r *MemoryUserRepository) FindSeason(id uint) (*models.Season, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, season := range r.seasons {
+		if season.ID == id {
+			return &season, nil
+		}
+	}
+	return nil, ErrSeasonNotFound
+}
+
+// FindSeasons returns all seasons in the order they start
+func (r *MemoryUserRepository) FindSeasons() ([]models.Season, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seasons := make([]models.Season, len(r.seasons))
+	copy(seasons, r.seasons)
+	sort.SliceStable(seasons, func(i, j int) bool {
+		return seasons[i].StartsAt.Before(seasons[j].StartsAt)
+	})
+	return seasons, nil
+}
+
+// StartSeason marks a scheduled season active and resets every rating
+func (r *MemoryUserRepository) StartSeason(season *models.Season, reset func(*models.Rating)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.transition(season, SeasonScheduled); err != nil {
+		return err
+	}
+	for userID, rating := range r.ratings {
+		reset(&rating)
+		r.ratings[userID] = rating
+	}
+	return nil
+}
+
+// CloseSeason marks an active season closed and archives its leaderboard
+func (r *MemoryUserRepository) CloseSeason(season *models.Season, leaderboard []models.LeaderboardEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.transition(season, SeasonActive); err != nil {
+		return err
+	}
+	r.archive[season.ID] = append([]models.LeaderboardEntry(nil), leaderboard...)
+	return nil
+}
+
+// FindLeaderboard returns the archived leaderboard of a closed season by rank
+func (r *MemoryUserRepository) FindLeaderboard(seasonID uint) ([]models.LeaderboardEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]models.LeaderboardEntry(nil), r.archive[seasonID]...), nil
+}
+
+// rating returns the stored rating of a user, or one with only the user ID set
+func (r *MemoryUserRepository) rating(userID uint) models.Rating {
+	if rating, ok := r.ratings[userID]; ok {
+		return rating
+	}
+	return models.Rating{UserID: userID}
+}
+
+// transition replaces the stored season if it is still in status
+func (r *MemoryUserRepository) transition(season *models.Season, status string) error {
+	for i := range r.seasons {
+		if r.seasons[i].ID != season.ID {
+			continue
+		}
+		if r.seasons[i].Status != status {
+			return ErrSeasonChanged
+		}
+		r.seasons[i] = *season
+		return nil
+	}
+	return ErrSeasonNotFound
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+)
+
+// CreateReport stores a new report
+func (r *PostgresUserRepository) CreateReport(report *models.Report) error {
+	if err := r.db.Create(report).Error; err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	return nil
+}
+
+// FindReports returns limit reports from offset, newest first
+func (r *PostgresUserRepository) FindReports(offset, limit int) ([]models.Report, error) {
+	var reports []models.Report
+	err := r.db.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reports: %w", err)
+	}
+	return reports, nil
+}
+
+// CreateReport stores a new report, assigning its ID
+func (r *MemoryUserRepository) CreateReport(report *models.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextReportID++
+	report.ID = r.nextReportID
+	report.CreatedAt = time.Now()
+	r.reports = append(r.reports, *report)
+	return nil
+}
+
+// FindReports returns limit reports from offset, newest first
+func (r *MemoryUserRepository) FindReports(offset, limit int) ([]models.Report, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reports := make([]models.Report, 0, limit)
+	for i := len(r.reports) - 1 - offset; i >= 0 && len(reports) < limit; i-- {
+		reports = append(reports, r.reports[i])
+	}
+	return reports, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"github.com/your-org/go-tic-tac-toe/pkg/outbox"
+)
+
+var (
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUsernameTaken is returned when another user already has the username
+	ErrUsernameTaken = errors.New("username is already taken")
+
+	// ErrFriendshipNotFound is returned when there is no friendship or request between users
+	ErrFriendshipNotFound = errors.New("friendship not found")
+
+	// ErrFriendshipExists is returned when the users are friends or a request is pending
+	ErrFriendshipExists = errors.New("friendship already exists")
+
+	// ErrRatingNotFound is returned when a user never played a rated game
+	ErrRatingNotFound = errors.New("rating not found")
+
+	// ErrSeasonNotFound is returned when a season does not exist
+	ErrSeasonNotFound = errors.New("season not found")
+
+	// ErrSeasonChanged is returned when a season was started or closed in the meantime
+	ErrSeasonChanged = errors.New("season was changed concurrently")
+)
+
+// Friendship statuses
+const (
+	FriendshipPending  = "pending"
+	FriendshipAccepted = "accepted"
+)
+
+// Season statuses
+const (
+	SeasonScheduled = "scheduled"
+	SeasonActive    = "active"
+	SeasonClosed    = "closed"
+)
+
+// Default settings of users who never saved their own
+const (
+	DefaultBoardSize = 3
+	DefaultWinLength = 3
+	DefaultTheme     = "system"
+)
+
+// UserRepository stores user profiles and settings
+type UserRepository interface {
+	// Create stores the profile of a user registered by the auth service, keeping their ID
+	Create(user *models.User) error
+	FindByID(id uint) (*models.User, error)
+	// FindByIDs returns the users with the given IDs in one query, leaving out unknown ones
+	FindByIDs(ids []uint) ([]models.User, error)
+	FindByUsername(username string) (*models.User, error)
+	Update(user *models.User) error
+
+	// FindSettings returns the settings of the user, or the defaults if none were saved
+	FindSettings(userID uint) (*models.UserSettings, error)
+	SaveSettings(settings *models.UserSettings) error
+}
+
+// MessageRepository remembers the outbox messages consumed from other services
+type MessageRepository interface {
+	// ConsumeMessage runs handle unless the message was consumed before. handle gets repositories
+	// writing in the transaction that records the message, so the message only counts as consumed
+	// together with its changes and a failed one is handled again when redelivered.
+	ConsumeMessage(envelope outbox.Envelope, handle func(repo ConsumerRepository) error) error
+}
+
+// ConsumerRepository is the part of the repository consumed messages are handled with
+type ConsumerRepository interface {
+	StatsRepository
+	AchievementRepository
+	RatingRepository
+}
+
+// StatsRepository stores the game results statistics are computed from
+type StatsRepository interface {
+	// RecordResults stores results, ignoring those of games already recorded for the user
+	RecordResults(results []models.GameResult) error
+	// FindResults returns the results of a user in the order the games finished
+	FindResults(userID uint) ([]models.GameResult, error)
+	// UpsertResults stores results, overwriting those of games already recorded for the user
+	UpsertResults(results []models.GameResult) error
+}
+
+// SocialRepository stores friendships and blocks between users
+type SocialRepository interface {
+	// FindFriendship returns the friendship or request between two users in either direction
+	FindFriendship(userID, otherID uint) (*models.Friendship, error)
+	FindFriendshipByID(id uint) (*models.Friendship, error)
+	// FindFriendships returns the friendships of the user with the given status in creation order
+	FindFriendships(userID uint, status string) ([]models.Friendship, error)
+	CreateFriendship(friendship *models.Friendship) error
+	SaveFriendship(friendship *models.Friendship) error
+	DeleteFriendship(id uint) error
+
+	// Block records a block and ends any friendship or request between the users
+	Block(blockerID, blockedID uint) error
+	Unblock(blockerID, blockedID uint) error
+	// FindBlocks returns the blocks made by the user in creation order
+	FindBlocks(blockerID uint) ([]models.Block, error)
+	// BlockedUserIDs returns the users the user blocked or who blocked the user
+	BlockedUserIDs(userID uint) ([]uint, error)
+}
+
+// AchievementRepository stores earned badges and the activity they are unlocked by
+type AchievementRepository interface {
+	// FindAchievements returns the badges of a user in the order they were unlocked
+	FindAchievements(userID uint) ([]models.Achievement, error)
+	// UnlockAchievements stores badges and returns those the users did not have before
+	UnlockAchievements(achievements []models.Achievement) ([]models.Achievement, error)
+	// RecordChatMessage counts a chat message of the user and returns their total. It is not idempotent,
+	// so redelivered chat events must be dropped with MessageRepository first.
+	RecordChatMessage(userID uint) (int, error)
+}
+
+// RatingRepository stores the ratings of players and the games applied to them
+type RatingRepository interface {
+	FindRating(userID uint) (*models.Rating, error)
+	// FindRatings returns the ratings with at least minGames games, best rating first
+	FindRatings(minGames int) ([]models.Rating, error)
+	// RateGame applies rate to the ratings of two players once per game and reports whether
+	// it did. Players without a rating are passed with only their user ID set.
+	RateGame(gameID string, firstID, secondID uint, rate func(first, second *models.Rating)) (bool, error)
+	// DecayRatings applies decay to the ratings last brought up to date before the given time
+	// and returns how many it changed
+	DecayRatings(before time.Time, decay func(*models.Rating) bool) (int, error)
+}
+
+// SeasonRepository stores seasons and the archived leaderboards of closed ones
+type SeasonRepository interface {
+	CreateSeason(season *models.Season) error
+	FindSeason(id uint) (*models.Season, error)
+	// FindSeasons returns all seasons in the order they start
+	FindSeasons() ([]models.Season, error)
+	// StartSeason marks a scheduled season active and applies reset to every rating,
+	// ErrSeasonChanged if it is no longer scheduled
+	StartSeason(season *models.Season, reset func(*models.Rating)) error
+	// CloseSeason marks an active season closed and archives its leaderboard,
+	// ErrSeasonChanged if it is no longer active
+	CloseSeason(season *models.Season, leaderboard []models.LeaderboardEntry) error
+	// FindLeaderboard returns the archived leaderboard of a closed season by rank
+	FindLeaderboard(seasonID uint) ([]models.LeaderboardEntry, error)
+}
+
+// ReportRepository stores reports of users about other users
+type ReportRepository interface {
+	CreateReport(report *models.Report) error
+	// FindReports returns limit reports from offset, newest first
+	FindReports(offset, limit int) ([]models.Report, error)
+}
+
+// DefaultSettings returns the settings of a user who never changed them
+func DefaultSettings(userID uint) *models.UserSettings {
+	return &models.UserSettings{
+		UserID:            userID,
+		DefaultBoardSize:  DefaultBoardSize,
+		DefaultWinLength:  DefaultWinLength,
+		Theme:             DefaultTheme,
+		NotifyGameInvites: true,
+		NotifyYourTurn:    true,
+		NotifyChat:        true,
+	}
+}
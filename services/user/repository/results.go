@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm/clause"
+)
+
+// resultBatchSize is the number of results inserted per statement
+const resultBatchSize = 500
+
+// RecordResults stores results, ignoring those of games already recorded for the user
+func (r *PostgresUserRepository) RecordResults(results []models.GameResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(results, resultBatchSize).Error
+	if err != nil {
+		return fmt.Errorf("failed to record results: %w", err)
+	}
+	return nil
+}
+
+// FindResults returns the results of a user in the order the games finished
+func (r *PostgresUserRepository) FindResults(userID uint) ([]models.GameResult, error) {
+	var results []models.GameResult
+	err := r.db.Where("user_id = ?", userID).Order("finished_at, id").Find(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load results: %w", err)
+	}
+	return results, nil
+}
+
+// UpsertResults stores results, overwriting those of games already recorded for the user
+func (r *PostgresUserRepository) UpsertResults(results []models.GameResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "game_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"variant", "board_size", "win_length", "symbol", "result", "moved_first", "moves", "duration", "finished_at",
+		}),
+	}).CreateInBatches(results, resultBatchSize).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert results: %w", err)
+	}
+	return nil
+}
+
+// resultKey identifies the result of a user in a game
+type resultKey struct {
+	userID uint
+	gameID string
+}
+
+// RecordResults stores results, ignoring those of games already recorded for the user
+func (r *MemoryUserRepository) RecordResults(results []models.GameResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recordResults(results)
+	return nil
+}
+
+// FindResults returns the results of a user in the order the games finished
+func (r *MemoryUserRepository) FindResults(userID uint) ([]models.GameResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var results []models.GameResult
+	for key, result := range r.results {
+		if key.userID == userID {
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].FinishedAt.Equal(results[j].FinishedAt) {
+			return results[i].FinishedAt.Before(results[j].FinishedAt)
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+// UpsertResults stores results, overwriting those of games already recorded for the user
+func (r *MemoryUserRepository) UpsertResults(results []models.GameResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, result := range results {
+		key := resultKey{userID: result.UserID, gameID: result.GameID}
+		if stored, ok := r.results[key]; ok {
+			result.ID = stored.ID
+			r.results[key] = result
+			continue
+		}
+		r.recordResults([]models.GameResult{result})
+	}
+	return nil
+}
+
+// recordResults adds results that are not stored yet, assigning their IDs
+func (r *MemoryUserRepository) recordResults(results []models.GameResult) {
+	for _, result := range results {
+		key := resultKey{userID: result.UserID, gameID: result.GameID}
+		if _, ok := r.results[key]; ok {
+			continue
+		}
+		r.nextResultID++
+		result.ID = r.nextResultID
+		r.results[key] = result
+	}
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FindFriendship returns the friendship or request between two users in either direction
+func (r *PostgresUserRepository) FindFriendship(userID, otherID uint) (*models.Friendship, error) {
+	return r.findFriendship(r.db.Where(
+		"(requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?)",
+		userID, otherID, otherID, userID,
+	))
+}
+
+// FindFriendshipByID returns the friendship or request with the given ID
+func (r *PostgresUserRepository) FindFriendshipByID(id uint) (*models.Friendship, error) {
+	return r.findFriendship(r.db.Where("id = ?", id))
+}
+
+// findFriendship loads the first friendship matching query
+func (r *PostgresUserRepository) findFriendship(query *gorm.DB) (*models.Friendship, error) {
+	var friendship models.Friendship
+	err := query.First(&friendship).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrFriendshipNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load friendship: %w", err)
+	}
+	return &friendship, nil
+}
+
+// FindFriendships returns the friendships of the user with the given status in creation order
+func (r *PostgresUserRepository) FindFriendships(userID uint, status string) ([]models.Friendship, error) {
+	var friendships []models.Friendship
+	err := r.db.Where("(requester_id = ? OR addressee_id = ?) AND status = ?", userID, userID, status).
+		Order("created_at, id").
+		Find(&friendships).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load friendships: %w", err)
+	}
+	return friendships, nil
+}
+
+// CreateFriendship stores a new friend request
+func (r *PostgresUserRepository) CreateFriendship(friendship *models.Friendship) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(friendship)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save friendship: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrFriendshipExists
+	}
+	return nil
+}
+
+// SaveFriendship updates an existing friendship
+func (r *PostgresUserRepository) SaveFriendship(friendship *models.Friendship) error {
+	if err := r.db.Save(friendship).Error; err != nil {
+		return fmt.Errorf("failed to save friendship: %w", err)
+	}
+	return nil
+}
+
+// DeleteFriendship removes a friendship or request
+func (r *PostgresUserRepository) DeleteFriendship(id uint) error {
+	result := r.db.Delete(&models.Friendship{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete friendship: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrFriendshipNotFound
+	}
+	return nil
+}
+
+// Block records a block and ends any friendship or request between the users
+func (r *PostgresUserRepository) Block(blockerID, blockedID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.Block{BlockerID: blockerID, BlockedID: blockedID}).Error
+		if err != nil {
+			return fmt.Errorf("failed to save block: %w", err)
+		}
+
+		err = tx.Where(
+			"(requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?)",
+			blockerID, blockedID, blockedID, blockerID,
+		).Delete(&models.Friendship{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete friendship: %w", err)
+		}
+		return nil
+	})
+}
+
+// Unblock removes a block, ignoring blocks that do not exist
+func (r *PostgresUserRepository) Unblock(blockerID, blockedID uint) error {
+	err := r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.Block{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete block: %w", err)
+	}
+	return nil
+}
+
+// FindBlocks returns the blocks made by the user in creation order
+func (r *PostgresUserRepository) FindBlocks(blockerID uint) ([]models.Block, error) {
+	var blocks []models.Block
+	if err := r.db.Where("blocker_id = ?", blockerID).Order("created_at, id").Find(&blocks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+// BlockedUserIDs returns the users the user blocked or who blocked the user
+func (r *PostgresUserRepository) BlockedUserIDs(userID uint) ([]uint, error) {
+	var blocks []models.Block
+	if err := r.db.Where("blocker_id = ? OR blocked_id = ?", userID, userID).Find(&blocks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load blocks: %w", err)
+	}
+	return otherParties(userID, blocks), nil
+}
+
+// otherParties returns the users on the other side of the blocks, without duplicates
+func otherParties(userID uint, blocks []models.Block) []uint {
+	seen := make(map[uint]bool, len(blocks))
+	ids := make([]uint, 0, len(blocks))
+	for _, block := range blocks {
+		other := block.BlockedID
+		if other == userID {
+			other = block.BlockerID
+		}
+		if !seen[other] {
+			seen[other] = true
+			ids = append(ids, other)
+		}
+	}
+	return ids
+}
+
+// FindFriendship returns the friendship or request between two users in either direction
+func (r *MemoryUserRepository) FindFriendship(userID, otherID uint) (*models.Friendship, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if friendship := r.friendshipBetween(userID, otherID); friendship != nil {
+		copied := *friendship
+		return &copied, nil
+	}
+	return nil, ErrFriendshipNotFound
+}
+
+// FindFriendshipByID returns the friendship or request with the given ID
+func (r *MemoryUserRepository) FindFriendshipByID(id uint) (*models.Friendship, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	friendship, ok := r.friendships[id]
+	if !ok {
+		return nil, ErrFriendshipNotFound
+	}
+	return &friendship, nil
+}
+
+// FindFriendships returns the friendships of the user with the given status in creation order
+func (r *MemoryUserRepository) FindFriendships(userID uint, status string) ([]models.Friendship, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var friendships []models.Friendship
+	for _, friendship := range r.friendships {
+		if friendship.Status != status {
+			continue
+		}
+		if friendship.RequesterID == userID || friendship.AddresseeID == userID {
+			friendships = append(friendships, friendship)
+		}
+	}
+	sort.Slice(friendships, func(i, j int) bool { return friendships[i].ID < friendships[j].ID })
+	return friendships, nil
+}
+
+// CreateFriendship stores a new friend request
+func (r *MemoryUserRepository) CreateFriendship(friendship *models.Friendship) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.friendships {
+		if stored.RequesterID == friendship.RequesterID && stored.AddresseeID == friendship.AddresseeID {
+			return ErrFriendshipExists
+		}
+	}
+
+	r.nextFriendshipID++
+	friendship.ID = r.nextFriendshipID
+	friendship.CreatedAt = time.Now()
+	friendship.UpdatedAt = friendship.CreatedAt
+	r.friendships[friendship.ID] = *friendship
+	return nil
+}
+
+// SaveFriendship updates an existing friendship
+func (r *MemoryUserRepository) SaveFriendship(friendship *models.Friendship) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.friendships[friendship.ID]; !ok {
+		return ErrFriendshipNotFound
+	}
+	friendship.UpdatedAt = time.Now()
+	r.friendships[friendship.ID] = *friendship
+	return nil
+}
+
+// DeleteFriendship removes a friendship or request
+func (r *MemoryUserRepository) DeleteFriendship(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.friendships[id]; !ok {
+		return ErrFriendshipNotFound
+	}
+	delete(r.friendships, id)
+	return nil
+}
+
+// friendshipBetween returns the stored friendship between two users in either direction
+func (r *MemoryUserRepository) friendshipBetween(userID, otherID uint) *models.Friendship {
+	for id, friendship := range r.friendships {
+		if (friendship.RequesterID == userID && friendship.AddresseeID == otherID) ||
+			(friendship.RequesterID == otherID && friendship.AddresseeID == userID) {
+			found := r.friendships[id]
+			return &found
+		}
+	}
+	return nil
+}
+
+// Block records a block and ends any friendship or request between the users
+func (r *MemoryUserRepository) Block(blockerID, blockedID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if friendship := r.friendshipBetween(blockerID, blockedID); friendship != nil {
+		delete(r.friendships, friendship.ID)
+	}
+	for _, block := range r.blocks {
+		if block.BlockerID == blockerID && block.BlockedID == blockedID {
+			return nil
+		}
+	}
+
+	r.blocks = append(r.blocks, models.Block{
+		ID:        uint(len(r.blocks) + 1),
+		CreatedAt: time.Now(),
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+	})
+	return nil
+}
+
+// Unblock removes a block, ignoring blocks that do not exist
+func (r *MemoryUserRepository) Unblock(blockerID, blockedID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, block := range r.blocks {
+		if block.BlockerID == blockerID && block.BlockedID == blockedID {
+			r.blocks = append(r.blocks[:i], r.blocks[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// FindBlocks returns the blocks made by the user in creation order
+func (r *MemoryUserRepository) FindBlocks(blockerID uint) ([]models.Block, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var blocks []models.Block
+	for _, block := range r.blocks {
+		if block.BlockerID == blockerID {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+// BlockedUserIDs returns the users the user blocked or who blocked the user
+func (r *MemoryUserRepository) BlockedUserIDs(userID uint) ([]uint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var blocks []models.Block
+	for _, block := range r.blocks {
+		if block.BlockerID == userID || block.BlockedID == userID {
+			blocks = append(blocks, block)
+		}
+	}
+	return otherParties(userID, blocks), nil
+}
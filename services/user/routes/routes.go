@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/your-org/go-tic-tac-toe/pkg/middleware"
+
+	"user-service/handlers"
+)
+
+// Setup registers user service routes
+func Setup(app *fiber.App, userHandler *handlers.UserHandler, statsHandler *handlers.StatsHandler, achievementHandler *handlers.AchievementHandler, socialHandler *handlers.SocialHandler, ratingHandler *handlers.RatingHandler, reportHandler *handlers.ReportHandler, jwtSecret, serviceSecret, subscriberSecret string) {
+	auth := middleware.Auth(jwtSecret)
+
+	app.Get("/profile", auth, userHandler.GetProfile)
+	app.Put("/profile", auth, userHandler.UpdateProfile)
+	app.Post("/profile/avatar", auth, userHandler.UploadAvatar)
+	app.Delete("/profile/avatar", auth, userHandler.DeleteAvatar)
+	app.Get("/settings", auth, userHandler.GetSettings)
+	app.Put("/settings", auth, userHandler.UpdateSettings)
+
+	friends := app.Group("/friends", auth)
+	friends.Get("/", socialHandler.ListFriends)
+	friends.Get("/requests", socialHandler.ListFriendRequests)
+	friends.Post("/requests", socialHandler.SendFriendRequest)
+	friends.Post("/requests/:id/accept", socialHandler.AcceptFriendRequest)
+	friends.Post("/requests/:id/decline", socialHandler.DeclineFriendRequest)
+	friends.Delete("/:id", socialHandler.RemoveFriend)
+	friends.Post("/:id/challenge", socialHandler.ChallengeFriend)
+
+	blocks := app.Group("/blocks", auth)
+	blocks.Get("/", socialHandler.ListBlocks)
+	blocks.Put("/:id", socialHandler.BlockUser)
+	blocks.Delete("/:id", socialHandler.UnblockUser)
+
+	app.Get("/users/:username", userHandler.GetPublicProfile)
+	app.Get("/users/:id/stats", statsHandler.GetStats)
+	app.Get("/users/:id/achievements", achievementHandler.GetAchievements)
+	app.Get("/users/:id/rating", ratingHandler.GetRating)
+	app.Post("/users/:id/report", auth, reportHandler.ReportUser)
+
+	app.Get("/leaderboard", ratingHandler.GetLeaderboard)
+	app.Get("/seasons", ratingHandler.ListSeasons)
+	app.Get("/seasons/current", ratingHandler.GetCurrentSeason)
+	app.Get("/seasons/:id", ratingHandler.GetSeason)
+	app.Get("/seasons/:id/leaderboard", ratingHandler.GetSeasonLeaderboard)
+
+	admin := app.Group("/admin", auth)
+	admin.Post("/seasons", middleware.RequirePermission(middleware.PermissionManageSeasons), ratingHandler.CreateSeason)
+	admin.Post("/seasons/:id/close", middleware.RequirePermission(middleware.PermissionManageSeasons), ratingHandler.CloseSeason)
+	admin.Get("/reports", middleware.RequirePermission(middleware.PermissionViewReports), reportHandler.ListReports)
+
+	// Service-to-service endpoints, authenticated with the shared service secret
+	internal := app.Group("/internal", middleware.ServiceAuth(serviceSecret))
+	internal.Post("/users", userHandler.CreateUser)
+	internal.Post("/events", statsHandler.ReceiveEvent)
+	internal.Get("/users/:id/blocks", socialHandler.BlockedUserIDs)
+
+	// Outbox webhooks of other services, authenticated with a signature keyed by the secret of this subscriber
+	app.Post("/webhooks/events", middleware.WebhookSignature(subscriberSecret), statsHandler.ReceiveEvent)
+}
@@ -0,0 +1,210 @@
+package seasons
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+
+	"user-service/rating"
+	"user-service/repository"
+)
+
+const (
+	defaultInterval    = time.Hour
+	defaultDecayPeriod = 7 * 24 * time.Hour
+)
+
+// MinLeaderboardGames is the number of rated games in a season a player needs to be ranked
+const MinLeaderboardGames = 5
+
+var (
+	// ErrNotActive is returned when closing a season that is not running
+	ErrNotActive = errors.New("season is not active")
+
+	// ErrOverlap is returned when a new season would start before an open one ends
+	ErrOverlap = errors.New("season overlaps another season")
+)
+
+// Store holds the ratings, the seasons and the users they are ranked by name
+type Store interface {
+	repository.UserRepository
+	repository.RatingRepository
+	repository.SeasonRepository
+}
+
+// Manager rolls seasons over: it starts scheduled seasons with a soft reset of all ratings,
+// closes ended ones with an archived leaderboard and lets the deviation of inactive players grow.
+// Run does so periodically, admins can also close a season early.
+type Manager struct {
+	store Store
+
+	// Interval is the time between two rollovers of Run
+	Interval time.Duration
+	// DecayPeriod is the rating period: deviations grow once per period without games
+	DecayPeriod time.Duration
+}
+
+// NewManager creates a manager with default settings
+func NewManager(store Store) *Manager {
+	return &Manager{
+		store:       store,
+		Interval:    defaultInterval,
+		DecayPeriod: defaultDecayPeriod,
+	}
+}
+
+// Run rolls seasons over until ctx is cancelled, once right away and then every Interval
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Rollover(time.Now()); err != nil {
+			log.Printf("Season rollover error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rollover closes the active season once it ended, starts the next scheduled season
+// once it is due and decays the ratings of players without games for a rating period
+func (m *Manager) Rollover(now time.Time) error {
+	seasons, err := m.store.FindSeasons()
+	if err != nil {
+		return err
+	}
+
+	// Seasons start in order and at most one is active, a season missed entirely
+	// is started and closed right away so that its leaderboard is still archived
+	for i := range seasons {
+		season := &seasons[i]
+		var err error
+		if season.Status == repository.SeasonScheduled && !season.StartsAt.After(now) {
+			err = m.Start(season, now)
+		}
+		if err == nil && season.Status == repository.SeasonActive && !season.EndsAt.After(now) {
+			err = m.Close(season, now)
+		}
+		if errors.Is(err, repository.ErrSeasonChanged) {
+			// another instance or an admin got there first, pick up their changes next time
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	changed, err := m.store.DecayRatings(now.Add(-m.DecayPeriod), func(r *models.Rating) bool {
+		return rating.Decay(r, now, m.DecayPeriod)
+	})
+	if err != nil {
+		return err
+	}
+	if changed > 0 {
+		log.Printf("Rating deviation of %d inactive players increased", changed)
+	}
+	return nil
+}
+
+// Start activates a scheduled season and soft-resets every rating
+func (m *Manager) Start(season *models.Season, now time.Time) error {
+	season.Status = repository.SeasonActive
+	season.StartedAt = &now
+	if err := m.store.StartSeason(season, func(r *models.Rating) { rating.SoftReset(r, now) }); err != nil {
+		return err
+	}
+	log.Printf("Season %d %q started", season.ID, season.Name)
+	return nil
+}
+
+// Close ends an active season and archives its leaderboard
+func (m *Manager) Close(season *models.Season, now time.Time) error {
+	if season.Status != repository.SeasonActive {
+		return ErrNotActive
+	}
+
+	standings, err := m.Leaderboard(0, 0)
+	if err != nil {
+		return err
+	}
+	for i := range standings {
+		standings[i].SeasonID = season.ID
+	}
+
+	season.Status = repository.SeasonClosed
+	season.ClosedAt = &now
+	if err := m.store.CloseSeason(season, standings); err != nil {
+		return err
+	}
+	log.Printf("Season %d %q closed with %d ranked players", season.ID, season.Name, len(standings))
+	return nil
+}
+
+// Schedule validates and stores a new season. Seasons run one after another,
+// so it may not start before every season that is not closed yet ends.
+func (m *Manager) Schedule(season *models.Season) error {
+	seasons, err := m.store.FindSeasons()
+	if err != nil {
+		return err
+	}
+	for _, other := range seasons {
+		if other.Status != repository.SeasonClosed && season.StartsAt.Before(other.EndsAt) {
+			return ErrOverlap
+		}
+	}
+
+	season.Status = repository.SeasonScheduled
+	return m.store.CreateSeason(season)
+}
+
+// Leaderboard ranks the players with enough games in the current season by rating and returns
+// limit entries from offset, all of them when limit is 0
+func (m *Manager) Leaderboard(offset, limit int) ([]models.LeaderboardEntry, error) {
+	ratings, err := m.store.FindRatings(MinLeaderboardGames)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > len(ratings) {
+		offset = len(ratings)
+	}
+	end := len(ratings)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	page := ratings[offset:end]
+	ids := make([]uint, 0, len(page))
+	for _, r := range page {
+		ids = append(ids, r.UserID)
+	}
+	users, err := m.store.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	usernames := make(map[uint]string, len(users))
+	for _, user := range users {
+		usernames[user.ID] = user.Username
+	}
+
+	entries := make([]models.LeaderboardEntry, 0, len(page))
+	for i, r := range page {
+		entries = append(entries, models.LeaderboardEntry{
+			Rank:      offset + i + 1,
+			UserID:    r.UserID,
+			Username:  usernames[r.UserID],
+			Rating:    r.Rating,
+			Deviation: r.Deviation,
+			Games:     r.Games,
+		})
+	}
+	return entries, nil
+}
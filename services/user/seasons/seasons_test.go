@@ -0,0 +1,54 @@
+package seasons
+
+import (
+	"testing"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+
+	"user-service/repository"
+)
+
+// batchOnlyStore fails the test when users are loaded one by one
+type batchOnlyStore struct {
+	*repository.MemoryUserRepository
+	t *testing.T
+}
+
+func (s batchOnlyStore) FindByID(id uint) (*models.User, error) {
+	s.t.Errorf("user %d loaded on its own", id)
+	return s.MemoryUserRepository.FindByID(id)
+}
+
+func TestLeaderboardLoadsUsersInOneBatch(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	for id, username := range map[uint]string{1: "alice", 2: "bob"} {
+		user := &models.User{Email: username + "@example.com", Username: username}
+		user.ID = id
+		if err := repo.Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// User 3 has a rating but no profile
+	rate := func(first, second *models.Rating) {
+		first.Games, second.Games = MinLeaderboardGames, MinLeaderboardGames
+		first.Rating, second.Rating = second.Rating+100, second.Rating-100
+	}
+	for gameID, players := range map[string][2]uint{"g1": {1, 2}, "g2": {3, 2}} {
+		if _, err := repo.RateGame(gameID, players[0], players[1], rate); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := NewManager(batchOnlyStore{repo, t}).Leaderboard(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usernames := make(map[uint]string)
+	for _, entry := range entries {
+		usernames[entry.UserID] = entry.Username
+	}
+	if len(entries) != 3 || usernames[1] != "alice" || usernames[2] != "bob" || usernames[3] != "" {
+		t.Fatalf("leaderboard %+v", entries)
+	}
+}
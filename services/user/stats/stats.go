@@ -0,0 +1,182 @@
+package stats
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/your-org/go-tic-tac-toe/pkg/models"
+)
+
+// GameFinishedEvent is the outbox event type of finished game summaries
+const GameFinishedEvent = "game.finished"
+
+// Results of a player in a finished game
+const (
+	ResultWin  = "win"
+	ResultLoss = "loss"
+	ResultDraw = "draw"
+)
+
+// Finish reasons of game summaries
+const (
+	// ReasonWinLine is the reason of games won by completing a line
+	ReasonWinLine = "win_line"
+	// ReasonEnded is the reason of games ended by a moderator, which count for nobody
+	ReasonEnded = "ended"
+)
+
+// AIHard is the difficulty of the strongest computer opponent
+const AIHard = "hard"
+
+// GameSummary - payload of a game.finished message of the game service
+type GameSummary struct {
+	GameID        string          `json:"game_id"`
+	Variant       string          `json:"variant"`
+	BoardSize     int             `json:"board_size"`
+	WinLength     int             `json:"win_length"`
+	AI            string          `json:"ai"` // difficulty of the computer opponent, empty in games between people
+	Players       []PlayerSummary `json:"players"`
+	WinnerID      string          `json:"winner_id"`
+	Reason        string          `json:"reason"`
+	FirstPlayerID string          `json:"first_player_id"`
+	Moves         int             `json:"moves"`
+	StartedAt     *time.Time      `json:"started_at"`
+	FinishedAt    *time.Time      `json:"finished_at"`
+}
+
+// PlayerSummary - a player of a finished game and how it ended for them
+type PlayerSummary struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Symbol   string `json:"symbol"`
+	Result   string `json:"result"`
+}
+
+// Record - games played and their outcomes
+type Record struct {
+	Played  int     `json:"played"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Draws   int     `json:"draws"`
+	WinRate float64 `json:"win_rate"` // share of games won, 0 without games
+}
+
+// Stats - aggregate statistics of a user
+type Stats struct {
+	UserID uint `json:"user_id"`
+	Record
+	BySymbol    map[string]*Record `json:"by_symbol"`
+	ByBoardSize map[int]*Record    `json:"by_board_size"`
+	// FirstMove covers the games in which the user made the first move
+	FirstMove Record `json:"first_move"`
+
+	AverageMoves     float64 `json:"average_moves"`
+	AverageDuration  int64   `json:"average_duration"` // milliseconds
+	CurrentWinStreak int     `json:"current_win_streak"`
+	LongestWinStreak int     `json:"longest_win_streak"`
+
+	LastPlayedAt *time.Time `json:"last_played_at,omitempty"`
+}
+
+// Results returns the results of the players of a finished game.
+// Players without a numeric user ID have no statistics and are skipped,
+// and games ended by a moderator have no results at all.
+func Results(summary GameSummary) []models.GameResult {
+	if summary.Reason == ReasonEnded {
+		return nil
+	}
+
+	var duration int64
+	var finishedAt time.Time
+	if summary.FinishedAt != nil {
+		finishedAt = *summary.FinishedAt
+		if summary.StartedAt != nil {
+			duration = summary.FinishedAt.Sub(*summary.StartedAt).Milliseconds()
+		}
+	}
+
+	results := make([]models.GameResult, 0, len(summary.Players))
+	for _, player := range summary.Players {
+		userID, err := strconv.ParseUint(player.ID, 10, 0)
+		if err != nil || userID == 0 {
+			continue
+		}
+		results = append(results, models.GameResult{
+			UserID:     uint(userID),
+			GameID:     summary.GameID,
+			Variant:    summary.Variant,
+			BoardSize:  summary.BoardSize,
+			WinLength:  summary.WinLength,
+			Symbol:     player.Symbol,
+			Result:     player.Result,
+			MovedFirst: player.ID == summary.FirstPlayerID,
+			Moves:      summary.Moves,
+			Duration:   duration,
+			FinishedAt: finishedAt,
+		})
+	}
+	return results
+}
+
+// Compute aggregates the results of a user, which must be in the order the games finished
+func Compute(userID uint, results []models.GameResult) *Stats {
+	stats := &Stats{
+		UserID:      userID,
+		BySymbol:    make(map[string]*Record),
+		ByBoardSize: make(map[int]*Record),
+	}
+
+	var moves, duration int64
+	for _, result := range results {
+		stats.add(result.Result)
+		record(stats.BySymbol, result.Symbol).add(result.Result)
+		record(stats.ByBoardSize, result.BoardSize).add(result.Result)
+		if result.MovedFirst {
+			stats.FirstMove.add(result.Result)
+		}
+
+		moves += int64(result.Moves)
+		duration += result.Duration
+
+		if result.Result == ResultWin {
+			stats.CurrentWinStreak++
+			if stats.CurrentWinStreak > stats.LongestWinStreak {
+				stats.LongestWinStreak = stats.CurrentWinStreak
+			}
+		} else {
+			stats.CurrentWinStreak = 0
+		}
+	}
+
+	if len(results) > 0 {
+		stats.AverageMoves = float64(moves) / float64(len(results))
+		stats.AverageDuration = duration / int64(len(results))
+		lastPlayedAt := results[len(results)-1].FinishedAt
+		stats.LastPlayedAt = &lastPlayedAt
+	}
+	return stats
+}
+
+// record returns the record of key, creating it on first use
+func record[K comparable](records map[K]*Record, key K) *Record {
+	r, ok := records[key]
+	if !ok {
+		r = &Record{}
+		records[key] = r
+	}
+	return r
+}
+
+// add counts a game with the given result
+func (r *Record) add(result string) {
+	r.Played++
+	switch result {
+	case ResultWin:
+		r.Wins++
+	case ResultLoss:
+		r.Losses++
+	case ResultDraw:
+		r.Draws++
+	}
+	r.WinRate = float64(r.Wins) / float64(r.Played)
+}
@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResults(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	finished := started.Add(90 * time.Second)
+	summary := GameSummary{
+		GameID:    "g1",
+		Variant:   "classic",
+		BoardSize: 3,
+		WinLength: 3,
+		Players: []PlayerSummary{
+			{ID: "1", Symbol: "X", Result: ResultWin},
+			{ID: "guest-7", Symbol: "O", Result: ResultLoss},
+		},
+		WinnerID:      "1",
+		Reason:        ReasonWinLine,
+		FirstPlayerID: "1",
+		Moves:         5,
+		StartedAt:     &started,
+		FinishedAt:    &finished,
+	}
+
+	results := Results(summary)
+	if len(results) != 1 {
+		t.Fatalf("%d results, want only the registered player", len(results))
+	}
+	result := results[0]
+	if result.UserID != 1 || result.GameID != "g1" || result.Result != ResultWin || !result.MovedFirst {
+		t.Errorf("result %+v", result)
+	}
+	if result.Duration != 90000 || !result.FinishedAt.Equal(finished) {
+		t.Errorf("duration %d finished at %v", result.Duration, result.FinishedAt)
+	}
+
+	summary.Reason = ReasonEnded
+	if results := Results(summary); len(results) != 0 {
+		t.Errorf("game ended by a moderator has results %+v", results)
+	}
+}